@@ -0,0 +1,217 @@
+// Package audit 只追加的审计日志，记录每一次密钥创建和签名。
+//
+// 文件每行一条 JSON 记录，Hash 为本条记录（Hash 字段为空）JSON 的 sha256，记录中包含上一条的 Hash，
+// 修改、删除、插入或调换任何一条记录都会使之后的校验失败。
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DecisionAllowed = "allowed"
+	DecisionDenied  = "denied"
+
+	// 第一条记录的 PrevHash
+	genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+	// 单条记录的最大长度
+	maxLineSize = 1 << 20
+)
+
+var ErrBrokenChain = errors.New("audit log hash chain is broken")
+
+// Entry 一条审计记录，Seq、Time、PrevHash 和 Hash 由 Append 填写
+type Entry struct {
+	Seq uint64 `json:"seq"`
+	// RFC3339Nano，UTC
+	Time      string `json:"time"`
+	Consumer  string `json:"consumer"`
+	Chain     string `json:"chain"`
+	PublicKey string `json:"public_key,omitempty"`
+	Method    string `json:"method"`
+	// 交易的目的地址、资产和金额摘要，原始消息签名为消息 hash
+	Intent   string `json:"intent,omitempty"`
+	Decision string `json:"decision"`
+	// 拒绝或失败的原因
+	Reason string `json:"reason,omitempty"`
+	// 签名结果的 sha256，不记录签名本身
+	SignatureHash string `json:"signature_hash,omitempty"`
+	PrevHash      string `json:"prev_hash"`
+	Hash          string `json:"hash"`
+}
+
+func (e *Entry) computeHash() (string, error) {
+	unsigned := *e
+	unsigned.Hash = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashSignatures 多个签名结果按顺序合并计算 hash，没有签名时返回空字符串
+func HashSignatures(signatures ...string) string {
+	if len(signatures) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(signatures, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+type Log struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	last string
+	now  func() time.Time
+}
+
+// Open 打开审计日志并校验已有的记录，新记录接在最后一条之后。校验失败时拒绝打开，需要先用 audit verify 排查
+func Open(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{file: file, last: genesisHash, now: time.Now}
+	err = scan(file, func(e *Entry, _ []byte) error {
+		l.seq, l.last = e.Seq, e.Hash
+		return nil
+	})
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("open audit log %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// Append 写入一条记录并落盘
+func (l *Log) Append(e *Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e.Seq = l.seq + 1
+	e.Time = l.now().UTC().Format(time.RFC3339Nano)
+	e.PrevHash = l.last
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+	l.seq, l.last = e.Seq, e.Hash
+	return nil
+}
+
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// scan 按顺序读取并校验每一条记录，fn 收到解码后的记录和原始行
+func scan(r io.Reader, fn func(e *Entry, line []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	prev, seq := genesisHash, uint64(0)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		seq++
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("%w: entry %d: %v", ErrBrokenChain, seq, err)
+		}
+		if e.Seq != seq {
+			return fmt.Errorf("%w: entry %d has sequence %d", ErrBrokenChain, seq, e.Seq)
+		}
+		if e.PrevHash != prev {
+			return fmt.Errorf("%w: entry %d does not follow the previous entry", ErrBrokenChain, seq)
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != e.Hash {
+			return fmt.Errorf("%w: entry %d was modified", ErrBrokenChain, seq)
+		}
+		if err := fn(&e, line); err != nil {
+			return err
+		}
+		prev = e.Hash
+	}
+	return scanner.Err()
+}
+
+// Verify 校验整个日志，返回记录数和最后一条记录的 hash
+func Verify(r io.Reader) (uint64, string, error) {
+	count, last := uint64(0), genesisHash
+	err := scan(r, func(e *Entry, _ []byte) error {
+		count, last = e.Seq, e.Hash
+		return nil
+	})
+	return count, last, err
+}
+
+// Range 导出的范围，零值表示不限制
+type Range struct {
+	FromSeq uint64
+	ToSeq   uint64
+	Since   time.Time
+	Until   time.Time
+}
+
+func (rg *Range) contains(e *Entry) (bool, error) {
+	if (rg.FromSeq != 0 && e.Seq < rg.FromSeq) || (rg.ToSeq != 0 && e.Seq > rg.ToSeq) {
+		return false, nil
+	}
+	if rg.Since.IsZero() && rg.Until.IsZero() {
+		return true, nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, e.Time)
+	if err != nil {
+		return false, fmt.Errorf("entry %d: %w", e.Seq, err)
+	}
+	return (rg.Since.IsZero() || !at.Before(rg.Since)) && (rg.Until.IsZero() || at.Before(rg.Until)), nil
+}
+
+// Export 把范围内的记录按原样写成 JSONL，导出前后的记录同样参与校验，链断开时返回错误
+func Export(r io.Reader, w io.Writer, rg Range) (int, error) {
+	exported := 0
+	err := scan(r, func(e *Entry, line []byte) error {
+		ok, err := rg.contains(e)
+		if err != nil || !ok {
+			return err
+		}
+		// line 指向读取缓冲区，不能 append
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+		exported++
+		return nil
+	})
+	return exported, err
+}
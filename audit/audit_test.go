@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	for i, method := range []string{"createKeyPairsWithAddresses", "buildAndSignTransaction", "signTransactionMessage"} {
+		now = now.Add(time.Hour)
+		e := &Entry{Consumer: "exchange", Chain: "Ethereum", PublicKey: "04ab", Method: method, Decision: DecisionAllowed}
+		if i == 2 {
+			e.Decision, e.Reason = DecisionDenied, "raw_message_not_allowed"
+		}
+		if err := l.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	// 重新打开后继续接在最后一条之后
+	if l, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Append(&Entry{Consumer: "exchange", Chain: "Solana", Method: "buildAndSignTransaction", Decision: DecisionAllowed, SignatureHash: HashSignatures("sig")}); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	data, _ := os.ReadFile(path)
+	if count, _, err := Verify(bytes.NewReader(data)); err != nil || count != 4 {
+		t.Fatalf("verify: count %d, err %v", count, err)
+	}
+	var out bytes.Buffer
+	n, err := Export(bytes.NewReader(data), &out, Range{FromSeq: 2, Until: time.Date(2025, 1, 1, 3, 30, 0, 0, time.UTC)})
+	if err != nil || n != 2 || strings.Count(out.String(), "\n") != 2 || !strings.Contains(out.String(), `"seq":2`) {
+		t.Fatalf("export %d entries, err %v:\n%s", n, err, out.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	tampered := map[string][]string{
+		"modified": {lines[0], strings.Replace(lines[1], `"decision":"allowed"`, `"decision":"denied"`, 1), lines[2], lines[3]},
+		"deleted":  {lines[0], lines[2], lines[3]},
+		"swapped":  {lines[0], lines[2], lines[1], lines[3]},
+	}
+	for name, tamperedLines := range tampered {
+		if _, _, err := Verify(strings.NewReader(strings.Join(tamperedLines, "\n"))); !errors.Is(err, ErrBrokenChain) {
+			t.Fatalf("%s: err = %v", name, err)
+		}
+	}
+	os.WriteFile(path, []byte(strings.Join(tampered["deleted"], "\n")), 0600)
+	if _, err := Open(path); !errors.Is(err, ErrBrokenChain) {
+		t.Fatalf("open tampered log: err = %v", err)
+	}
+}
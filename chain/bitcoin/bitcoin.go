@@ -2,13 +2,15 @@ package bitcoin
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"github.com/0xshin-chan/wallet-sign/chain"
 	"github.com/0xshin-chan/wallet-sign/config"
-	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
 	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
 	"github.com/0xshin-chan/wallet-sign/ssm"
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -17,21 +19,29 @@ import (
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/ethereum/go-ethereum/log"
+	"math/big"
 )
 
 const ChainName = "Bitcoin"
 
 type ChainAdaptor struct {
 	db        *leveldb.Keys
-	HsmClient *hsm.HsmClient
 	signer    ssm.Signer
+	keySource *chain.KeySource
 }
 
-func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, hsmCli *hsm.HsmClient) (chain.IChainAdaptor, error) {
+func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, signer ssm.Signer) (chain.IChainAdaptor, error) {
+	if signer == nil {
+		signer = &ssm.ECDSASigner{}
+	}
+	keySource, err := chain.NewKeySource(conf, db, ChainName, ssm.CurveSecp256k1, signer, hdwallet.Bip44(hdwallet.CoinTypeBitcoin))
+	if err != nil {
+		return nil, err
+	}
 	return &ChainAdaptor{
 		db:        db,
-		HsmClient: hsmCli,
-		signer:    &ssm.ECDSASigner{},
+		signer:    signer,
+		keySource: keySource,
 	}, nil
 }
 
@@ -84,21 +94,19 @@ func (c ChainAdaptor) CreateKeyPairsExportPublicKeyList(ctx context.Context, req
 	var keyList []leveldb.Key
 	var retKeyList []*wallet.ExportPublicKey
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKey, pubKey, compressPubKey, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKey, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			resp.Message = "Failed to create key pair"
 			return resp, nil
 		}
-		keyList = append(keyList, leveldb.Key{
-			PrivateKey: priKey,
-			PublicKey:  pubKey,
-		})
+		pubKey := keyItem.PublicKey
+		keyList = append(keyList, keyItem)
 		retKeyList = append(retKeyList, &wallet.ExportPublicKey{
 			PublicKey:         pubKey,
 			CompressPublicKey: compressPubKey,
 		})
 	}
-	if isOk := c.db.StoreKeys(keyList); !isOk {
+	if isOk := c.db.StoreKeys(ctx, keyList); !isOk {
 		resp.Message = "Failed to create key pair"
 		return resp, nil
 	}
@@ -117,15 +125,21 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 		resp.Message = "key num too large"
 		return resp, nil
 	}
+	// FROST 等只能产生 Schnorr 签名的密钥只能用于 p2tr 地址
+	if ssm.SchnorrOnly(c.signer) && request.AddressFormat != "p2tr" {
+		resp.Message = "threshold schnorr keys only support p2tr address"
+		return resp, nil
+	}
 	var keyList []leveldb.Key
 	var retKeyListWithAddressList []*wallet.ExportPublicKeyWithAddress
 
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKey, pubKey, compressPubKey, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKey, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			resp.Message = "Failed to create key pair"
 			return resp, nil
 		}
+		pubKey := keyItem.PublicKey
 
 		var address string
 		compressedPubKeyBytes, _ := hex.DecodeString(compressPubKey)
@@ -167,6 +181,7 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 				resp.Message = "create p2tr address fail"
 				return resp, nil
 			}
+			// 输出公钥直接使用公钥本身，没有 BIP86 tweak，与 FROST 签名的公钥一致
 			taprootPubKdy := schnorr.SerializePubKey(pubKey)
 			taprootAddr, err := btcutil.NewAddressTaproot(taprootPubKdy, &chaincfg.MainNetParams)
 			if err != nil {
@@ -179,10 +194,6 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 			resp.Message = "Do not support address type"
 			return resp, nil
 		}
-		keyItem := leveldb.Key{
-			PrivateKey: priKey,
-			PublicKey:  pubKey,
-		}
 		pukAddressItem := &wallet.ExportPublicKeyWithAddress{
 			PublicKey:         pubKey,
 			Address:           address,
@@ -191,7 +202,7 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 		keyList = append(keyList, keyItem)
 		retKeyListWithAddressList = append(retKeyListWithAddressList, pukAddressItem)
 	}
-	if isOK := c.db.StoreKeys(keyList); !isOK {
+	if isOK := c.db.StoreKeys(ctx, keyList); !isOK {
 		resp.Message = "Failed to store key pair"
 		return resp, nil
 	}
@@ -206,14 +217,19 @@ func (c ChainAdaptor) SignTransactionMessage(ctx context.Context, request *walle
 		Code: wallet.ReturnCode_ERROR,
 	}
 
-	privKey, isOk := c.db.GetPrivKey(request.PublicKey)
-	if !isOk {
-		return nil, errors.New("get private key fail")
+	keyRecord, err := chain.LoadSigningKey(ctx, c.db, ChainName, request.Network, request.PublicKey)
+	if err != nil {
+		log.Error("load signing key fail", "err", err)
+		resp.Message = "get private key fail: " + err.Error()
+		return resp, nil
 	}
+	privKey := keyRecord.PrivateKey
 
-	signature, err := c.signer.SignMessage(privKey, request.MessageHash)
+	signature, err := chain.SignMessage(ctx, ChainName, c.signer, privKey, request.MessageHash)
 	if err != nil {
 		log.Error("sign message fail", "err", err)
+		resp.Message = "sign message fail: " + err.Error()
+		return resp, nil
 	}
 
 	resp.Code = wallet.ReturnCode_SUCCESS
@@ -222,12 +238,102 @@ func (c ChainAdaptor) SignTransactionMessage(ctx context.Context, request *walle
 	return resp, nil
 }
 
+// BuildAndSignTransaction 还没有实现交易构造，调用方需要自己计算 sighash 并通过 SignTransactionMessage 签名
 func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wallet.BuildAndSignTransactionRequest) (*wallet.BuildAndSignTransactionResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	return &wallet.BuildAndSignTransactionResponse{
+		Code:    wallet.ReturnCode_ERROR,
+		Message: "build and sign transaction is not supported on bitcoin, sign the sighash with signTransactionMessage",
+	}, nil
+}
+
+// DecodeIntents 每个转出的输出是一个 Intent，找零到签名密钥自己地址的输出不算转账。
+// 手续费为输入金额之和减输出金额之和（satoshi），不使用请求体中的 fee；输出大于输入时拒绝
+func (c ChainAdaptor) DecodeIntents(publicKey string, txBase64Body string) ([]policy.Intent, error) {
+	jsonBytes, err := base64.StdEncoding.DecodeString(txBase64Body)
+	if err != nil {
+		return nil, err
+	}
+	var data BitcoinSchema
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return nil, err
+	}
+	from, ownAddresses, err := keyAddresses(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	inputs, outputs := new(big.Int), new(big.Int)
+	for _, vin := range data.Vins {
+		inputs.Add(inputs, new(big.Int).SetUint64(vin.Amount))
+	}
+	for _, vout := range data.Vouts {
+		outputs.Add(outputs, new(big.Int).SetUint64(vout.Amount))
+	}
+	if outputs.Cmp(inputs) > 0 {
+		return nil, fmt.Errorf("outputs %s exceed inputs %s", outputs, inputs)
+	}
+	fee := new(big.Int).Sub(inputs, outputs)
+	var intents []policy.Intent
+	for _, vout := range data.Vouts {
+		if ownAddresses[vout.Address] {
+			continue
+		}
+		intents = append(intents, policy.Intent{
+			Chain:  ChainName,
+			From:   from,
+			To:     vout.Address,
+			Asset:  policy.AssetNative,
+			Amount: new(big.Int).SetUint64(vout.Amount),
+			Fee:    fee,
+		})
+	}
+	// 全部是找零时手续费仍然要经过策略检查
+	if len(intents) == 0 {
+		intents = append(intents, policy.Intent{Chain: ChainName, From: from, To: from, Asset: policy.AssetNative, Amount: new(big.Int), Fee: fee})
+	}
+	return intents, nil
+}
+
+// keyAddresses 公钥的 p2wpkh 地址，以及 CreateKeyPairsWithAddresses 能为它生成的所有地址
+func keyAddresses(publicKey string) (string, map[string]bool, error) {
+	pubKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return "", nil, err
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
+	witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+	if err != nil {
+		return "", nil, err
+	}
+	p2pkhAddr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+	if err != nil {
+		return "", nil, err
+	}
+	script, err := txscript.PayToAddrScript(witnessAddr)
+	if err != nil {
+		return "", nil, err
+	}
+	p2shAddr, err := btcutil.NewAddressScriptHash(script, &chaincfg.MainNetParams)
+	if err != nil {
+		return "", nil, err
+	}
+	taprootAddr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(pubKey), &chaincfg.MainNetParams)
+	if err != nil {
+		return "", nil, err
+	}
+	addresses := map[string]bool{}
+	for _, addr := range []btcutil.Address{witnessAddr, p2pkhAddr, p2shAddr, taprootAddr} {
+		addresses[addr.EncodeAddress()] = true
+	}
+	return witnessAddr.EncodeAddress(), addresses, nil
 }
 
 func (c ChainAdaptor) BuildAndSignBatchTransaction(ctx context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	return &wallet.BuildAndSignBatchTransactionResponse{
+		Code:    wallet.ReturnCode_ERROR,
+		Message: chain.UnsupportedBatchSigning,
+	}, nil
 }
@@ -0,0 +1,106 @@
+package bitcoin
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+const testPublicKey = "044e3b81af9c2234cad09d679ce6035ed1392347ce64ce405f5dcd36228a25de6e47fd35c4215d1edf53e6f83de344615ce719bdb0fd878f6ed76f06dd277956de"
+
+func encodeSchema(t *testing.T, data BitcoinSchema) string {
+	body, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(body)
+}
+
+func TestDecodeIntentsFeeAndChange(t *testing.T) {
+	from, _, err := keyAddresses(testPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const to = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+	// 请求体中的 fee 不可信，手续费按输入减输出计算
+	body := encodeSchema(t, BitcoinSchema{
+		Fee:   "1",
+		Vins:  []Vin{{Hash: "aa", Amount: 100000}, {Hash: "bb", Index: 1, Amount: 50000}},
+		Vouts: []Vout{{Address: to, Amount: 120000}, {Address: from, Amount: 25000, Index: 1}},
+	})
+	intents, err := ChainAdaptor{}.DecodeIntents(testPublicKey, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intents) != 1 {
+		t.Fatalf("intents = %+v, want only the transfer", intents)
+	}
+	intent := intents[0]
+	if intent.From != from || intent.To != to || intent.Amount.Int64() != 120000 || intent.Fee.Int64() != 5000 {
+		t.Fatalf("intent = %+v", intent)
+	}
+
+	// 全部找零时仍然返回手续费
+	body = encodeSchema(t, BitcoinSchema{
+		Vins:  []Vin{{Hash: "aa", Amount: 100000}},
+		Vouts: []Vout{{Address: from, Amount: 90000}},
+	})
+	if intents, err = (ChainAdaptor{}).DecodeIntents(testPublicKey, body); err != nil || len(intents) != 1 || intents[0].Fee.Int64() != 10000 || intents[0].Amount.Sign() != 0 {
+		t.Fatalf("change only = %+v, err %v", intents, err)
+	}
+
+	// 输出大于输入
+	body = encodeSchema(t, BitcoinSchema{
+		Vins:  []Vin{{Hash: "aa", Amount: 100000}},
+		Vouts: []Vout{{Address: to, Amount: 100001}},
+	})
+	if _, err := (ChainAdaptor{}).DecodeIntents(testPublicKey, body); err == nil {
+		t.Fatal("outputs exceeding inputs were accepted")
+	}
+}
+
+// schnorrSigner 模拟只能产生 Schnorr 签名的门限签名器
+type schnorrSigner struct {
+	ssm.Signer
+}
+
+func (schnorrSigner) SchnorrOnly() bool {
+	return true
+}
+
+func TestSchnorrOnlyAddressFormat(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	create := func(signer ssm.Signer, format string) *wallet.CreateKeyPairsWithAddressesResponse {
+		adaptor, err := NewChainAdaptor(&config.Config{}, db, signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := adaptor.CreateKeyPairsWithAddresses(context.Background(), &wallet.CreateKeyPairsWithAddressesRequest{ChainName: ChainName, KeyNum: 1, AddressFormat: format})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	frostLike := schnorrSigner{&ssm.ECDSASigner{}}
+	if resp := create(frostLike, "p2pkh"); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatalf("schnorr-only p2pkh = %v", resp)
+	}
+	if resp := create(frostLike, "p2tr"); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("schnorr-only p2tr = %v", resp)
+	}
+	// HSM 在后端生成 ECDSA 密钥，fallback 是门限签名器时新地址格式不受限制
+	if resp := create(hsm.NewSigner(hsm.NewMemoryBackend(), ssm.CurveSecp256k1, frostLike), "p2pkh"); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("hsm p2pkh = %v", resp)
+	}
+}
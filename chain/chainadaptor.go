@@ -2,9 +2,26 @@ package chain
 
 import (
 	"context"
+
+	"github.com/0xshin-chan/wallet-sign/policy"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
 )
 
+// UnsupportedBatchSigning 没有实现批量签名的 adaptor 返回的错误信息
+const UnsupportedBatchSigning = "build and sign batch transaction is not supported"
+
+// BatchSigner 实现了 BuildAndSignBatchTransaction 的 adaptor 返回 true，
+// 其它 adaptor 的批量请求由 dispatcher 在预占 nonce 和限额之前拒绝
+type BatchSigner interface {
+	SupportsBatchSigning() bool
+}
+
+// SupportsBatchSigning adaptor 是否实现了批量签名
+func SupportsBatchSigning(adaptor IChainAdaptor) bool {
+	batch, ok := adaptor.(BatchSigner)
+	return ok && batch.SupportsBatchSigning()
+}
+
 type IChainAdaptor interface {
 	GetChainSignMethod(ctx context.Context, request *wallet.ChainSignMethodRequest) (*wallet.ChainSignMethodResponse, error)
 	GetChainSchema(ctx context.Context, request *wallet.ChainSchemaRequest) (*wallet.ChainSchemaResponse, error)
@@ -15,4 +32,8 @@ type IChainAdaptor interface {
 
 	BuildAndSignTransaction(ctx context.Context, request *wallet.BuildAndSignTransactionRequest) (*wallet.BuildAndSignTransactionResponse, error)
 	BuildAndSignBatchTransaction(ctx context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error)
+
+	// DecodeIntents 把 BuildAndSignTransaction 的交易请求体解码成交易策略检查的 Intent。
+	// Intent.From 由签名公钥 publicKey 推导，请求体中的发送方与之不一致时返回错误
+	DecodeIntents(publicKey string, txBase64Body string) ([]policy.Intent, error)
 }
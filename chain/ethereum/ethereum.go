@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -16,25 +17,34 @@ import (
 
 	"github.com/0xshin-chan/wallet-sign/chain"
 	"github.com/0xshin-chan/wallet-sign/config"
-	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
 	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
 	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
 )
 
 const ChainName = "Ethereum"
 
 type ChainAdaptor struct {
 	db        *leveldb.Keys
-	HsmClient *hsm.HsmClient
-	signer    *ssm.ECDSASigner
+	signer    ssm.Signer
+	keySource *chain.KeySource
 }
 
-func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, hsmCli *hsm.HsmClient) (chain.IChainAdaptor, error) {
+func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, signer ssm.Signer) (chain.IChainAdaptor, error) {
+	if signer == nil {
+		signer = &ssm.ECDSASigner{}
+	}
+	keySource, err := chain.NewKeySource(conf, db, ChainName, ssm.CurveSecp256k1, signer, hdwallet.Bip44(hdwallet.CoinTypeEthereum))
+	if err != nil {
+		return nil, err
+	}
 	return &ChainAdaptor{
 		db:        db,
-		HsmClient: hsmCli,
-		signer:    &ssm.ECDSASigner{},
+		signer:    signer,
+		keySource: keySource,
 	}, nil
 }
 
@@ -97,15 +107,12 @@ func (c ChainAdaptor) CreateKeyPairsExportPublicKeyList(ctx context.Context, req
 	var retKeyList []*wallet.ExportPublicKey
 
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKey, pubKey, compressPubKey, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKey, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			resp.Message = "create key pair fail"
 			return resp, nil
 		}
-		keyItem := leveldb.Key{
-			PrivateKey: priKey,
-			PublicKey:  pubKey,
-		}
+		pubKey := keyItem.PublicKey
 		pukItem := &wallet.ExportPublicKey{
 			PublicKey:         pubKey,
 			CompressPublicKey: compressPubKey,
@@ -113,7 +120,7 @@ func (c ChainAdaptor) CreateKeyPairsExportPublicKeyList(ctx context.Context, req
 		retKeyList = append(retKeyList, pukItem)
 		keyList = append(keyList, keyItem)
 	}
-	isOk := c.db.StoreKeys(keyList)
+	isOk := c.db.StoreKeys(ctx, keyList)
 	if !isOk {
 		log.Error("store keys fail", "isOk", isOk)
 		return nil, errors.New("store keys fail")
@@ -136,15 +143,12 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 	var keyList []leveldb.Key
 	var retKeyWithAddrList []*wallet.ExportPublicKeyWithAddress
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKey, pubKey, compressPubKey, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKey, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			resp.Message = "create key pair fail"
 			return resp, nil
 		}
-		keyItem := leveldb.Key{
-			PrivateKey: priKey,
-			PublicKey:  pubKey,
-		}
+		pubKey := keyItem.PublicKey
 
 		publicKeyByte, err := hex.DecodeString(pubKey)
 		pukAddrItem := &wallet.ExportPublicKeyWithAddress{
@@ -155,7 +159,7 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 		retKeyWithAddrList = append(retKeyWithAddrList, pukAddrItem)
 		keyList = append(keyList, keyItem)
 	}
-	isOk := c.db.StoreKeys(keyList)
+	isOk := c.db.StoreKeys(ctx, keyList)
 	if !isOk {
 		log.Error("store keys fail", "isOk", isOk)
 		return nil, errors.New("store keys fail")
@@ -171,14 +175,19 @@ func (c ChainAdaptor) SignTransactionMessage(ctx context.Context, request *walle
 		Code: wallet.ReturnCode_ERROR,
 	}
 
-	privKey, isOk := c.db.GetPrivKey(request.PublicKey)
-	if !isOk {
-		return nil, errors.New("get private key fail")
+	keyRecord, err := chain.LoadSigningKey(ctx, c.db, ChainName, request.Network, request.PublicKey)
+	if err != nil {
+		log.Error("load signing key fail", "err", err)
+		resp.Message = "get private key fail: " + err.Error()
+		return resp, nil
 	}
+	privKey := keyRecord.PrivateKey
 
-	signature, err := c.signer.SignMessage(privKey, request.MessageHash)
+	signature, err := chain.SignMessage(ctx, ChainName, c.signer, privKey, request.MessageHash)
 	if err != nil {
 		log.Error("sign message fail", "err", err)
+		resp.Message = "sign message fail: " + err.Error()
+		return resp, nil
 	}
 
 	resp.Code = wallet.ReturnCode_SUCCESS
@@ -193,32 +202,39 @@ func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wall
 		Code: wallet.ReturnCode_ERROR,
 	}
 
-	dFeeTx, _, err := c.buildDynamicFeeTx(request.TxBase64Body)
+	_, buildSpan := chain.StartPhase(ctx, ChainName, "build")
+	dFeeTx, txReq, err := c.buildDynamicFeeTx(request.TxBase64Body)
 	if err != nil {
+		tracing.End(buildSpan, err)
 		return nil, err
 	}
+	if _, err := checkFromAddress(txReq.FromAddress, request.PublicKey); err != nil {
+		tracing.End(buildSpan, err)
+		resp.Message = err.Error()
+		return resp, nil
+	}
 
 	rawTx, err := CreateEip1559UnSignTx(dFeeTx, dFeeTx.ChainID)
+	tracing.End(buildSpan, err)
 	if err != nil {
 		log.Error("create un sign tx fail", "err", err)
 		resp.Message = "get un sign tx fail"
 		return resp, nil
 	}
 
-	privKey, isOk := c.db.GetPrivKey(request.PublicKey)
-	log.Error("priKey ==== ", privKey, "pubKey ==== ", request.PublicKey)
-
-	if !isOk {
+	keyRecord, err := chain.LoadSigningKey(ctx, c.db, ChainName, request.Network, request.PublicKey)
+	if err != nil {
 		log.Error("get private key by public key fail", "err", err)
-		resp.Message = "get private key by public key fail"
+		resp.Message = "get private key by public key fail: " + err.Error()
 		return resp, nil
 	}
+	privKey := keyRecord.PrivateKey
 
 	// 用私钥对交易哈希进行签名，得到 65 字节的签名 (R, S, V)
-	signature, err := c.signer.SignMessage(privKey, rawTx)
+	signature, err := chain.SignMessage(ctx, ChainName, c.signer, privKey, rawTx)
 	if err != nil {
 		log.Error("sign transaction fail", "err", err)
-		resp.Message = "sign transaction fail"
+		resp.Message = "sign transaction fail: " + err.Error()
 		return resp, nil
 	}
 
@@ -235,6 +251,12 @@ func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wall
 		resp.Message = "create signed tx fail"
 		return resp, nil
 	}
+	// HSM 返回的签名恢复 id 是推算出来的，广播前确认交易确实由该公钥签名
+	if err := checkSender(signedTx, eip1559Signer, request.PublicKey); err != nil {
+		log.Error("check tx sender fail", "err", err)
+		resp.Message = "check tx sender fail: " + err.Error()
+		return resp, nil
+	}
 	log.Info("sign transaction success",
 		"eip1559Signer", eip1559Signer,
 		"signedTx", signedTx,
@@ -250,8 +272,40 @@ func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wall
 }
 
 func (c ChainAdaptor) BuildAndSignBatchTransaction(ctx context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	return &wallet.BuildAndSignBatchTransactionResponse{
+		Code:    wallet.ReturnCode_ERROR,
+		Message: chain.UnsupportedBatchSigning,
+	}, nil
+}
+
+// DecodeIntents 从与签名时相同的 DynamicFeeTx 中解码，ERC20 转账的资产和被调用合约都是 contract_address。
+// 发送方是签名公钥对应的地址，from_address 与它不一致时拒绝
+func (c ChainAdaptor) DecodeIntents(publicKey string, txBase64Body string) ([]policy.Intent, error) {
+	dFeeTx, txReq, err := c.buildDynamicFeeTx(txBase64Body)
+	if err != nil {
+		return nil, err
+	}
+	from, err := checkFromAddress(txReq.FromAddress, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	amount, _ := new(big.Int).SetString(txReq.Amount, 10)
+	intent := policy.Intent{
+		Chain:    ChainName,
+		From:     from.Hex(),
+		To:       common.HexToAddress(txReq.ToAddress).Hex(),
+		Asset:    policy.AssetNative,
+		Amount:   amount,
+		Fee:      new(big.Int).Mul(new(big.Int).SetUint64(dFeeTx.Gas), dFeeTx.GasFeeCap),
+		CallData: dFeeTx.Data,
+		Nonce:    fmt.Sprintf("%s:%d", dFeeTx.ChainID, dFeeTx.Nonce),
+	}
+	if len(dFeeTx.Data) >= 4 {
+		intent.Asset = dFeeTx.To.Hex()
+		intent.Contract = dFeeTx.To.Hex()
+		intent.Method = hex.EncodeToString(dFeeTx.Data[:4])
+	}
+	return []policy.Intent{intent}, nil
 }
 
 func (c ChainAdaptor) buildDynamicFeeTx(base64Tx string) (*types.DynamicFeeTx, *Eip1559DynamicFeeTx, error) {
@@ -352,3 +406,46 @@ func isEthTransfer(tx *Eip1559DynamicFeeTx) bool {
 	"contract_address": "0x00"
 }
 */
+
+// publicKeyAddress hex 编码的压缩或非压缩公钥对应的地址
+func publicKeyAddress(publicKey string) (common.Address, error) {
+	pubKeyBytes, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pubKey, err := crypto.UnmarshalPubkey(pubKeyBytes)
+	if err != nil {
+		if pubKey, err = crypto.DecompressPubkey(pubKeyBytes); err != nil {
+			return common.Address{}, errors.New("invalid public key")
+		}
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
+
+// checkFromAddress 返回签名公钥对应的地址，请求体中填写了 from_address 时必须与之一致
+func checkFromAddress(fromAddress string, publicKey string) (common.Address, error) {
+	from, err := publicKeyAddress(publicKey)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if fromAddress != "" && !strings.EqualFold(fromAddress, from.Hex()) {
+		return common.Address{}, fmt.Errorf("from address %s does not match signing key address %s", fromAddress, from)
+	}
+	return from, nil
+}
+
+// checkSender 从签名中恢复发送方地址，与 publicKey 对应的地址比较
+func checkSender(tx *types.Transaction, signer types.Signer, publicKey string) error {
+	want, err := publicKeyAddress(publicKey)
+	if err != nil {
+		return err
+	}
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	if sender != want {
+		return fmt.Errorf("signature recovers to %s, want %s", sender, want)
+	}
+	return nil
+}
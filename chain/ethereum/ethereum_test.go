@@ -0,0 +1,103 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// TestBuildAndSignTransactionWithHsmKey 内存后端与 Cloud KMS 一样返回 DER 签名，签出的交易应能恢复出密钥地址
+func TestBuildAndSignTransactionWithHsmKey(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := hsm.NewSigner(hsm.NewMemoryBackend(), ssm.CurveSecp256k1, &ssm.ECDSASigner{})
+	adaptor, err := NewChainAdaptor(&config.Config{}, db, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := consumer.NewContext(context.Background(), "wallet")
+
+	keys, err := adaptor.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ChainName: ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	key := keys.PublicKeyAddresses[0]
+
+	txJson := `{"chain_id":"11155111","nonce":7,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+		`"gas_limit":21000,"max_fee_per_gas":"327993150328","max_priority_fee_per_gas":"32799315032","amount":"1000000000000000"}`
+	// 多签几次，覆盖 KMS 返回 high-S 和两种恢复 id 的情况
+	for i := 0; i < 8; i++ {
+		resp, err := adaptor.BuildAndSignTransaction(ctx, &wallet.BuildAndSignTransactionRequest{
+			ChainName:    ChainName,
+			PublicKey:    key.PublicKey,
+			TxBase64Body: base64.StdEncoding.EncodeToString([]byte(txJson)),
+		})
+		if err != nil || resp.Code != wallet.ReturnCode_SUCCESS {
+			t.Fatalf("sign = %v, err %v", resp, err)
+		}
+
+		rawTx, err := hexutil.Decode(resp.SignedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tx types.Transaction
+		if err := tx.UnmarshalBinary(rawTx); err != nil {
+			t.Fatalf("decode raw tx: %v", err)
+		}
+		if tx.Hash().String() != resp.TxHash {
+			t.Fatalf("tx hash %s, want %s", tx.Hash(), resp.TxHash)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), &tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.EqualFold(sender.String(), key.Address) {
+			t.Fatalf("sender %s, want %s", sender, key.Address)
+		}
+		if tx.Nonce() != 7 || *tx.To() != common.HexToAddress("0x35096AD62E57e86032a3Bb35aDaCF2240d55421D") {
+			t.Fatalf("unexpected tx nonce %d to %s", tx.Nonce(), tx.To())
+		}
+	}
+}
+
+// TestSignTransactionMessageReportsSignerError 后端签名失败时返回错误，不能返回空签名的 SUCCESS
+func TestSignTransactionMessageReportsSignerError(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := hsm.NewSigner(hsm.NewMemoryBackend(), ssm.CurveSecp256k1, &ssm.ECDSASigner{})
+	adaptor, err := NewChainAdaptor(&config.Config{}, db, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := consumer.NewContext(context.Background(), "wallet")
+	// 密钥记录存在，但后端中已经没有这个密钥
+	record := leveldb.Key{Chain: ChainName, Consumer: "wallet", PublicKey: "04aa", PrivateKey: hsm.KeyIDPrefix + hsm.BackendMemory + ":missing"}
+	if !db.StoreKeys(ctx, []leveldb.Key{record}) {
+		t.Fatal("store key fail")
+	}
+
+	resp, err := adaptor.SignTransactionMessage(ctx, &wallet.SignTransactionMessageRequest{
+		ChainName:   ChainName,
+		PublicKey:   record.PublicKey,
+		MessageHash: common.Hash{1}.Hex(),
+	})
+	if err != nil || resp.Code != wallet.ReturnCode_ERROR || resp.Signature != "" || !strings.HasPrefix(resp.Message, "sign message fail: ") {
+		t.Fatalf("sign with missing hsm key = %v, err %v", resp, err)
+	}
+}
@@ -88,11 +88,11 @@ func CreateEip1559SignedTx(txData *types.DynamicFeeTx, signature []byte, chainId
 	if err != nil {
 		return nil, nil, "", "", errors.New("tx with signature fail")
 	}
-	// 将已签名交易进行 RLP 编码（以太坊要求的交易格式）生成最终的可广播原始交易
-	signedTxData, err := rlp.EncodeToBytes(signedTx)
+	// 编码为 EIP-2718 格式（0x02 || rlp(tx)），即可广播的原始交易
+	signedTxData, err := signedTx.MarshalBinary()
 	if err != nil {
 		return nil, nil, "", "", errors.New("encode tx to byte fail")
 	}
-	// 返回签名器、已签名交易、最终交易的 RLP 编码（去掉前 2 个字节）和 tx hash
-	return signer, signedTx, "0x" + hex.EncodeToString(signedTxData)[4:], signedTx.Hash().String(), nil
+	// 返回签名器、已签名交易、最终交易的原始编码和 tx hash
+	return signer, signedTx, "0x" + hex.EncodeToString(signedTxData), signedTx.Hash().String(), nil
 }
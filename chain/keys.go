@@ -0,0 +1,41 @@
+package chain
+
+import (
+	"context"
+	"errors"
+
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+var (
+	ErrKeyNotFound      = errors.New("key not found for this chain")
+	ErrKeyNotOwned      = errors.New("key does not belong to the calling consumer")
+	ErrKeyWrongNetwork  = errors.New("key does not belong to this network")
+	ErrKeyDisabled      = errors.New("key is disabled")
+	ErrKeyStoreNotReady = errors.New("key store is not available")
+)
+
+// LoadSigningKey 读取签名用的密钥，并确认它属于当前链、network 和调用方
+func LoadSigningKey(ctx context.Context, db *leveldb.Keys, chainName string, network string, publicKey string) (*leveldb.Key, error) {
+	if db == nil {
+		return nil, ErrKeyStoreNotReady
+	}
+	record, err := db.GetKey(ctx, chainName, publicKey)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrKeyNotFound) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	if record.Consumer != consumer.FromContext(ctx) {
+		return nil, ErrKeyNotOwned
+	}
+	if network != "" && record.Network != "" && network != record.Network {
+		return nil, ErrKeyWrongNetwork
+	}
+	if record.KeyStatus() == leveldb.KeyStatusDisabled {
+		return nil, ErrKeyDisabled
+	}
+	return record, nil
+}
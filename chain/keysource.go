@@ -0,0 +1,111 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// KeySource 负责为适配器生成密钥对：未开启 HD 钱包时随机生成，开启后从该链的主种子按路径派生
+type KeySource struct {
+	chain    string
+	curve    string
+	db       *leveldb.Keys
+	signer   ssm.Signer
+	hdSigner ssm.HDSigner
+	seedName string
+	pathFn   hdwallet.PathFunc
+	envName  string
+
+	seedLock sync.Mutex
+	seed     []byte
+}
+
+func NewKeySource(conf *config.Config, db *leveldb.Keys, chainName string, curve string, signer ssm.Signer, pathFn hdwallet.PathFunc) (*KeySource, error) {
+	ks := &KeySource{
+		chain:  chainName,
+		curve:  curve,
+		db:     db,
+		signer: signer,
+	}
+	if !conf.HdWallet.Enabled {
+		return ks, nil
+	}
+	hdSigner, ok := signer.(ssm.HDSigner)
+	if !ok {
+		return nil, errors.New("signer does not support hd derivation")
+	}
+	ks.hdSigner = hdSigner
+	ks.seedName = conf.SeedName(chainName)
+	ks.pathFn = pathFn
+	ks.envName = conf.HdWallet.PassphraseEnv
+	return ks, nil
+}
+
+// CreateKeyPair 生成一个待存储的密钥记录和它的压缩公钥，记录归属于当前链、network 和调用方
+func (ks *KeySource) CreateKeyPair(ctx context.Context, network string) (leveldb.Key, string, error) {
+	record := leveldb.Key{
+		Chain:     ks.chain,
+		Curve:     ks.curve,
+		Network:   network,
+		Consumer:  consumer.FromContext(ctx),
+		CreatedAt: time.Now().Unix(),
+	}
+	if ks.hdSigner == nil {
+		priKey, pubKey, compressPubKey, err := ks.signer.CreateKeyPair()
+		if err != nil {
+			return leveldb.Key{}, "", err
+		}
+		record.PrivateKey = priKey
+		record.PublicKey = pubKey
+		return record, compressPubKey, nil
+	}
+	seed, err := ks.unlockSeed()
+	if err != nil {
+		return leveldb.Key{}, "", err
+	}
+	index, err := ks.db.NextIndex(ks.seedName)
+	if err != nil {
+		log.Error("allocate hd index fail", "seed", ks.seedName, "err", err)
+		return leveldb.Key{}, "", err
+	}
+	path := ks.pathFn(index)
+	priKey, pubKey, compressPubKey, err := ks.hdSigner.DeriveKeyPair(seed, path)
+	if err != nil {
+		return leveldb.Key{}, "", err
+	}
+	record.PrivateKey = priKey
+	record.PublicKey = pubKey
+	record.DerivationPath = path
+	return record, compressPubKey, nil
+}
+
+// unlockSeed 首次使用时解密主种子并缓存在内存中
+func (ks *KeySource) unlockSeed() ([]byte, error) {
+	ks.seedLock.Lock()
+	defer ks.seedLock.Unlock()
+	if ks.seed != nil {
+		return ks.seed, nil
+	}
+	encryptedSeed, ok := ks.db.GetSeed(ks.seedName)
+	if !ok {
+		return nil, errors.New("hd seed is not initialized for " + ks.seedName)
+	}
+	seed, err := hdwallet.DecryptSeed(encryptedSeed, os.Getenv(ks.envName))
+	if err != nil {
+		log.Error("decrypt hd seed fail", "seed", ks.seedName, "err", err)
+		return nil, err
+	}
+	ks.seed = seed
+	return seed, nil
+}
@@ -0,0 +1,301 @@
+package solana
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
+	"github.com/gagliardetto/solana-go/programs/memo"
+	"github.com/gagliardetto/solana-go/programs/stake"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/programs/token"
+
+	"github.com/0xshin-chan/wallet-sign/policy"
+)
+
+// StakeAccountSpace stake 账户的数据大小（字节）
+const StakeAccountSpace = 200
+
+// schemaActions 返回交易中的 action 列表，老格式（只有单笔转账）会被转换成一个 action
+func schemaActions(data *SolanaSchema) []SolanaAction {
+	if len(data.Actions) > 0 {
+		return data.Actions
+	}
+	actionType := ActionSolTransfer
+	if !isSOLTransfer(data.ContractAddress) {
+		actionType = ActionSplTransfer
+	}
+	return []SolanaAction{{
+		Type:            actionType,
+		ToAddress:       data.ToAddress,
+		ContractAddress: data.ContractAddress,
+		Decimal:         data.Decimal,
+		TokenCreate:     data.TokenCreate,
+		Value:           data.Value,
+	}}
+}
+
+// buildInstructions 把所有 action 按顺序组装成指令列表
+func buildInstructions(fromPubKey solana.PublicKey, actions []SolanaAction) ([]solana.Instruction, error) {
+	var instructions []solana.Instruction
+	for i, action := range actions {
+		actionInstructions, err := buildActionInstructions(fromPubKey, action)
+		if err != nil {
+			return nil, fmt.Errorf("action %d (%s): %w", i, action.Type, err)
+		}
+		instructions = append(instructions, actionInstructions...)
+	}
+	if len(instructions) == 0 {
+		return nil, fmt.Errorf("transaction has no actions")
+	}
+	return instructions, nil
+}
+
+func buildActionInstructions(fromPubKey solana.PublicKey, action SolanaAction) ([]solana.Instruction, error) {
+	switch action.Type {
+	case ActionSolTransfer:
+		toPubKey, err := solana.PublicKeyFromBase58(action.ToAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to address: %w", err)
+		}
+		value, err := strconv.ParseUint(action.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		return []solana.Instruction{
+			system.NewTransferInstruction(value, fromPubKey, toPubKey).Build(),
+		}, nil
+	case ActionSplTransfer:
+		toPubKey, err := solana.PublicKeyFromBase58(action.ToAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to address: %w", err)
+		}
+		mintPubKey, err := solana.PublicKeyFromBase58(action.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract address: %w", err)
+		}
+		fromTokenAccount, _, err := solana.FindAssociatedTokenAddress(fromPubKey, mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("find from associated token address: %w", err)
+		}
+		toTokenAccount, _, err := solana.FindAssociatedTokenAddress(toPubKey, mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("find to associated token address: %w", err)
+		}
+		actualValue, err := splAmount(action)
+		if err != nil {
+			return nil, err
+		}
+
+		var instructions []solana.Instruction
+		//交易体中 TokenCreate 为 true 时先给 toAddress 创建 ATA
+		if action.TokenCreate {
+			instructions = append(instructions, associatedtokenaccount.NewCreateInstruction(
+				fromPubKey,
+				toPubKey,
+				mintPubKey,
+			).Build())
+		}
+		instructions = append(instructions, token.NewTransferInstruction(
+			actualValue,
+			fromTokenAccount,
+			toTokenAccount,
+			fromPubKey,
+			[]solana.PublicKey{},
+		).Build())
+		return instructions, nil
+	case ActionCreateATA:
+		ownerPubKey, err := solana.PublicKeyFromBase58(action.ToAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner address: %w", err)
+		}
+		mintPubKey, err := solana.PublicKeyFromBase58(action.ContractAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract address: %w", err)
+		}
+		return []solana.Instruction{
+			associatedtokenaccount.NewCreateInstruction(fromPubKey, ownerPubKey, mintPubKey).Build(),
+		}, nil
+	case ActionMemo:
+		if action.Memo == "" {
+			return nil, fmt.Errorf("memo is empty")
+		}
+		return []solana.Instruction{
+			memo.NewMemoInstruction([]byte(action.Memo), fromPubKey).Build(),
+		}, nil
+	case ActionStakeCreate:
+		if action.StakeSeed == "" {
+			return nil, fmt.Errorf("stake seed is empty")
+		}
+		lamports, err := strconv.ParseUint(action.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		// stake 账户由 from 地址加 seed 派生，这样只需要 from 一个签名
+		stakeAccount, err := solana.CreateWithSeed(fromPubKey, action.StakeSeed, solana.StakeProgramID)
+		if err != nil {
+			return nil, fmt.Errorf("derive stake account: %w", err)
+		}
+		// 同 newDelegateStakeInstruction，去掉 stake 账户的 SIGNER 标记
+		initialize := stake.NewInitializeInstruction(fromPubKey, fromPubKey, stakeAccount)
+		initialize.AccountMetaSlice[0] = solana.Meta(stakeAccount).WRITE()
+		instructions := []solana.Instruction{
+			system.NewCreateAccountWithSeedInstruction(
+				fromPubKey,
+				action.StakeSeed,
+				lamports,
+				StakeAccountSpace,
+				solana.StakeProgramID,
+				fromPubKey,
+				stakeAccount,
+				fromPubKey,
+			).Build(),
+			initialize.Build(),
+		}
+		// 指定了 vote 账户时创建后直接委托
+		if action.VoteAccount != "" {
+			voteAccount, err := solana.PublicKeyFromBase58(action.VoteAccount)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vote account: %w", err)
+			}
+			instructions = append(instructions, newDelegateStakeInstruction(voteAccount, fromPubKey, stakeAccount))
+		}
+		return instructions, nil
+	case ActionStakeDelegate:
+		stakeAccount, err := actionStakeAccount(fromPubKey, action)
+		if err != nil {
+			return nil, err
+		}
+		voteAccount, err := solana.PublicKeyFromBase58(action.VoteAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vote account: %w", err)
+		}
+		return []solana.Instruction{
+			newDelegateStakeInstruction(voteAccount, fromPubKey, stakeAccount),
+		}, nil
+	case ActionStakeDeactivate:
+		stakeAccount, err := actionStakeAccount(fromPubKey, action)
+		if err != nil {
+			return nil, err
+		}
+		return []solana.Instruction{
+			stake.NewDeactivateInstruction(stakeAccount, fromPubKey).Build(),
+		}, nil
+	case ActionStakeWithdraw:
+		stakeAccount, err := actionStakeAccount(fromPubKey, action)
+		if err != nil {
+			return nil, err
+		}
+		lamports, err := strconv.ParseUint(action.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+		// 没有指定接收地址时提回 from 地址
+		recipient := fromPubKey
+		if action.ToAddress != "" {
+			recipient, err = solana.PublicKeyFromBase58(action.ToAddress)
+			if err != nil {
+				return nil, fmt.Errorf("invalid to address: %w", err)
+			}
+		}
+		return []solana.Instruction{
+			stake.NewWithdrawInstruction(lamports, stakeAccount, recipient, fromPubKey).Build(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported action type")
+	}
+}
+
+// splAmount 按精度把十进制的 value 精确换算成最小单位，小数位超过精度或超出 uint64 时拒绝
+func splAmount(action SolanaAction) (uint64, error) {
+	// big.Rat 同时接受 3/4 这样的分数，这里只允许十进制小数
+	if strings.Contains(action.Value, "/") {
+		return 0, fmt.Errorf("invalid value: %s", action.Value)
+	}
+	value, ok := new(big.Rat).SetString(action.Value)
+	if !ok {
+		return 0, fmt.Errorf("invalid value: %s", action.Value)
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(action.Decimal)), nil)
+	value.Mul(value, new(big.Rat).SetInt(scale))
+	if !value.IsInt() {
+		return 0, fmt.Errorf("value %s has more than %d decimal places", action.Value, action.Decimal)
+	}
+	amount := value.Num()
+	if amount.Sign() < 0 || !amount.IsUint64() {
+		return 0, fmt.Errorf("value %s is out of range", action.Value)
+	}
+	return amount.Uint64(), nil
+}
+
+// actionIntent 返回交易策略检查的 Intent，金额与 buildActionInstructions 写入指令的一致
+func actionIntent(fromPubKey solana.PublicKey, action SolanaAction) (policy.Intent, error) {
+	intent := policy.Intent{Chain: ChainName, From: fromPubKey.String(), Method: action.Type}
+	var lamports string
+	switch action.Type {
+	case ActionSolTransfer:
+		intent.To, intent.Asset, lamports = action.ToAddress, policy.AssetNative, action.Value
+	case ActionSplTransfer:
+		amount, err := splAmount(action)
+		if err != nil {
+			return intent, err
+		}
+		intent.To, intent.Asset, intent.Contract = action.ToAddress, action.ContractAddress, action.ContractAddress
+		intent.Amount = new(big.Int).SetUint64(amount)
+	case ActionCreateATA:
+		intent.To, intent.Contract = action.ToAddress, action.ContractAddress
+	case ActionMemo, ActionStakeDeactivate:
+	case ActionStakeCreate:
+		intent.To, intent.Asset, lamports = action.VoteAccount, policy.AssetNative, action.Value
+		if intent.To == "" {
+			stakeAccount, err := solana.CreateWithSeed(fromPubKey, action.StakeSeed, solana.StakeProgramID)
+			if err != nil {
+				return intent, fmt.Errorf("derive stake account: %w", err)
+			}
+			intent.To = stakeAccount.String()
+		}
+	case ActionStakeDelegate:
+		intent.To = action.VoteAccount
+	case ActionStakeWithdraw:
+		intent.To, intent.Asset, lamports = action.ToAddress, policy.AssetNative, action.Value
+		if intent.To == "" {
+			intent.To = fromPubKey.String()
+		}
+	default:
+		return intent, fmt.Errorf("unsupported action type")
+	}
+	if lamports != "" {
+		value, err := strconv.ParseUint(lamports, 10, 64)
+		if err != nil {
+			return intent, fmt.Errorf("invalid value: %w", err)
+		}
+		intent.Amount = new(big.Int).SetUint64(value)
+	}
+	return intent, nil
+}
+
+// newDelegateStakeInstruction stake 程序的 Initialize 和 DelegateStake 并不要求 stake 账户签名，
+// seed 派生的账户也没有私钥，这里去掉 SDK 默认加的 SIGNER 标记
+func newDelegateStakeInstruction(voteAccount, stakeAuthority, stakeAccount solana.PublicKey) solana.Instruction {
+	delegate := stake.NewDelegateStakeInstruction(voteAccount, stakeAuthority, stakeAccount)
+	delegate.AccountMetaSlice[0] = solana.Meta(stakeAccount).WRITE()
+	return delegate.Build()
+}
+
+// actionStakeAccount 优先使用显式指定的 stake 账户，否则按 from + seed 派生
+func actionStakeAccount(fromPubKey solana.PublicKey, action SolanaAction) (solana.PublicKey, error) {
+	if action.StakeAccount != "" {
+		stakeAccount, err := solana.PublicKeyFromBase58(action.StakeAccount)
+		if err != nil {
+			return solana.PublicKey{}, fmt.Errorf("invalid stake account: %w", err)
+		}
+		return stakeAccount, nil
+	}
+	if action.StakeSeed == "" {
+		return solana.PublicKey{}, fmt.Errorf("stake account or stake seed is required")
+	}
+	return solana.CreateWithSeed(fromPubKey, action.StakeSeed, solana.StakeProgramID)
+}
@@ -0,0 +1,66 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestBuildInstructionsMultiAction(t *testing.T) {
+	from := solana.NewWallet().PublicKey()
+	actions := []SolanaAction{
+		{Type: ActionSolTransfer, ToAddress: solana.NewWallet().PublicKey().String(), Value: "1000"},
+		{Type: ActionSolTransfer, ToAddress: solana.NewWallet().PublicKey().String(), Value: "2000"},
+		{Type: ActionMemo, Memo: "order-1"},
+		{Type: ActionStakeCreate, StakeSeed: "stake:0", Value: "5000000", VoteAccount: solana.NewWallet().PublicKey().String()},
+		{Type: ActionStakeDeactivate, StakeSeed: "stake:0"},
+	}
+	instructions, err := buildInstructions(from, actions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2 transfers + memo + (create, initialize, delegate) + deactivate
+	if len(instructions) != 7 {
+		t.Fatalf("instructions = %d, want 7", len(instructions))
+	}
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(from))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Message.AccountKeys[0] != from {
+		t.Fatalf("fee payer = %s, want %s", tx.Message.AccountKeys[0], from)
+	}
+	if tx.Message.Header.NumRequiredSignatures != 1 {
+		t.Fatalf("required signatures = %d, want 1", tx.Message.Header.NumRequiredSignatures)
+	}
+}
+
+func TestBuildInstructionsRejectsUnknownAction(t *testing.T) {
+	from := solana.NewWallet().PublicKey()
+	if _, err := buildInstructions(from, []SolanaAction{{Type: "swap"}}); err == nil {
+		t.Fatal("expected error for unsupported action")
+	}
+	if _, err := buildInstructions(from, nil); err == nil {
+		t.Fatal("expected error for empty actions")
+	}
+}
+
+func TestSplAmount(t *testing.T) {
+	for value, want := range map[string]uint64{
+		"1":                     1000000,
+		"0.1":                   100000,
+		"1.000001":              1000001,
+		"18446744073709.551615": 18446744073709551615,
+	} {
+		amount, err := splAmount(SolanaAction{Value: value, Decimal: 6})
+		if err != nil || amount != want {
+			t.Fatalf("splAmount(%s) = %d, err %v, want %d", value, amount, err, want)
+		}
+	}
+	// 不能精确表示的金额直接拒绝，不截断
+	for _, value := range []string{"0.0000001", "18446744073709.551616", "-1", "1/3", "abc", ""} {
+		if amount, err := splAmount(SolanaAction{Value: value, Decimal: 6}); err == nil {
+			t.Fatalf("splAmount(%s) = %d, want error", value, amount)
+		}
+	}
+}
@@ -6,38 +6,42 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"math"
-	"strconv"
+	"fmt"
 
 	"github.com/cosmos/btcutil/base58"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/gagliardetto/solana-go"
-	associatedtokenaccount "github.com/gagliardetto/solana-go/programs/associated-token-account"
-	"github.com/gagliardetto/solana-go/programs/system"
-	"github.com/gagliardetto/solana-go/programs/token"
 
 	"github.com/0xshin-chan/wallet-sign/chain"
 	"github.com/0xshin-chan/wallet-sign/config"
-	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
 	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
 	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
 )
 
 const ChainName = "Solana"
 
 type ChainAdaptor struct {
 	db        *leveldb.Keys
-	HsmClient *hsm.HsmClient
 	signer    ssm.Signer
+	keySource *chain.KeySource
 }
 
-func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, hsmCli *hsm.HsmClient) (chain.IChainAdaptor, error) {
+func NewChainAdaptor(conf *config.Config, db *leveldb.Keys, signer ssm.Signer) (chain.IChainAdaptor, error) {
+	if signer == nil {
+		signer = &ssm.EdDSASigner{}
+	}
+	keySource, err := chain.NewKeySource(conf, db, ChainName, ssm.CurveEd25519, signer, hdwallet.Slip10(hdwallet.CoinTypeSolana))
+	if err != nil {
+		return nil, err
+	}
 	return &ChainAdaptor{
 		db:        db,
-		HsmClient: hsmCli,
-		signer:    &ssm.EdDSASigner{},
+		signer:    signer,
+		keySource: keySource,
 	}, nil
 }
 
@@ -61,6 +65,18 @@ func (c ChainAdaptor) GetChainSchema(ctx context.Context, request *wallet.ChainS
 		ToAddress:       "",
 		TokenId:         "",
 		Value:           "",
+		Actions: []SolanaAction{{
+			Type:            ActionSolTransfer,
+			ToAddress:       "",
+			ContractAddress: "",
+			Decimal:         0,
+			TokenCreate:     false,
+			Value:           "",
+			Memo:            "",
+			StakeSeed:       "",
+			StakeAccount:    "",
+			VoteAccount:     "",
+		}},
 	}
 	b, err := json.Marshal(ss)
 	if err != nil {
@@ -84,14 +100,13 @@ func (c ChainAdaptor) CreateKeyPairsExportPublicKeyList(ctx context.Context, req
 	var keyList []leveldb.Key
 	var retKeyList []*wallet.ExportPublicKey
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKeyStr, pubKeyStr, compressPubKeyStr, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKeyStr, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			log.Error("create key fail", "err", err)
+			resp.Message = "create key pair fail"
+			return resp, nil
 		}
-		keyItem := leveldb.Key{
-			PrivateKey: priKeyStr,
-			PublicKey:  pubKeyStr,
-		}
+		pubKeyStr := keyItem.PublicKey
 		pubKeyItem := &wallet.ExportPublicKey{
 			PublicKey:         pubKeyStr,
 			CompressPublicKey: compressPubKeyStr,
@@ -100,7 +115,7 @@ func (c ChainAdaptor) CreateKeyPairsExportPublicKeyList(ctx context.Context, req
 		keyList = append(keyList, keyItem)
 		retKeyList = append(retKeyList, pubKeyItem)
 	}
-	if isOk := c.db.StoreKeys(keyList); !isOk {
+	if isOk := c.db.StoreKeys(ctx, keyList); !isOk {
 		resp.Message = "store keys fail"
 		return resp, nil
 	}
@@ -122,14 +137,13 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 	var keyList []leveldb.Key
 	var retKeyList []*wallet.ExportPublicKeyWithAddress
 	for counter := 0; counter < int(request.KeyNum); counter++ {
-		priKeyStr, pubKeyStr, compressPubKeyStr, err := c.signer.CreateKeyPair()
+		keyItem, compressPubKeyStr, err := c.keySource.CreateKeyPair(ctx, request.Network)
 		if err != nil {
 			log.Error("create key fail", "err", err)
+			resp.Message = "create key pair fail"
+			return resp, nil
 		}
-		keyItem := leveldb.Key{
-			PrivateKey: priKeyStr,
-			PublicKey:  pubKeyStr,
-		}
+		pubKeyStr := keyItem.PublicKey
 
 		address, err := PubKeyHexToAddress(pubKeyStr)
 		if err != nil {
@@ -145,7 +159,7 @@ func (c ChainAdaptor) CreateKeyPairsWithAddresses(ctx context.Context, request *
 		keyList = append(keyList, keyItem)
 		retKeyList = append(retKeyList, pubKeyItem)
 	}
-	if isOk := c.db.StoreKeys(keyList); !isOk {
+	if isOk := c.db.StoreKeys(ctx, keyList); !isOk {
 		resp.Message = "store keys fail"
 		return resp, nil
 	}
@@ -161,14 +175,19 @@ func (c ChainAdaptor) SignTransactionMessage(ctx context.Context, request *walle
 		Code: wallet.ReturnCode_ERROR,
 	}
 
-	privKey, isOk := c.db.GetPrivKey(request.PublicKey)
-	if !isOk {
-		return nil, errors.New("get private key fail")
+	keyRecord, err := chain.LoadSigningKey(ctx, c.db, ChainName, request.Network, request.PublicKey)
+	if err != nil {
+		log.Error("load signing key fail", "err", err)
+		resp.Message = "get private key fail: " + err.Error()
+		return resp, nil
 	}
+	privKey := keyRecord.PrivateKey
 
-	signature, err := c.signer.SignMessage(privKey, request.MessageHash)
+	signature, err := chain.SignMessage(ctx, ChainName, c.signer, privKey, request.MessageHash)
 	if err != nil {
 		log.Error("sign message fail", "err", err)
+		resp.Message = "sign message fail: " + err.Error()
+		return resp, nil
 	}
 
 	resp.Code = wallet.ReturnCode_SUCCESS
@@ -181,131 +200,41 @@ func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wall
 	resp := &wallet.BuildAndSignTransactionResponse{
 		Code: wallet.ReturnCode_ERROR,
 	}
-	//base64 => byte
-	jsonBytes, err := base64.StdEncoding.DecodeString(request.TxBase64Body)
-	if err != nil {
-		resp.Message = "base64 decode fail"
-		return resp, nil
-	}
-	//byte => solanaSchema
-	var data SolanaSchema
-	if err := json.Unmarshal(jsonBytes, &data); err != nil {
-		resp.Message = "json unmarshal fail"
-		return resp, nil
-	}
-	//将value转为十进制，uint64
-	value, _ := strconv.ParseUint(data.Value, 10, 64)
-	//将from地址从base58转为solana.PublicKey类型
-	fromPubKey, err := solana.PublicKeyFromBase58(data.FromAddress)
-	if err != nil {
-		resp.Message = "Failed to parse public key from base58 by from address"
-		return resp, nil
-	}
-	toPubKey, err := solana.PublicKeyFromBase58(data.ToAddress)
+	_, buildSpan := chain.StartPhase(ctx, ChainName, "build")
+	tx, err := buildTransaction(request.TxBase64Body)
+	tracing.End(buildSpan, err)
 	if err != nil {
-		resp.Message = "Failed to parse public key from base58 by to address"
+		resp.Message = err.Error()
 		return resp, nil
 	}
-	//定义solana交易
-	var tx *solana.Transaction
-	//判断是否是sol币交易，如果是就直接构建交易，如果不是，处理代币合约地址
-	if isSOLTransfer(data.ContractAddress) {
-		tx, err = solana.NewTransaction(
-			[]solana.Instruction{
-				system.NewTransferInstruction(
-					value,
-					fromPubKey,
-					toPubKey,
-				).Build(),
-			},
-			solana.MustHashFromBase58(data.Nonce),
-			solana.TransactionPayer(fromPubKey),
-		)
-	} else {
-		mintPubKey := solana.MustPublicKeyFromBase58(data.ContractAddress)
-		fromTokenAccount, _, err := solana.FindAssociatedTokenAddress(
-			fromPubKey,
-			mintPubKey,
-		)
-		if err != nil {
-			resp.Message = "Failed to find associated token address"
-			return resp, nil
-		}
-		toTokenAccount, _, err := solana.FindAssociatedTokenAddress(
-			toPubKey,
-			mintPubKey,
-		)
-		if err != nil {
-			resp.Message = "Failed to find associated token address"
-			return resp, nil
-		}
-		decimals := data.Decimal
-
-		//把value转为float64
-		valueFloat, err := strconv.ParseFloat(data.Value, 64)
-		if err != nil {
-			resp.Message = "Failed to parse value to float"
-			return resp, nil
-		}
-		actualValue := uint64(valueFloat * math.Pow10(int(decimals)))
-
-		transferInstruction := token.NewTransferInstruction(
-			actualValue,
-			fromTokenAccount,
-			toTokenAccount,
-			fromPubKey,
-			[]solana.PublicKey{},
-		).Build()
-		//如果处理有错误，或者在交易体中TokenCreate为true（及在交易体中要求给toAddress创建ATA）时创建ATA
-		if err != nil || data.TokenCreate {
-			createATAInstruction := associatedtokenaccount.NewCreateInstruction(
-				fromPubKey,
-				toPubKey,
-				mintPubKey,
-			).Build()
-			tx, err = solana.NewTransaction(
-				[]solana.Instruction{createATAInstruction, transferInstruction},
-				solana.MustHashFromBase58(data.Nonce),
-				solana.TransactionPayer(fromPubKey),
-			)
-			//否则就直接构建交易
-		} else {
-			tx, err = solana.NewTransaction(
-				[]solana.Instruction{transferInstruction},
-				solana.MustHashFromBase58(data.Nonce),
-				solana.TransactionPayer(fromPubKey),
-			)
-		}
-	}
-	log.Info("Transaction", tx.String())
+	log.Info("Transaction", "instructions", len(tx.Message.Instructions), "accounts", len(tx.Message.AccountKeys))
 	//tx =》 bytes
 	txm, _ := tx.Message.MarshalBinary()
 	//bytes => hex
 	signingMessageHex := hex.EncodeToString(txm)
 
 	log.Info("this is we should use sign message hash", "signingMessageHex", signingMessageHex)
-	priKey, isOk := c.db.GetPrivKey(request.PublicKey)
-	if !isOk {
-		resp.Message = "get private key fail"
+	keyRecord, err := chain.LoadSigningKey(ctx, c.db, ChainName, request.Network, request.PublicKey)
+	if err != nil {
+		log.Error("load signing key fail", "err", err)
+		resp.Message = "get private key fail: " + err.Error()
 		return resp, nil
 	}
-	txSignatures, err := c.signer.SignMessage(priKey, signingMessageHex)
+	priKey := keyRecord.PrivateKey
+	txSignatures, err := chain.SignMessage(ctx, ChainName, c.signer, priKey, signingMessageHex)
 	if err != nil {
-		resp.Message = "sign message fail"
+		log.Error("sign transaction fail", "err", err)
+		resp.Message = "sign message fail: " + err.Error()
 		return resp, nil
 	}
-	if len(txSignatures) == 0 {
-		tx.Signatures = make([]solana.Signature, 1)
-	}
-	if len(txSignatures) != 64 {
+	signatureBytes, err := hex.DecodeString(txSignatures)
+	if err != nil || len(signatureBytes) != solana.SignatureLength {
 		resp.Message = "invalid signature length"
 		return resp, nil
 	}
 	var solanaSig solana.Signature
-	copy(solanaSig[:], txSignatures)
-	tx.Signatures[0] = solanaSig
-	//展示交易，类似logInfo
-	spew.Dump(tx)
+	copy(solanaSig[:], signatureBytes)
+	tx.Signatures = []solana.Signature{solanaSig}
 	if err := tx.VerifySignatures(); err != nil {
 		resp.Message = "verify signatures fail"
 		return resp, nil
@@ -315,16 +244,91 @@ func (c ChainAdaptor) BuildAndSignTransaction(ctx context.Context, request *wall
 		resp.Message = "Failed to serialize transaction"
 		return resp, nil
 	}
-	log.Info("serialized transaction", "serializedTx", serializedTx)
 	base58Tx := base58.Encode(serializedTx)
 	resp.Code = wallet.ReturnCode_SUCCESS
+	resp.Message = "sign whole transaction success"
 	resp.SignedTx = base58Tx
+	resp.TxHash = solanaSig.String()
+	resp.TxMessageHash = signingMessageHex
+	resp.AccountCount = uint64(len(tx.Message.AccountKeys))
 	return resp, nil
 }
 
+// buildTransaction 把交易请求体中的所有 action 组装成一笔未签名交易，错误信息直接返回给调用方
+func buildTransaction(txBase64Body string) (*solana.Transaction, error) {
+	//base64 => byte
+	jsonBytes, err := base64.StdEncoding.DecodeString(txBase64Body)
+	if err != nil {
+		return nil, errors.New("base64 decode fail")
+	}
+	//byte => solanaSchema
+	var data SolanaSchema
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return nil, errors.New("json unmarshal fail")
+	}
+	//将from地址从base58转为solana.PublicKey类型
+	fromPubKey, err := solana.PublicKeyFromBase58(data.FromAddress)
+	if err != nil {
+		return nil, errors.New("Failed to parse public key from base58 by from address")
+	}
+	recentBlockHash, err := solana.HashFromBase58(data.Nonce)
+	if err != nil {
+		return nil, errors.New("Failed to parse nonce to block hash")
+	}
+	instructions, err := buildInstructions(fromPubKey, schemaActions(&data))
+	if err != nil {
+		log.Error("build instructions fail", "err", err)
+		return nil, fmt.Errorf("build instructions fail: %w", err)
+	}
+	tx, err := solana.NewTransaction(
+		instructions,
+		recentBlockHash,
+		solana.TransactionPayer(fromPubKey),
+	)
+	if err != nil {
+		log.Error("new transaction fail", "err", err)
+		return nil, errors.New("new transaction fail")
+	}
+	return tx, nil
+}
+
+// DecodeIntents from_address 是手续费支付方和唯一的签名方，必须是签名公钥对应的地址
+func (c ChainAdaptor) DecodeIntents(publicKey string, txBase64Body string) ([]policy.Intent, error) {
+	jsonBytes, err := base64.StdEncoding.DecodeString(txBase64Body)
+	if err != nil {
+		return nil, err
+	}
+	var data SolanaSchema
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return nil, err
+	}
+	fromPubKey, err := solana.PublicKeyFromBase58(data.FromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	signingAddress, err := PubKeyHexToAddress(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if fromPubKey.String() != signingAddress {
+		return nil, fmt.Errorf("from address %s does not match signing key address %s", fromPubKey, signingAddress)
+	}
+	var intents []policy.Intent
+	for i, action := range schemaActions(&data) {
+		intent, err := actionIntent(fromPubKey, action)
+		if err != nil {
+			return nil, fmt.Errorf("action %d (%s): %w", i, action.Type, err)
+		}
+		intents = append(intents, intent)
+	}
+	return intents, nil
+}
+
 func (c ChainAdaptor) BuildAndSignBatchTransaction(ctx context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error) {
-	//TODO implement me
-	panic("implement me")
+	return &wallet.BuildAndSignBatchTransactionResponse{
+		Code:    wallet.ReturnCode_ERROR,
+		Message: chain.UnsupportedBatchSigning,
+	}, nil
 }
 
 func isSOLTransfer(coinAddress string) bool {
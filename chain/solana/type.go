@@ -1,16 +1,44 @@
 package solana
 
 type SolanaSchema struct {
-	Nonce           string `json:"nonce"`
-	GasPrice        string `json:"gas_price"`
-	GasTipCap       string `json:"gas_tip_cap"`
-	GasFeeCap       string `json:"gas_fee_cap"`
-	Gas             uint64 `json:"gas"`
+	Nonce           string         `json:"nonce"`
+	GasPrice        string         `json:"gas_price"`
+	GasTipCap       string         `json:"gas_tip_cap"`
+	GasFeeCap       string         `json:"gas_fee_cap"`
+	Gas             uint64         `json:"gas"`
+	ContractAddress string         `json:"contract_address"`
+	Decimal         uint8          `json:"decimal"`
+	TokenCreate     bool           `json:"token_create"`
+	FromAddress     string         `json:"from_address"`
+	ToAddress       string         `json:"to_address"`
+	TokenId         string         `json:"token_id"`
+	Value           string         `json:"value"`
+	Actions         []SolanaAction `json:"actions"`
+}
+
+// 交易中的 action 类型，一笔交易可以包含多个 action
+const (
+	ActionSolTransfer     = "sol_transfer"
+	ActionSplTransfer     = "spl_transfer"
+	ActionMemo            = "memo"
+	ActionCreateATA       = "create_ata"
+	ActionStakeCreate     = "stake_create"
+	ActionStakeDelegate   = "stake_delegate"
+	ActionStakeDeactivate = "stake_deactivate"
+	ActionStakeWithdraw   = "stake_withdraw"
+)
+
+// SolanaAction 描述交易中的一个操作，from 地址统一取 SolanaSchema.FromAddress，
+// 它同时是手续费支付者和唯一签名者
+type SolanaAction struct {
+	Type            string `json:"type"`
+	ToAddress       string `json:"to_address"`
 	ContractAddress string `json:"contract_address"`
 	Decimal         uint8  `json:"decimal"`
 	TokenCreate     bool   `json:"token_create"`
-	FromAddress     string `json:"from_address"`
-	ToAddress       string `json:"to_address"`
-	TokenId         string `json:"token_id"`
 	Value           string `json:"value"`
+	Memo            string `json:"memo"`
+	StakeSeed       string `json:"stake_seed"`
+	StakeAccount    string `json:"stake_account"`
+	VoteAccount     string `json:"vote_account"`
 }
@@ -0,0 +1,23 @@
+package chain
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+// StartPhase adaptor 处理阶段的 span，名称为 <链>.<阶段>，例如 Ethereum.build
+func StartPhase(ctx context.Context, chainName string, phase string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, chainName+"."+phase)
+}
+
+// SignMessage 在 <链>.sign span 中签名，签名器支持上下文时 KMS/HSM 调用挂在该 span 下
+func SignMessage(ctx context.Context, chainName string, signer ssm.Signer, priKey string, msg string) (string, error) {
+	ctx, span := StartPhase(ctx, chainName, "sign")
+	signature, err := ssm.SignMessageContext(ctx, signer, priKey, msg)
+	tracing.End(span, err)
+	return signature, err
+}
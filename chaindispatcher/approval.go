@@ -0,0 +1,42 @@
+package chaindispatcher
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+// newApprovalVerifier 按配置创建审批校验器，hmac 密钥从环境变量读取
+func newApprovalVerifier(role string, keys []config.ApprovalKeyConfig) (*riskkey.Verifier, error) {
+	parsed, err := parseApprovalKeys(role, keys)
+	if err != nil {
+		return nil, err
+	}
+	return riskkey.NewVerifier(role, parsed)
+}
+
+func parseApprovalKeys(role string, keys []config.ApprovalKeyConfig) ([]*riskkey.Key, error) {
+	parsed := make([]*riskkey.Key, 0, len(keys))
+	for _, keyConf := range keys {
+		var secret []byte
+		if keyConf.Type == riskkey.TypeHmacSha256 {
+			value := os.Getenv(keyConf.SecretEnv)
+			if value == "" {
+				return nil, fmt.Errorf("%s approval key %s: %s is empty", role, keyConf.Id, keyConf.SecretEnv)
+			}
+			var err error
+			if secret, err = hex.DecodeString(value); err != nil {
+				return nil, fmt.Errorf("%s approval key %s: secret is not hex", role, keyConf.Id)
+			}
+		}
+		key, err := riskkey.NewKey(keyConf.Id, keyConf.Type, secret, keyConf.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, key)
+	}
+	return parsed, nil
+}
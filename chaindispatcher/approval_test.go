@@ -0,0 +1,100 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+func TestBuildAndSignTransactionRequiresApproval(t *testing.T) {
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	body := []byte(`{"chain_id":"1","nonce":0,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+		`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"1"}`)
+	request := func(walletApproval, riskApproval string) *wallet.BuildAndSignTransactionRequest {
+		return &wallet.BuildAndSignTransactionRequest{
+			ConsumerToken: "token",
+			ChainName:     ethereum.ChainName,
+			PublicKey:     keys.PublicKeyAddresses[0].PublicKey,
+			WalletKeyHash: walletApproval,
+			RiskKeyHash:   riskApproval,
+			TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+		}
+	}
+
+	walletApproval := riskkey.HmacApproval("w1", walletSecret, body)
+	riskApproval := riskkey.HmacApproval("r1", riskSecret, body)
+	resp, err := d.BuildAndSignTransaction(ctx, request(walletApproval, riskApproval))
+	if err != nil || resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("approved request = %v, err %v", resp, err)
+	}
+	// 审批互换、用错密钥都应被拒绝
+	for _, r := range []*wallet.BuildAndSignTransactionRequest{
+		request(riskApproval, walletApproval),
+		request(walletApproval, riskkey.HmacApproval("r1", walletSecret, body)),
+		request(walletApproval, ""),
+	} {
+		resp, err := d.BuildAndSignTransaction(ctx, r)
+		if err != nil || resp.Code != wallet.ReturnCode_ERROR || resp.SignedTx != "" {
+			t.Fatalf("unapproved request = %v, err %v", resp, err)
+		}
+	}
+
+	// 批量签名同样检查每一笔的审批，任何一笔未审批时整批拒绝
+	d.registry[ethereum.ChainName] = batchAdaptor{d.registry[ethereum.ChainName]}
+	batch, err := d.BuildAndSignBatchTransaction(ctx, &wallet.BuildAndSignBatchTransactionRequest{
+		ConsumerToken: "token",
+		ChainName:     ethereum.ChainName,
+		TxMsg: []*wallet.TransactionMessage{{
+			PublicKey:     keys.PublicKeyAddresses[0].PublicKey,
+			WalletKeyHash: walletApproval,
+			RiskKeyHash:   "",
+			TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+		}},
+	})
+	if err != nil || batch.Code != wallet.ReturnCode_ERROR || len(batch.TxWithSign) != 0 {
+		t.Fatalf("unapproved batch = %v, err %v", batch, err)
+	}
+}
@@ -0,0 +1,141 @@
+package chaindispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/metrics"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/velocity"
+)
+
+var (
+	errAuditFailed = errors.New("write audit log fail")
+	// errSignPanic adaptor 在签名时 panic，返回给调用方的信息不包含内部细节
+	errSignPanic = errors.New("sign fail: internal error")
+)
+
+// writeAudit 写入一条审计记录，没有配置审计日志时不记录
+func (d *ChainDispatcher) writeAudit(ctx context.Context, entry *audit.Entry) error {
+	if d.audit == nil {
+		return nil
+	}
+	entry.Consumer = consumer.FromContext(ctx)
+	if err := d.audit.Append(entry); err != nil {
+		log.Error("write audit log fail", "method", entry.Method, "err", err)
+		metrics.ObserveAuditFailure(entry.Method)
+		return errAuditFailed
+	}
+	return nil
+}
+
+// auditResult 按结果填写审计记录的决定和原因，签名成功时记录签名结果的 hash
+func auditResult(entry *audit.Entry, code wallet.ReturnCode, message string, reason string, err error, signatures ...string) {
+	switch {
+	case err != nil:
+		entry.Decision, entry.Reason = audit.DecisionDenied, err.Error()
+	case code != wallet.ReturnCode_SUCCESS:
+		entry.Decision, entry.Reason = audit.DecisionDenied, message
+		if reason != "" {
+			entry.Reason = reason + ": " + message
+		}
+	default:
+		entry.Decision = audit.DecisionAllowed
+		entry.SignatureHash = audit.HashSignatures(signatures...)
+	}
+}
+
+type auditedResponse interface {
+	signingResponse
+	GetMessage() string
+	GetRejectReason() string
+}
+
+// reservations 一次签名请求占用的 nonce 和限额，审计记录写入后按最终结果结算
+type reservations struct {
+	nonces   *nonceReservation
+	velocity []*velocity.Reservation
+}
+
+// settle 最终返回签名时记录 nonce 并保留限额，否则全部释放，调用方可以重试
+func (r *reservations) settle(ctx context.Context, signed bool) {
+	settleBatchVelocity(r.velocity, signed)
+	r.nonces.settle(ctx, signed)
+}
+
+// audited 记录一次签名请求的结果。签名成功但审计记录写入失败时不返回签名。
+// sign 把占用的 nonce 和限额放进 held，只有最终返回成功时才保留；sign panic 时按失败记录并归还
+func audited[T auditedResponse](ctx context.Context, d *ChainDispatcher, entry audit.Entry, fail func(message string, reason string) T,
+	signatures func(T) []string, sign func(held *reservations) (T, error)) (resp T, err error) {
+	held := &reservations{}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("sign panic", "method", entry.Method, "chain", entry.Chain, "panic", r, "stack", string(debug.Stack()))
+			resp, err = fail(errSignPanic.Error(), ""), nil
+		}
+		var sigs []string
+		if err == nil && resp.GetCode() == wallet.ReturnCode_SUCCESS {
+			sigs = signatures(resp)
+		}
+		auditResult(&entry, resp.GetCode(), resp.GetMessage(), resp.GetRejectReason(), err, sigs...)
+		if auditErr := d.writeAudit(ctx, &entry); auditErr != nil && entry.Decision == audit.DecisionAllowed {
+			held.settle(ctx, false)
+			resp, err = fail(auditErr.Error(), ""), nil
+			return
+		}
+		held.settle(ctx, entry.Decision == audit.DecisionAllowed)
+	}()
+	return sign(held)
+}
+
+// auditKeys 记录密钥的创建、导入、导出、停用和删除，每个公钥一条，失败且没有公钥时记录一条。
+// 返回第一个写入失败的错误，失败同时由 writeAudit 计入 audit_write_failures_total
+func (d *ChainDispatcher) auditKeys(ctx context.Context, method string, chainName string, code wallet.ReturnCode, message string, err error, publicKeys []string) error {
+	if len(publicKeys) == 0 {
+		if code == wallet.ReturnCode_SUCCESS && err == nil {
+			return nil
+		}
+		publicKeys = []string{""}
+	}
+	var auditErr error
+	for _, publicKey := range publicKeys {
+		entry := &audit.Entry{Chain: chainName, PublicKey: publicKey, Method: method}
+		auditResult(entry, code, message, "", err)
+		if writeErr := d.writeAudit(ctx, entry); writeErr != nil && auditErr == nil {
+			auditErr = writeErr
+		}
+	}
+	return auditErr
+}
+
+// intentSummary 交易的目的地址、资产和金额，多笔交易用 | 分隔
+func (d *ChainDispatcher) intentSummary(chainName string, txMsgs ...*wallet.TransactionMessage) string {
+	summaries := make([]string, 0, len(txMsgs))
+	for _, txMsg := range txMsgs {
+		intents, err := d.registry[chainName].DecodeIntents(txMsg.PublicKey, txMsg.TxBase64Body)
+		if err != nil {
+			summaries = append(summaries, "undecodable")
+			continue
+		}
+		parts := make([]string, 0, len(intents))
+		for _, intent := range intents {
+			part := fmt.Sprintf("to=%s asset=%s amount=%s", intent.To, intent.Asset, intent.Amount)
+			if intent.Method != "" {
+				part += " method=" + intent.Method
+			}
+			if intent.Nonce != "" {
+				part += " nonce=" + intent.Nonce
+			}
+			parts = append(parts, part)
+		}
+		summaries = append(summaries, strings.Join(parts, "; "))
+	}
+	return strings.Join(summaries, " | ")
+}
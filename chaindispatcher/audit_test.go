@@ -0,0 +1,110 @@
+package chaindispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+func TestAuditLog(t *testing.T) {
+	conf := &config.Config{Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}}}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "audit.log")
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.audit, err = audit.Open(path); err != nil {
+		t.Fatal(err)
+	}
+	defer d.audit.Close()
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 2})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	publicKey := keys.PublicKeyAddresses[0].PublicKey
+	messageHash := hex.EncodeToString(crypto.Keccak256([]byte("tx")))
+	for _, key := range []string{publicKey, "04unknown"} {
+		if _, err := d.SignTransactionMessage(ctx, &wallet.SignTransactionMessageRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: key, MessageHash: messageHash}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 导出、停用和删除密钥同样记录成功和失败
+	adminHash := sha256.Sum256([]byte("admin"))
+	d.adminTokenHash = hex.EncodeToString(adminHash[:])
+	exportRequest := &wallet.ExportKeysRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, AdminToken: "wrong", PublicKeys: []string{publicKey}, Password: "backup"}
+	if resp, _ := d.ExportKeys(ctx, exportRequest); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatalf("export with a wrong admin token = %v", resp)
+	}
+	exportRequest.AdminToken = "admin"
+	if resp, _ := d.ExportKeys(ctx, exportRequest); resp.Code != wallet.ReturnCode_SUCCESS || len(resp.Backup) == 0 {
+		t.Fatalf("export = %v", resp)
+	}
+	otherKey := keys.PublicKeyAddresses[1].PublicKey
+	if resp, _ := d.DisableKey(ctx, &wallet.DisableKeyRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: otherKey}); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("disable = %v", resp)
+	}
+	issued, _ := d.DeleteKey(ctx, &wallet.DeleteKeyRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: otherKey})
+	if resp, _ := d.DeleteKey(ctx, &wallet.DeleteKeyRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: otherKey, ConfirmToken: issued.ConfirmToken}); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("delete = %v", resp)
+	}
+	if resp, _ := d.DeleteKey(ctx, &wallet.DeleteKeyRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: otherKey, ConfirmToken: issued.ConfirmToken}); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatalf("delete twice = %v", resp)
+	}
+
+	data, _ := os.ReadFile(path)
+	count, _, err := audit.Verify(strings.NewReader(string(data)))
+	if err != nil || count != 9 {
+		t.Fatalf("verify: count %d, err %v\n%s", count, err, data)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i, want := range []string{
+		`"method":"createKeyPairsWithAddresses","decision":"allowed"`,
+		`"method":"createKeyPairsWithAddresses","decision":"allowed"`,
+		`"method":"signTransactionMessage","intent":"message_hash=` + messageHash + `","decision":"allowed","signature_hash":`,
+		`"public_key":"04unknown","method":"signTransactionMessage","intent":"message_hash=` + messageHash + `","decision":"denied"`,
+		`"public_key":"` + publicKey + `","method":"exportKeys","decision":"denied","reason":"invalid admin token"`,
+		`"public_key":"` + publicKey + `","method":"exportKeys","decision":"allowed"`,
+		`"public_key":"` + otherKey + `","method":"disableKey","decision":"allowed"`,
+		`"public_key":"` + otherKey + `","method":"deleteKey","decision":"allowed"`,
+		`"public_key":"` + otherKey + `","method":"deleteKey","decision":"denied"`,
+	} {
+		if !strings.Contains(lines[i], `"consumer":"exchange"`) || !strings.Contains(lines[i], want) {
+			t.Fatalf("entry %d = %s, want %s", i, lines[i], want)
+		}
+	}
+	// 审计日志只记录公钥和签名的 hash
+	if strings.Contains(string(data), "private_key") {
+		t.Fatal("audit log contains key material")
+	}
+
+	// 审计日志写入失败时不返回私钥备份
+	d.audit.Close()
+	if resp, _ := d.ExportKeys(ctx, exportRequest); resp.Code != wallet.ReturnCode_ERROR || len(resp.Backup) != 0 {
+		t.Fatalf("export without audit = %v", resp)
+	}
+}
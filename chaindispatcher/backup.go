@@ -0,0 +1,256 @@
+package chaindispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/keybackup"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tss"
+)
+
+// ImportOptions 导入私钥的参数，Keys 中每一项是一个私钥（backup 格式时是一个备份文件）
+type ImportOptions struct {
+	Consumer string
+	Network  string
+	Format   string
+	Keys     []string
+	Password string
+	Label    string
+}
+
+// ImportKeys 解码并校验所有私钥后一次性写入，任意一个失败则全部不写入；已存在的公钥不会被覆盖
+func ImportKeys(ctx context.Context, db *leveldb.Keys, chainName string, opts ImportOptions) ([]*leveldb.Key, error) {
+	curve, ok := chainCurves[chainName]
+	if !ok {
+		return nil, errors.New("unsupported chain")
+	}
+	importer, ok := chainSigners[chainName].(ssm.KeyImporter)
+	if !ok {
+		return nil, fmt.Errorf("chain %s does not support key import", chainName)
+	}
+	if len(opts.Keys) == 0 {
+		return nil, errors.New("no keys to import")
+	}
+
+	var entries []keybackup.Entry
+	for i, input := range opts.Keys {
+		if opts.Format != keybackup.FormatBackup {
+			priKey, err := keybackup.DecodePrivateKey(opts.Format, input, opts.Password)
+			if err != nil {
+				return nil, fmt.Errorf("key %d: %w", i, err)
+			}
+			entries = append(entries, keybackup.Entry{PrivateKey: priKey})
+			continue
+		}
+		backupEntries, err := keybackup.Open([]byte(input), opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range backupEntries {
+			if entry.Chain != chainName {
+				return nil, fmt.Errorf("backup key %s belongs to chain %s", entry.PublicKey, entry.Chain)
+			}
+		}
+		entries = append(entries, backupEntries...)
+	}
+
+	now := time.Now().Unix()
+	seen := make(map[string]bool)
+	records := make([]leveldb.Key, 0, len(entries))
+	for i, entry := range entries {
+		priKey, pubKey, _, err := importer.ImportKeyPair(entry.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i, err)
+		}
+		if entry.PublicKey != "" && entry.PublicKey != pubKey {
+			return nil, fmt.Errorf("key %d: public key does not match private key", i)
+		}
+		if seen[pubKey] {
+			return nil, fmt.Errorf("key %d: duplicate public key %s", i, pubKey)
+		}
+		seen[pubKey] = true
+		if _, err := db.GetKey(ctx, chainName, pubKey); err == nil {
+			return nil, fmt.Errorf("key %d: public key %s already exists", i, pubKey)
+		} else if !errors.Is(err, leveldb.ErrKeyNotFound) {
+			return nil, err
+		}
+		record := leveldb.Key{
+			Chain:          chainName,
+			Curve:          curve,
+			Network:        opts.Network,
+			Consumer:       opts.Consumer,
+			PublicKey:      pubKey,
+			PrivateKey:     priKey,
+			CreatedAt:      now,
+			Label:          opts.Label,
+			DerivationPath: entry.DerivationPath,
+		}
+		// 从备份恢复时保留原来的元数据
+		if record.Network == "" {
+			record.Network = entry.Network
+		}
+		if record.Label == "" {
+			record.Label = entry.Label
+		}
+		records = append(records, record)
+	}
+	if !db.StoreKeys(ctx, records) {
+		return nil, errors.New("store keys fail")
+	}
+	imported := make([]*leveldb.Key, 0, len(records))
+	for i := range records {
+		imported = append(imported, &records[i])
+	}
+	return imported, nil
+}
+
+// ExportKeys 把 consumer 名下的指定私钥导出为口令加密的备份文件
+func ExportKeys(ctx context.Context, db *leveldb.Keys, chainName string, consumerName string, publicKeys []string, password string) ([]byte, error) {
+	if len(publicKeys) == 0 {
+		return nil, errors.New("no keys to export")
+	}
+	entries := make([]keybackup.Entry, 0, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		record, err := db.GetKey(ctx, chainName, publicKey)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrKeyNotFound) {
+				return nil, fmt.Errorf("%w: %s", chain.ErrKeyNotFound, publicKey)
+			}
+			return nil, err
+		}
+		if record.Consumer != consumerName {
+			return nil, fmt.Errorf("%w: %s", chain.ErrKeyNotOwned, publicKey)
+		}
+		if strings.HasPrefix(record.PrivateKey, tss.KeyIDPrefix) || strings.HasPrefix(record.PrivateKey, tss.FrostKeyIDPrefix) {
+			return nil, fmt.Errorf("key %s is a threshold key and has no exportable private key", publicKey)
+		}
+		priKey, err := hex.DecodeString(record.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("decode private key %s: %w", publicKey, err)
+		}
+		entries = append(entries, keybackup.Entry{
+			Chain:          record.Chain,
+			Curve:          record.Curve,
+			Network:        record.Network,
+			PublicKey:      record.PublicKey,
+			Label:          record.Label,
+			DerivationPath: record.DerivationPath,
+			PrivateKey:     priKey,
+		})
+	}
+	return keybackup.Seal(entries, password)
+}
+
+// checkAdminToken 用 sha256 摘要做常量时间比较，未配置 admin_token_hash 时管理接口关闭
+func (d *ChainDispatcher) checkAdminToken(token string) error {
+	if d.adminTokenHash == "" {
+		return errors.New("admin rpc is disabled")
+	}
+	want, err := hex.DecodeString(strings.TrimSpace(d.adminTokenHash))
+	if err != nil {
+		return errors.New("invalid admin token hash config")
+	}
+	got := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare(got[:], want) != 1 {
+		return errors.New("invalid admin token")
+	}
+	return nil
+}
+
+func (d *ChainDispatcher) ImportKeys(ctx context.Context, request *wallet.ImportKeysRequest) (*wallet.ImportKeysResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ImportKeysResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if err := d.checkAdminToken(request.AdminToken); err != nil {
+		d.auditKeys(ctx, "importKeys", request.ChainName, wallet.ReturnCode_ERROR, "", err, nil)
+		return &wallet.ImportKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	records, err := ImportKeys(ctx, d.db, request.ChainName, ImportOptions{
+		Consumer: consumer.FromContext(ctx),
+		Network:  request.Network,
+		Format:   request.Format,
+		Keys:     request.Keys,
+		Password: request.Password,
+		Label:    request.Label,
+	})
+	if err != nil {
+		log.Error("import keys fail", "chain", request.ChainName, "err", err)
+		d.auditKeys(ctx, "importKeys", request.ChainName, wallet.ReturnCode_ERROR, "", err, nil)
+		return &wallet.ImportKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "import keys fail: " + err.Error(),
+		}, nil
+	}
+	keys := make([]*wallet.KeyInfo, 0, len(records))
+	publicKeys := make([]string, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, toKeyInfo(record))
+		publicKeys = append(publicKeys, record.PublicKey)
+	}
+	d.auditKeys(ctx, "importKeys", request.ChainName, wallet.ReturnCode_SUCCESS, "", nil, publicKeys)
+	log.Info("import keys success", "chain", request.ChainName, "count", len(keys))
+	return &wallet.ImportKeysResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "import keys success",
+		Keys:    keys,
+	}, nil
+}
+
+func (d *ChainDispatcher) ExportKeys(ctx context.Context, request *wallet.ExportKeysRequest) (*wallet.ExportKeysResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ExportKeysResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if err := d.checkAdminToken(request.AdminToken); err != nil {
+		d.auditKeys(ctx, "exportKeys", request.ChainName, wallet.ReturnCode_ERROR, "", err, request.PublicKeys)
+		return &wallet.ExportKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	backup, err := ExportKeys(ctx, d.db, request.ChainName, consumer.FromContext(ctx), request.PublicKeys, request.Password)
+	if err != nil {
+		log.Error("export keys fail", "chain", request.ChainName, "err", err)
+		d.auditKeys(ctx, "exportKeys", request.ChainName, wallet.ReturnCode_ERROR, "", err, request.PublicKeys)
+		return &wallet.ExportKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "export keys fail: " + err.Error(),
+		}, nil
+	}
+	// 私钥备份与签名一样，没有审计记录时不返回
+	if err := d.auditKeys(ctx, "exportKeys", request.ChainName, wallet.ReturnCode_SUCCESS, "", nil, request.PublicKeys); err != nil {
+		return &wallet.ExportKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	log.Info("export keys success", "chain", request.ChainName, "count", len(request.PublicKeys))
+	return &wallet.ExportKeysResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "export keys success",
+		Backup:  backup,
+	}, nil
+}
@@ -0,0 +1,71 @@
+package chaindispatcher
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// loadConsumers 合并配置文件和数据库中的调用方，同名调用方视为配置错误
+func loadConsumers(conf *config.Config, db *leveldb.Keys) (*consumer.Registry, error) {
+	stored, err := db.ListConsumers()
+	if err != nil {
+		return nil, err
+	}
+	consumers := append(append([]consumer.Consumer(nil), conf.Consumers...), stored...)
+	registry, err := consumer.NewRegistry(consumers)
+	if err != nil {
+		return nil, err
+	}
+	if len(consumers) == 0 {
+		log.Warn("no consumer configured, all rpc calls will be rejected")
+	}
+	return registry, nil
+}
+
+// authenticate 优先按经过校验的客户端证书认证，证书没有映射到调用方时使用 token；
+// 同时带了证书和 token 时两者必须属于同一个调用方
+func (d *ChainDispatcher) authenticate(ctx context.Context, token string) (*consumer.Consumer, error) {
+	cert := peerCertificate(ctx)
+	if cert == nil {
+		return d.consumers.Authenticate(token)
+	}
+	c, err := d.consumers.AuthenticateCertificate(cert)
+	if errors.Is(err, consumer.ErrUnknownCertificate) {
+		return d.consumers.Authenticate(token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		byToken, err := d.consumers.Authenticate(token)
+		if err != nil {
+			return nil, err
+		}
+		if byToken.Name != c.Name {
+			return nil, consumer.ErrIdentityMismatch
+		}
+	}
+	return c, nil
+}
+
+// peerCertificate 返回 TLS 握手时校验过的客户端证书，没有时返回 nil
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
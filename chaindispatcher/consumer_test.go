@@ -0,0 +1,118 @@
+package chaindispatcher
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+func TestPreHandlerAuthenticatesConsumer(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutConsumer(&consumer.Consumer{Name: "stored", TokenHash: consumer.HashToken("stored-token"), Chains: []string{"Solana"}}); err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.Config{Consumers: []consumer.Consumer{
+		{Name: "exchange", TokenHash: consumer.HashToken("exchange-token")},
+		{Name: "signer-only", TokenHash: consumer.HashToken("signer-token"), Methods: []string{"signTransactionMessage"}},
+	}}
+	registry, err := loadConsumers(conf, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db, consumers: registry}
+
+	ctx, resp := d.preHandler(context.Background(), &wallet.ChainSignMethodRequest{ConsumerToken: "exchange-token", ChainName: ethereum.ChainName})
+	if resp != nil {
+		t.Fatalf("exchange rejected: %s", resp.Message)
+	}
+	if c, ok := consumer.ConsumerFromContext(ctx); !ok || c.Name != "exchange" || consumer.FromContext(ctx) != "exchange" {
+		t.Fatal("consumer identity missing from the context")
+	}
+
+	rejected := map[string]string{
+		"wrong-token":  "unknown token",
+		"stored-token": "chain not allowed",
+		// 直接调用没有 gRPC 方法名，限制了方法的调用方不能通过
+		"signer-token": "method unknown",
+	}
+	for token, reason := range rejected {
+		_, resp := d.preHandler(context.Background(), &wallet.ChainSignMethodRequest{ConsumerToken: token, ChainName: ethereum.ChainName})
+		if resp == nil || resp.Code != wallet.ReturnCode_ERROR {
+			t.Fatalf("%s: expected rejection", reason)
+		}
+	}
+}
+
+func TestPreHandlerAuthenticatesClientCertificate(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.Config{Consumers: []consumer.Consumer{
+		{Name: "exchange", CertSubjects: []string{"CN=exchange,O=Acme"}},
+		{Name: "payroll", TokenHash: consumer.HashToken("payroll-token"), CertSubjects: []string{"payroll"}},
+	}}
+	registry, err := loadConsumers(conf, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db, consumers: registry}
+	withCert := func(subject pkix.Name) context.Context {
+		cert := &x509.Certificate{Subject: subject}
+		state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+	}
+
+	cases := []struct {
+		subject pkix.Name
+		token   string
+		want    string
+	}{
+		{pkix.Name{CommonName: "exchange", Organization: []string{"Acme"}}, "", "exchange"},
+		// 按 CN 映射
+		{pkix.Name{CommonName: "payroll", Organization: []string{"Other"}}, "payroll-token", "payroll"},
+		// 证书没有映射时使用 token
+		{pkix.Name{CommonName: "unknown"}, "payroll-token", "payroll"},
+	}
+	for _, c := range cases {
+		ctx, resp := d.preHandler(withCert(c.subject), &wallet.ChainSignMethodRequest{ConsumerToken: c.token, ChainName: ethereum.ChainName})
+		if resp != nil {
+			t.Fatalf("%s rejected: %s", c.subject, resp.Message)
+		}
+		if got := consumer.FromContext(ctx); got != c.want {
+			t.Fatalf("%s: consumer %s, want %s", c.subject, got, c.want)
+		}
+	}
+
+	// 证书和 token 属于不同调用方
+	_, resp := d.preHandler(withCert(pkix.Name{CommonName: "exchange", Organization: []string{"Acme"}}), &wallet.ChainSignMethodRequest{ConsumerToken: "payroll-token", ChainName: ethereum.ChainName})
+	if resp == nil || resp.Message != consumer.ErrIdentityMismatch.Error() {
+		t.Fatalf("expected identity mismatch, got %v", resp)
+	}
+	if _, resp := d.preHandler(withCert(pkix.Name{CommonName: "unknown"}), &wallet.ChainSignMethodRequest{ChainName: ethereum.ChainName}); resp == nil {
+		t.Fatal("expected an unmapped certificate without token to be rejected")
+	}
+}
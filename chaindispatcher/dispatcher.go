@@ -3,31 +3,39 @@ package chaindispatcher
 import (
 	"context"
 	"encoding/base64"
-	"github.com/status-im/keycard-go/hexutils"
+	"fmt"
 	"runtime/debug"
 	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/0xshin-chan/wallet-sign/audit"
 	"github.com/0xshin-chan/wallet-sign/chain"
 	"github.com/0xshin-chan/wallet-sign/chain/bitcoin"
 	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
 	"github.com/0xshin-chan/wallet-sign/chain/solana"
+	"github.com/0xshin-chan/wallet-sign/common/redact"
 	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
 	"github.com/0xshin-chan/wallet-sign/hsm"
 	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/metrics"
+	"github.com/0xshin-chan/wallet-sign/policy"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
-)
-
-const (
-	AccessToken = "slim"
-	WalletKey   = "wallet key 111"
-	RiskKey     = "risk key 111"
+	"github.com/0xshin-chan/wallet-sign/quorum"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+	"github.com/0xshin-chan/wallet-sign/velocity"
 )
 
 type CommonRequest interface {
@@ -37,16 +45,47 @@ type CommonRequest interface {
 
 type CommonReply = wallet.ChainSignMethodResponse
 
+// chainCurves 各链密钥使用的曲线
+var chainCurves = map[string]string{
+	bitcoin.ChainName:  ssm.CurveSecp256k1,
+	ethereum.ChainName: ssm.CurveSecp256k1,
+	solana.ChainName:   ssm.CurveEd25519,
+}
+
+// ChainCurve 返回链使用的曲线
+func ChainCurve(chainName string) (string, bool) {
+	curve, ok := chainCurves[chainName]
+	return curve, ok
+}
+
+// chainSigners 各链使用的本地签名器，导入私钥时用来校验曲线
+var chainSigners = map[string]ssm.Signer{
+	bitcoin.ChainName:  &ssm.ECDSASigner{},
+	ethereum.ChainName: &ssm.ECDSASigner{},
+	solana.ChainName:   &ssm.EdDSASigner{},
+}
+
 type ChainDispatcher struct {
-	registry map[string]chain.IChainAdaptor
+	registry       map[string]chain.IChainAdaptor
+	db             *leveldb.Keys
+	adminTokenHash string
+	consumers      *consumer.Registry
+	walletKeys     *riskkey.Verifier
+	riskKeys       *riskkey.Verifier
+	policy         *policy.Engine
+	limiter        *velocity.Limiter
+	quorum         *quorum.Workflow
+	replay         *replayGuard
+	audit          *audit.Log
 }
 
 func NewChainDispatcher(conf *config.Config) (*ChainDispatcher, error) {
 	dispatcher := ChainDispatcher{
-		registry: make(map[string]chain.IChainAdaptor),
+		registry:       make(map[string]chain.IChainAdaptor),
+		adminTokenHash: conf.AdminTokenHash,
 	}
 
-	chainAdaptorFactoryMap := map[string]func(conf *config.Config, db *leveldb.Keys, hsmCli *hsm.HsmClient) (chain.IChainAdaptor, error){
+	chainAdaptorFactoryMap := map[string]func(conf *config.Config, db *leveldb.Keys, signer ssm.Signer) (chain.IChainAdaptor, error){
 		bitcoin.ChainName:  bitcoin.NewChainAdaptor,
 		ethereum.ChainName: ethereum.NewChainAdaptor,
 		solana.ChainName:   solana.NewChainAdaptor,
@@ -57,25 +96,99 @@ func NewChainDispatcher(conf *config.Config) (*ChainDispatcher, error) {
 		solana.ChainName,
 	}
 
-	db, err := leveldb.NewKeyStore(conf.LevelDbPath)
+	db, err := leveldb.OpenKeyStore(conf)
 	if err != nil {
 		log.Error("new key store level db", "err", err)
+		return nil, err
 	}
-	var hsmClient *hsm.HsmClient
-	var errHsmCli error
-
+	dispatcher.db = db
+	metrics.SetKeyStore(db.CountKeys)
+	if dispatcher.consumers, err = loadConsumers(conf, db); err != nil {
+		log.Error("load consumers fail", "err", err)
+		return nil, err
+	}
+	if dispatcher.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		log.Error("load wallet approval keys fail", "err", err)
+		return nil, err
+	}
+	if dispatcher.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		log.Error("load risk approval keys fail", "err", err)
+		return nil, err
+	}
+	if conf.PolicyFile != "" {
+		if dispatcher.policy, err = policy.NewEngine(conf.PolicyFile); err != nil {
+			log.Error("load transaction policy fail", "err", err)
+			return nil, err
+		}
+	}
+	if dispatcher.limiter, err = newLimiter(db, conf.VelocityLimits); err != nil {
+		log.Error("load velocity limits fail", "err", err)
+		return nil, err
+	}
+	if dispatcher.quorum, err = newQuorumWorkflow(db, conf.MultiApproval); err != nil {
+		log.Error("load multi-party approval fail", "err", err)
+		return nil, err
+	}
+	dispatcher.replay = newReplayGuard(db, conf.IdempotencyTtl)
+	if conf.AuditLog != "" {
+		if dispatcher.audit, err = audit.Open(conf.AuditLog); err != nil {
+			log.Error("open audit log fail", "err", err)
+			return nil, err
+		}
+	}
+	// 按链替换默认的本地签名器，未设置的链由 adaptor 使用本地签名器；同时开启时 FROST 优先于门限 ECDSA
+	signers := make(map[string]ssm.Signer)
+	if conf.Tss.Enabled {
+		tssSigner, err := newTssSigner(conf.Tss, db)
+		if err != nil {
+			log.Error("new tss signer fail", "err", err)
+			return nil, err
+		}
+		for chainName, curve := range chainCurves {
+			if curve == ssm.CurveSecp256k1 {
+				signers[chainName] = tssSigner
+			}
+		}
+	}
+	if conf.Frost.Enabled {
+		frostSigners, err := newFrostSigners(conf.Frost, db)
+		if err != nil {
+			log.Error("new frost signer fail", "err", err)
+			return nil, err
+		}
+		for chainName, signer := range frostSigners {
+			signers[chainName] = signer
+		}
+	}
+	// HSM 模式下新密钥在后端中生成，原有的签名器作为 fallback 处理开启前的密钥
 	if conf.HsmEnabled {
-		hsmClient, errHsmCli = hsm.NewHSMClient(context.Background(), conf.KeyPath, conf.KeyName)
-		if errHsmCli != nil {
-			log.Error("new hsm client fail", "err", errHsmCli)
-			return nil, errHsmCli
+		backends := make(map[string]hsm.Backend)
+		for chainName, curve := range chainCurves {
+			name := chainHsmBackend(conf, chainName)
+			backend, ok := backends[name]
+			if !ok {
+				if backend, err = newHsmBackend(conf, name); err != nil {
+					log.Error("new hsm backend fail", "backend", name, "err", err)
+					return nil, err
+				}
+				backend = hsm.Instrument(backend)
+				backends[name] = backend
+			}
+			fallback, ok := signers[chainName]
+			if !ok {
+				fallback = chainSigners[chainName]
+			}
+			signers[chainName] = hsm.NewSigner(backend, curve, fallback)
+			log.Info("hsm signer enabled", "chain", chainName, "backend", backend.Name())
 		}
 	}
 	for _, chainName := range conf.Chains {
 		if factory, ok := chainAdaptorFactoryMap[chainName]; ok {
-			adaptor, err := factory(conf, db, hsmClient)
+			adaptor, err := factory(conf, db, signers[chainName])
+			// 配置的链不可用时拒绝启动，避免请求落到空的 adaptor 上
 			if err != nil {
 				log.Error("failed setup chain", "chain", chainName, "err", err)
+				return nil, fmt.Errorf("setup chain %s: %w", chainName, err)
 			}
 			dispatcher.registry[chainName] = adaptor
 		} else {
@@ -98,37 +211,106 @@ func (d *ChainDispatcher) Interceptor(ctx context.Context, req interface{}, info
 	method := info.FullMethod[pos+1:]
 
 	chainName := req.(CommonRequest).GetChainName()
-	log.Info(method, "chain", chainName, "req", req)
+	log.Info(method, "chain", chainName, "req", loggable(req))
 
+	// 继续上游传入的 trace，adaptor、key store 和 KMS 的 span 都挂在这个 span 下
+	ctx, span := tracing.StartServer(tracing.Extract(ctx), info.FullMethod,
+		attribute.String("rpc.system", "grpc"), attribute.String("rpc.method", method), attribute.String("wallet.chain", chainName))
+	start := time.Now()
+	defer func() {
+		code, reason := d.observe(method, chainName, req, resp, err, time.Since(start))
+		endRequestSpan(span, code, reason, err)
+	}()
 	resp, err = handler(ctx, req)
-	log.Debug("finish handling", "resp", resp, "err", err)
+	log.Debug("finish handling", "resp", loggable(resp), "err", err)
 	return
 }
 
-func (d *ChainDispatcher) preHandler(req interface{}) (resp *CommonReply) {
+type codedResponse interface {
+	GetCode() wallet.ReturnCode
+}
+
+type rejectedResponse interface {
+	GetRejectReason() string
+}
+
+// observe 记录请求指标，返回结果码和拒绝原因。未配置的链统一记为 unknown，避免调用方传入的任意链名产生大量时间序列
+func (d *ChainDispatcher) observe(method string, chainName string, req interface{}, resp interface{}, err error, elapsed time.Duration) (string, string) {
+	if _, ok := d.registry[chainName]; !ok {
+		chainName = "unknown"
+	}
+	code, reason := metrics.ResultError, ""
+	if err != nil {
+		code = status.Code(err).String()
+	} else if r, ok := resp.(codedResponse); ok {
+		code = r.GetCode().String()
+	}
+	metrics.ObserveRequest(method, chainName, code, elapsed)
+	if r, ok := resp.(rejectedResponse); ok && err == nil && r.GetRejectReason() != "" {
+		reason = r.GetRejectReason()
+		metrics.ObserveRejection(method, chainName, reason)
+	}
+	if batch, ok := req.(*wallet.BuildAndSignBatchTransactionRequest); ok {
+		metrics.ObserveBatchSize(chainName, len(batch.GetTxMsg()))
+	}
+	return code, reason
+}
+
+// endRequestSpan 在请求 span 上记录结果码，非 SUCCESS 的请求标记为错误
+func endRequestSpan(span trace.Span, code string, reason string, err error) {
+	span.SetAttributes(attribute.String("wallet.code", code))
+	if reason != "" {
+		span.SetAttributes(attribute.String("wallet.reject_reason", reason))
+	}
+	if err == nil && code != wallet.ReturnCode_SUCCESS.String() {
+		span.SetStatus(otelcodes.Error, code)
+	}
+	tracing.End(span, err)
+}
+
+// loggable 请求和响应只输出摘要，token、私钥、口令和审批 hash 不进入日志
+func loggable(v interface{}) interface{} {
+	if m, ok := v.(proto.Message); ok {
+		return redact.Message(m)
+	}
+	return v
+}
+
+// preHandler 认证调用方并检查链和方法权限，返回带有调用方身份的上下文
+func (d *ChainDispatcher) preHandler(ctx context.Context, req interface{}) (context.Context, *CommonReply) {
 	// proto 生成的 Go struct 已经实现了接口，因为生成的代码里自带了 GetConsumerToken() 和 GetChainName() 方法。
-	consumerToken := req.(CommonRequest).GetConsumerToken()
-	log.Debug("consumer token", "consumerToken", consumerToken, "req", req)
-	if consumerToken != AccessToken {
-		return &CommonReply{
+	c, err := d.authenticate(ctx, req.(CommonRequest).GetConsumerToken())
+	if err != nil {
+		log.Warn("authenticate consumer fail", "err", err)
+		return ctx, &CommonReply{
 			Code:    wallet.ReturnCode_ERROR,
-			Message: "invalid consumer token",
+			Message: err.Error(),
 		}
 	}
 
 	chainName := req.(CommonRequest).GetChainName()
-	log.Debug("chain", chainName, "req", req)
+	log.Debug("pre handle", "chain", chainName, "consumer", c.Name, "req", loggable(req))
 	if _, ok := d.registry[chainName]; !ok {
-		return &CommonReply{
+		return ctx, &CommonReply{
 			Code:    wallet.ReturnCode_ERROR,
 			Message: "unsupported chain",
 		}
 	}
-	return nil
+	// 不经过 gRPC 调用时没有方法名，只有不限制方法的调用方可以通过
+	fullMethod, _ := grpc.Method(ctx)
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if err := c.Allow(chainName, method); err != nil {
+		log.Warn("consumer not allowed", "consumer", c.Name, "err", err)
+		return ctx, &CommonReply{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return consumer.WithConsumer(ctx, c), nil
 }
 
 func (d *ChainDispatcher) GetChainSignMethod(ctx context.Context, request *wallet.ChainSignMethodRequest) (*wallet.ChainSignMethodResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.ChainSignMethodResponse{
 			Code:    resp.Code,
@@ -139,7 +321,7 @@ func (d *ChainDispatcher) GetChainSignMethod(ctx context.Context, request *walle
 }
 
 func (d *ChainDispatcher) GetChainSchema(ctx context.Context, request *wallet.ChainSchemaRequest) (*wallet.ChainSchemaResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.ChainSchemaResponse{
 			Code:    resp.Code,
@@ -150,80 +332,193 @@ func (d *ChainDispatcher) GetChainSchema(ctx context.Context, request *wallet.Ch
 }
 
 func (d *ChainDispatcher) CreateKeyPairsExportPublicKeyList(ctx context.Context, request *wallet.CreateKeyPairAndExportPublicKeyRequest) (*wallet.CreateKeyPairAndExportPublicKeyResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.CreateKeyPairAndExportPublicKeyResponse{
 			Code:    resp.Code,
 			Message: resp.Message,
 		}, nil
 	}
-	return d.registry[request.ChainName].CreateKeyPairsExportPublicKeyList(ctx, request)
+	keysResp, err := d.registry[request.ChainName].CreateKeyPairsExportPublicKeyList(ctx, request)
+	var publicKeys []string
+	for _, key := range keysResp.GetPublicKeyList() {
+		publicKeys = append(publicKeys, key.PublicKey)
+	}
+	d.auditKeys(ctx, "createKeyPairsExportPublicKeyList", request.ChainName, keysResp.GetCode(), keysResp.GetMessage(), err, publicKeys)
+	return keysResp, err
 }
 
 func (d *ChainDispatcher) CreateKeyPairsWithAddresses(ctx context.Context, request *wallet.CreateKeyPairsWithAddressesRequest) (*wallet.CreateKeyPairsWithAddressesResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.CreateKeyPairsWithAddressesResponse{
 			Code:    resp.Code,
 			Message: resp.Message,
 		}, nil
 	}
-	return d.registry[request.ChainName].CreateKeyPairsWithAddresses(ctx, request)
+	keysResp, err := d.registry[request.ChainName].CreateKeyPairsWithAddresses(ctx, request)
+	var publicKeys []string
+	for _, key := range keysResp.GetPublicKeyAddresses() {
+		publicKeys = append(publicKeys, key.PublicKey)
+	}
+	d.auditKeys(ctx, "createKeyPairsWithAddresses", request.ChainName, keysResp.GetCode(), keysResp.GetMessage(), err, publicKeys)
+	return keysResp, err
 }
 
 func (d *ChainDispatcher) SignTransactionMessage(ctx context.Context, request *wallet.SignTransactionMessageRequest) (*wallet.SignTransactionMessageResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.SignTransactionMessageResponse{
 			Code:    resp.Code,
 			Message: resp.Message,
 		}, nil
 	}
-	return d.registry[request.ChainName].SignTransactionMessage(ctx, request)
+	fail := func(message string, reason string) *wallet.SignTransactionMessageResponse {
+		return &wallet.SignTransactionMessageResponse{
+			Code:         wallet.ReturnCode_ERROR,
+			Message:      message,
+			RejectReason: reason,
+		}
+	}
+	entry := audit.Entry{Chain: request.ChainName, PublicKey: request.PublicKey, Method: "signTransactionMessage", Intent: "message_hash=" + request.MessageHash}
+	signatures := func(resp *wallet.SignTransactionMessageResponse) []string { return []string{resp.Signature} }
+	return signOnce(ctx, d, "signTransactionMessage", request.RequestId, request, fail, func() (*wallet.SignTransactionMessageResponse, error) {
+		return audited(ctx, d, entry, fail, signatures, func(held *reservations) (*wallet.SignTransactionMessageResponse, error) {
+			if reason, message := d.checkRawMessage(request.ChainName); reason != "" {
+				return fail(message, reason), nil
+			}
+			reservation, reason, message := d.reserveVelocity(ctx, request.ChainName, request.PublicKey, "")
+			if reason != "" {
+				return fail(message, reason), nil
+			}
+			held.velocity = []*velocity.Reservation{reservation}
+			return d.registry[request.ChainName].SignTransactionMessage(ctx, request)
+		})
+	})
 }
 
 func (d *ChainDispatcher) BuildAndSignTransaction(ctx context.Context, request *wallet.BuildAndSignTransactionRequest) (*wallet.BuildAndSignTransactionResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.BuildAndSignTransactionResponse{
 			Code:    resp.Code,
 			Message: resp.Message,
 		}, nil
 	}
-	//验证 walletKey 和 riskKey
-	txReqJsonByte, err := base64.StdEncoding.DecodeString(request.TxBase64Body)
-	if err != nil {
+	fail := func(message string, reason string) *wallet.BuildAndSignTransactionResponse {
 		return &wallet.BuildAndSignTransactionResponse{
-			Code:    wallet.ReturnCode_ERROR,
-			Message: "decode base64 string fail",
-		}, nil
+			Code:         wallet.ReturnCode_ERROR,
+			Message:      message,
+			RejectReason: reason,
+		}
 	}
-	RiskKeyHash := crypto.Keccak256(append(txReqJsonByte, []byte(RiskKey)...))
-	RiskKeyHashStr := hexutils.BytesToHex(RiskKeyHash)
-	if RiskKeyHashStr != request.RiskKeyHash {
-		return &wallet.BuildAndSignTransactionResponse{
-			Code:    wallet.ReturnCode_ERROR,
-			Message: "riskKey hash check fail",
-		}, nil
+	entry := audit.Entry{Chain: request.ChainName, PublicKey: request.PublicKey, Method: "buildAndSignTransaction", Intent: d.intentSummary(request.ChainName, &wallet.TransactionMessage{PublicKey: request.PublicKey, TxBase64Body: request.TxBase64Body})}
+	signatures := func(resp *wallet.BuildAndSignTransactionResponse) []string { return []string{resp.SignedTx} }
+	return signOnce(ctx, d, "buildAndSignTransaction", request.RequestId, request, fail, func() (*wallet.BuildAndSignTransactionResponse, error) {
+		return audited(ctx, d, entry, fail, signatures, func(held *reservations) (*wallet.BuildAndSignTransactionResponse, error) {
+			if message := d.verifyApprovals(ctx, request.TxBase64Body, request.WalletKeyHash, request.RiskKeyHash); message != "" {
+				return fail(message, ""), nil
+			}
+			if reason, message := d.checkPolicy(request.ChainName, request.PublicKey, request.TxBase64Body); reason != "" {
+				return fail(message, reason), nil
+			}
+			// 超过审批阈值的交易需要通过 submitPendingTransaction 走多方审批
+			if reason, message := d.checkQuorum(request.ChainName, request.PublicKey, request.TxBase64Body); reason != "" {
+				return fail(message, reason), nil
+			}
+			nonces, reason, message := d.reserveNonces(ctx, request.ChainName, []nonceTx{{request.PublicKey, request.TxBase64Body, request.Replacement}})
+			if reason != "" {
+				return fail(message, reason), nil
+			}
+			held.nonces = nonces
+			reservation, reason, message := d.reserveVelocity(ctx, request.ChainName, request.PublicKey, request.TxBase64Body)
+			if reason != "" {
+				return fail(message, reason), nil
+			}
+			held.velocity = []*velocity.Reservation{reservation}
+			return d.registry[request.ChainName].BuildAndSignTransaction(ctx, request)
+		})
+	})
+}
+
+// verifyApprovals 交易请求体需要风控系统和钱包后端的审批，失败时返回错误信息
+func (d *ChainDispatcher) verifyApprovals(ctx context.Context, txBase64Body string, walletKeyHash string, riskKeyHash string) string {
+	txReqJsonByte, err := base64.StdEncoding.DecodeString(txBase64Body)
+	if err != nil {
+		return "decode base64 string fail"
 	}
-	WalletKeyHash := crypto.Keccak256(append(txReqJsonByte, []byte(WalletKey)...))
-	WalletKeyHashStr := hexutils.BytesToHex(WalletKeyHash)
-	if WalletKeyHashStr != request.WalletKeyHash {
-		return &wallet.BuildAndSignTransactionResponse{
-			Code:    wallet.ReturnCode_ERROR,
-			Message: "wallet hash check fail",
-		}, nil
+	riskKeyID, err := d.riskKeys.Verify(txReqJsonByte, riskKeyHash)
+	if err != nil {
+		log.Warn("risk approval check fail", "err", err)
+		return "riskKey hash check fail"
+	}
+	walletKeyID, err := d.walletKeys.Verify(txReqJsonByte, walletKeyHash)
+	if err != nil {
+		log.Warn("wallet approval check fail", "err", err)
+		return "wallet hash check fail"
 	}
-	return d.registry[request.ChainName].BuildAndSignTransaction(ctx, request)
+	log.Info("transaction approved", "consumer", consumer.FromContext(ctx), "riskKey", riskKeyID, "walletKey", walletKeyID)
+	return ""
 }
 
 func (d *ChainDispatcher) BuildAndSignBatchTransaction(ctx context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error) {
-	resp := d.preHandler(request)
+	ctx, resp := d.preHandler(ctx, request)
 	if resp != nil {
 		return &wallet.BuildAndSignBatchTransactionResponse{
 			Code:    resp.Code,
 			Message: resp.Message,
 		}, nil
 	}
-	return d.registry[request.ChainName].BuildAndSignBatchTransaction(ctx, request)
+	fail := func(message string, reason string) *wallet.BuildAndSignBatchTransactionResponse {
+		return &wallet.BuildAndSignBatchTransactionResponse{
+			Code:         wallet.ReturnCode_ERROR,
+			Message:      message,
+			RejectReason: reason,
+		}
+	}
+	var publicKeys []string
+	for _, txMsg := range request.TxMsg {
+		publicKeys = append(publicKeys, txMsg.PublicKey)
+	}
+	entry := audit.Entry{Chain: request.ChainName, PublicKey: strings.Join(publicKeys, ","), Method: "buildAndSignBatchTransaction", Intent: d.intentSummary(request.ChainName, request.TxMsg...)}
+	signatures := func(resp *wallet.BuildAndSignBatchTransactionResponse) []string {
+		var signedTxs []string
+		for _, tx := range resp.TxWithSign {
+			signedTxs = append(signedTxs, tx.SignedTx)
+		}
+		return signedTxs
+	}
+	return signOnce(ctx, d, "buildAndSignBatchTransaction", request.RequestId, request, fail, func() (*wallet.BuildAndSignBatchTransactionResponse, error) {
+		return audited(ctx, d, entry, fail, signatures, func(held *reservations) (*wallet.BuildAndSignBatchTransactionResponse, error) {
+			// adaptor 没有实现批量签名时直接拒绝，不预占 nonce 和限额
+			if !chain.SupportsBatchSigning(d.registry[request.ChainName]) {
+				return fail(chain.UnsupportedBatchSigning, ""), nil
+			}
+			// 每笔交易都需要钱包后端和风控系统的审批，任何一笔未审批或违反策略时整批拒绝
+			txs := make([]nonceTx, 0, len(request.TxMsg))
+			for i, txMsg := range request.TxMsg {
+				if message := d.verifyApprovals(ctx, txMsg.TxBase64Body, txMsg.WalletKeyHash, txMsg.RiskKeyHash); message != "" {
+					return fail(fmt.Sprintf("tx %d: %s", i, message), ""), nil
+				}
+				if reason, message := d.checkPolicy(request.ChainName, txMsg.PublicKey, txMsg.TxBase64Body); reason != "" {
+					return fail(message, reason), nil
+				}
+				if reason, message := d.checkQuorum(request.ChainName, txMsg.PublicKey, txMsg.TxBase64Body); reason != "" {
+					return fail(message, reason), nil
+				}
+				txs = append(txs, nonceTx{txMsg.PublicKey, txMsg.TxBase64Body, txMsg.Replacement})
+			}
+			nonces, reason, message := d.reserveNonces(ctx, request.ChainName, txs)
+			if reason != "" {
+				return fail(message, reason), nil
+			}
+			held.nonces = nonces
+			reservations, reason, message := d.reserveBatchVelocity(ctx, request.ChainName, request.TxMsg)
+			if reason != "" {
+				return fail(message, reason), nil
+			}
+			held.velocity = reservations
+			return d.registry[request.ChainName].BuildAndSignBatchTransaction(ctx, request)
+		})
+	})
 }
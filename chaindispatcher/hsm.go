@@ -0,0 +1,147 @@
+package chaindispatcher
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/chain/bitcoin"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/chain/solana"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// newHsmBackend 创建名为 name 的签名后端，key_path 是 gcp 的凭据文件，pkcs11 的 PIN 和 vault 的凭据从环境变量读取
+func newHsmBackend(conf *config.Config, name string) (hsm.Backend, error) {
+	switch name {
+	case "", hsm.BackendGcp:
+		client, err := hsm.NewHSMClient(context.Background(), conf.KeyPath, conf.KeyName)
+		if err != nil {
+			return nil, err
+		}
+		return hsm.NewGcpBackend(client, conf.KeyRing), nil
+	case hsm.BackendPkcs11:
+		return hsm.NewPkcs11Backend(conf.Pkcs11.Library, conf.Pkcs11.TokenLabel, os.Getenv(conf.Pkcs11.PinEnv))
+	case hsm.BackendVault:
+		auth := hsm.VaultAuth{
+			RoleID:   conf.Vault.RoleId,
+			SecretID: os.Getenv(conf.Vault.SecretIdEnv),
+		}
+		if conf.Vault.TokenEnv != "" {
+			auth.Token = os.Getenv(conf.Vault.TokenEnv)
+		}
+		return hsm.NewVaultBackend(conf.Vault.Address, conf.Vault.Mount, auth, conf.Vault.Secp256k1KeyType)
+	case hsm.BackendMemory:
+		return hsm.NewMemoryBackend(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hsm backend %q", name)
+	}
+}
+
+// chainHsmBackend 返回某条链使用的后端名，hsm_chains 中未配置的链使用 hsm_backend
+func chainHsmBackend(conf *config.Config, chainName string) string {
+	if name, ok := conf.HsmChains[chainName]; ok && name != "" {
+		return name
+	}
+	return conf.HsmBackend
+}
+
+// HsmKeyOptions 登记一个在 HSM 中创建的密钥，KeyName 是后端中的密钥名（gcp 为密钥版本的完整资源名），
+// PublicKey 为后端返回的公钥
+type HsmKeyOptions struct {
+	Backend       string
+	KeyName       string
+	PublicKey     []byte
+	AddressFormat string
+	Consumer      string
+	Network       string
+	Label         string
+}
+
+// RegisterHsmKey 把 HSM 密钥登记到链的 key store 中，记录的私钥是 hsm 密钥 id，开启 HSM 后可以直接用于签名
+func RegisterHsmKey(ctx context.Context, db *leveldb.Keys, chainName string, opts HsmKeyOptions) (*leveldb.Key, error) {
+	curve, ok := chainCurves[chainName]
+	if !ok {
+		return nil, errors.New("unsupported chain")
+	}
+	pubKey := opts.PublicKey
+	if curve == ssm.CurveSecp256k1 {
+		parsed, err := btcec.ParsePubKey(pubKey)
+		if err != nil {
+			return nil, fmt.Errorf("key %s is not a secp256k1 key: %w", opts.KeyName, err)
+		}
+		pubKey = parsed.SerializeUncompressed()
+	} else if len(pubKey) != 32 {
+		return nil, fmt.Errorf("key %s is not an ed25519 key", opts.KeyName)
+	}
+	address, err := ChainAddress(chainName, pubKey, opts.AddressFormat)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := hex.EncodeToString(pubKey)
+	if _, err := db.GetKey(ctx, chainName, publicKey); err == nil {
+		return nil, fmt.Errorf("public key %s already exists", publicKey)
+	} else if !errors.Is(err, leveldb.ErrKeyNotFound) {
+		return nil, err
+	}
+	record := leveldb.Key{
+		Chain:      chainName,
+		Curve:      curve,
+		Network:    opts.Network,
+		Consumer:   opts.Consumer,
+		PublicKey:  publicKey,
+		PrivateKey: hsm.KeyIDPrefix + opts.Backend + ":" + opts.KeyName,
+		CreatedAt:  time.Now().Unix(),
+		Label:      opts.Label,
+		Address:    address,
+	}
+	if !db.StoreKeys(ctx, []leveldb.Key{record}) {
+		return nil, errors.New("store keys fail")
+	}
+	return &record, nil
+}
+
+// ChainAddress 由公钥计算链上地址。HSM 只能产生 ECDSA 签名，Bitcoin 不支持 p2tr
+func ChainAddress(chainName string, pubKey []byte, format string) (string, error) {
+	switch chainName {
+	case ethereum.ChainName:
+		return common.BytesToAddress(crypto.Keccak256(pubKey[1:])[12:]).String(), nil
+	case solana.ChainName:
+		return solana.PubKeyHexToAddress(hex.EncodeToString(pubKey))
+	case bitcoin.ChainName:
+		parsed, err := btcec.ParsePubKey(pubKey)
+		if err != nil {
+			return "", err
+		}
+		pubKeyHash := btcutil.Hash160(parsed.SerializeCompressed())
+		switch format {
+		case "", "p2wpkh":
+			addr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+			if err != nil {
+				return "", err
+			}
+			return addr.EncodeAddress(), nil
+		case "p2pkh":
+			addr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+			if err != nil {
+				return "", err
+			}
+			return addr.EncodeAddress(), nil
+		default:
+			return "", fmt.Errorf("address format %q is not supported for hsm keys", format)
+		}
+	}
+	return "", errors.New("unsupported chain")
+}
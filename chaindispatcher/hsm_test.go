@@ -0,0 +1,57 @@
+package chaindispatcher
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/chain/bitcoin"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+func TestRegisterHsmKey(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := hsm.NewMemoryBackend()
+	ctx := context.Background()
+	keyName, err := backend.CreateKey(ctx, ssm.CurveSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := backend.PublicKey(ctx, keyName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := HsmKeyOptions{Backend: backend.Name(), KeyName: keyName, PublicKey: pubKey, Consumer: "default"}
+	record, err := RegisterHsmKey(context.Background(), db, ethereum.ChainName, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := crypto.UnmarshalPubkey(pubKey)
+	if record.Address != crypto.PubkeyToAddress(*parsed).String() {
+		t.Fatalf("address %s, want %s", record.Address, crypto.PubkeyToAddress(*parsed))
+	}
+	stored, err := db.GetKey(context.Background(), ethereum.ChainName, record.PublicKey)
+	if err != nil || stored.PrivateKey != hsm.KeyIDPrefix+hsm.BackendMemory+":"+keyName {
+		t.Fatalf("stored = %+v, err %v", stored, err)
+	}
+	if _, err := RegisterHsmKey(context.Background(), db, ethereum.ChainName, opts); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected a duplicate registration to fail, got %v", err)
+	}
+
+	opts.AddressFormat = "p2tr"
+	if _, err := RegisterHsmKey(context.Background(), db, bitcoin.ChainName, opts); err == nil {
+		t.Fatal("expected p2tr to be rejected for an ecdsa hsm key")
+	}
+	opts.PublicKey = pubKey[1:33]
+	if _, err := RegisterHsmKey(context.Background(), db, bitcoin.ChainName, opts); err == nil {
+		t.Fatal("expected an invalid secp256k1 public key to be rejected")
+	}
+}
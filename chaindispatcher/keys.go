@@ -0,0 +1,216 @@
+package chaindispatcher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+const (
+	defaultKeyPageSize = 100
+	maxKeyPageSize     = 1000
+)
+
+func (d *ChainDispatcher) ListKeys(ctx context.Context, request *wallet.ListKeysRequest) (*wallet.ListKeysResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ListKeysResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	pageSize := int(request.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultKeyPageSize
+	}
+	if pageSize > maxKeyPageSize {
+		pageSize = maxKeyPageSize
+	}
+	filter := leveldb.KeyFilter{
+		Consumer: consumer.FromContext(ctx),
+		Label:    request.Label,
+		Status:   request.Status,
+	}
+	records, nextPageToken, err := d.db.ListKeys(request.ChainName, filter, pageSize, request.PageToken)
+	if err != nil {
+		log.Error("list keys fail", "chain", request.ChainName, "err", err)
+		return &wallet.ListKeysResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "list keys fail",
+		}, nil
+	}
+	keys := make([]*wallet.KeyInfo, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, toKeyInfo(record))
+	}
+	return &wallet.ListKeysResponse{
+		Code:          wallet.ReturnCode_SUCCESS,
+		Message:       "list keys success",
+		Keys:          keys,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (d *ChainDispatcher) GetKey(ctx context.Context, request *wallet.GetKeyRequest) (*wallet.GetKeyResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.GetKeyResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	record, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey)
+	if err != nil {
+		return &wallet.GetKeyResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	return &wallet.GetKeyResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "get key success",
+		Key:     toKeyInfo(record),
+	}, nil
+}
+
+func (d *ChainDispatcher) SetKeyLabel(ctx context.Context, request *wallet.SetKeyLabelRequest) (*wallet.SetKeyLabelResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.SetKeyLabelResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if _, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey); err != nil {
+		return &wallet.SetKeyLabelResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	if err := d.db.SetKeyLabel(request.ChainName, request.PublicKey, request.Label); err != nil {
+		log.Error("update key label fail", "chain", request.ChainName, "err", err)
+		return &wallet.SetKeyLabelResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "set key label fail",
+		}, nil
+	}
+	return &wallet.SetKeyLabelResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "set key label success",
+	}, nil
+}
+
+func (d *ChainDispatcher) DisableKey(ctx context.Context, request *wallet.DisableKeyRequest) (*wallet.DisableKeyResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.DisableKeyResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	publicKeys := []string{request.PublicKey}
+	if _, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey); err != nil {
+		d.auditKeys(ctx, "disableKey", request.ChainName, wallet.ReturnCode_ERROR, "", err, publicKeys)
+		return &wallet.DisableKeyResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	// 停用后签名会被拒绝，但记录保留用于审计
+	if err := d.db.SetKeyStatus(request.ChainName, request.PublicKey, leveldb.KeyStatusDisabled); err != nil {
+		log.Error("disable key fail", "chain", request.ChainName, "err", err)
+		d.auditKeys(ctx, "disableKey", request.ChainName, wallet.ReturnCode_ERROR, "", err, publicKeys)
+		return &wallet.DisableKeyResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "disable key fail",
+		}, nil
+	}
+	d.auditKeys(ctx, "disableKey", request.ChainName, wallet.ReturnCode_SUCCESS, "", nil, publicKeys)
+	return &wallet.DisableKeyResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "disable key success",
+	}, nil
+}
+
+func (d *ChainDispatcher) DeleteKey(ctx context.Context, request *wallet.DeleteKeyRequest) (*wallet.DeleteKeyResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.DeleteKeyResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	publicKeys := []string{request.PublicKey}
+	if _, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey); err != nil {
+		d.auditKeys(ctx, "deleteKey", request.ChainName, wallet.ReturnCode_ERROR, "", err, publicKeys)
+		return &wallet.DeleteKeyResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	// 第一次调用只下发确认 token，不做删除
+	if request.ConfirmToken == "" {
+		token, err := d.db.IssueDeleteToken(request.ChainName, request.PublicKey)
+		if err != nil {
+			log.Error("issue delete token fail", "chain", request.ChainName, "err", err)
+			return &wallet.DeleteKeyResponse{
+				Code:    wallet.ReturnCode_ERROR,
+				Message: "issue confirm token fail",
+			}, nil
+		}
+		return &wallet.DeleteKeyResponse{
+			Code:         wallet.ReturnCode_ERROR,
+			Message:      "call deleteKey again with the confirm token to destroy the key",
+			ConfirmToken: token,
+		}, nil
+	}
+	if err := d.db.DeleteKey(request.ChainName, request.PublicKey, request.ConfirmToken); err != nil {
+		log.Error("delete key fail", "chain", request.ChainName, "err", err)
+		d.auditKeys(ctx, "deleteKey", request.ChainName, wallet.ReturnCode_ERROR, "", err, publicKeys)
+		return &wallet.DeleteKeyResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	d.auditKeys(ctx, "deleteKey", request.ChainName, wallet.ReturnCode_SUCCESS, "", nil, publicKeys)
+	return &wallet.DeleteKeyResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "delete key success",
+	}, nil
+}
+
+// loadOwnedKey 读取调用方自己的密钥记录
+func (d *ChainDispatcher) loadOwnedKey(ctx context.Context, chainName string, publicKey string) (*leveldb.Key, error) {
+	record, err := d.db.GetKey(ctx, chainName, publicKey)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrKeyNotFound) {
+			return nil, chain.ErrKeyNotFound
+		}
+		log.Error("get key fail", "chain", chainName, "err", err)
+		return nil, errors.New("get key fail")
+	}
+	if record.Consumer != consumer.FromContext(ctx) {
+		return nil, chain.ErrKeyNotOwned
+	}
+	return record, nil
+}
+
+func toKeyInfo(record *leveldb.Key) *wallet.KeyInfo {
+	return &wallet.KeyInfo{
+		ChainName:      record.Chain,
+		PublicKey:      record.PublicKey,
+		Curve:          record.Curve,
+		Network:        record.Network,
+		Consumer:       record.Consumer,
+		CreatedAt:      record.CreatedAt,
+		Label:          record.Label,
+		DerivationPath: record.DerivationPath,
+		Status:         record.KeyStatus(),
+	}
+}
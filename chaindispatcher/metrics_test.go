@@ -0,0 +1,88 @@
+package chaindispatcher
+
+import (
+	"context"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/grpc"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/metrics"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+// metricValue 返回计数器的值或直方图的样本数，没有该时间序列时返回 0
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if matchLabels(m, labels) {
+				if m.GetHistogram() != nil {
+					return float64(m.GetHistogram().GetSampleCount())
+				}
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func matchLabels(m *dto.Metric, labels map[string]string) bool {
+	if len(m.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, pair := range m.GetLabel() {
+		if labels[pair.GetName()] != pair.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestInterceptorRecordsMetrics(t *testing.T) {
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: nil}}
+	call := func(method string, req interface{}, resp interface{}) {
+		_, err := d.Interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/dapplink.wallet.WalletService/" + method},
+			func(ctx context.Context, req interface{}) (interface{}, error) { return resp, nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rejected := map[string]string{"method": "buildAndSignTransaction", "chain": ethereum.ChainName, "code": "ERROR"}
+	rejection := map[string]string{"method": "buildAndSignTransaction", "chain": ethereum.ChainName, "reason": ReasonNonceReused}
+	batch := map[string]string{"chain": ethereum.ChainName}
+	unknown := map[string]string{"method": "buildAndSignTransaction", "chain": "unknown", "code": "ERROR"}
+	beforeRejected := metricValue(t, "wallet_sign_requests_total", rejected)
+	beforeRejection := metricValue(t, "wallet_sign_rejections_total", rejection)
+	beforeBatch := metricValue(t, "wallet_sign_batch_size", batch)
+	beforeUnknown := metricValue(t, "wallet_sign_requests_total", unknown)
+
+	call("buildAndSignTransaction", &wallet.BuildAndSignTransactionRequest{ChainName: ethereum.ChainName},
+		&wallet.BuildAndSignTransactionResponse{Code: wallet.ReturnCode_ERROR, RejectReason: ReasonNonceReused})
+	call("buildAndSignBatchTransaction", &wallet.BuildAndSignBatchTransactionRequest{ChainName: ethereum.ChainName, TxMsg: make([]*wallet.TransactionMessage, 3)},
+		&wallet.BuildAndSignBatchTransactionResponse{Code: wallet.ReturnCode_SUCCESS})
+	call("buildAndSignTransaction", &wallet.BuildAndSignTransactionRequest{ChainName: "made-up-chain"},
+		&wallet.BuildAndSignTransactionResponse{Code: wallet.ReturnCode_ERROR, Message: "unsupported chain"})
+
+	if got := metricValue(t, "wallet_sign_requests_total", rejected); got != beforeRejected+1 {
+		t.Fatalf("rejected requests = %v, want %v", got, beforeRejected+1)
+	}
+	if got := metricValue(t, "wallet_sign_rejections_total", rejection); got != beforeRejection+1 {
+		t.Fatalf("rejections = %v, want %v", got, beforeRejection+1)
+	}
+	if got := metricValue(t, "wallet_sign_batch_size", batch); got != beforeBatch+1 {
+		t.Fatalf("batch size samples = %v, want %v", got, beforeBatch+1)
+	}
+	if got := metricValue(t, "wallet_sign_requests_total", unknown); got != beforeUnknown+1 {
+		t.Fatalf("unknown chain requests = %v, want %v", got, beforeUnknown+1)
+	}
+}
@@ -0,0 +1,43 @@
+package chaindispatcher
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/policy"
+)
+
+// checkPolicy 用链的 adaptor 解码交易请求体并检查交易策略，拒绝时返回原因码和错误信息；未配置策略时不检查。
+// 发送方由签名公钥推导，请求体中的发送方与签名密钥不一致时按解码失败拒绝
+func (d *ChainDispatcher) checkPolicy(chainName string, publicKey string, txBase64Body string) (string, string) {
+	if d.policy == nil {
+		return "", ""
+	}
+	intents, err := d.registry[chainName].DecodeIntents(publicKey, txBase64Body)
+	if err != nil {
+		log.Warn("decode transaction intents fail", "chain", chainName, "err", err)
+		return policy.ReasonDecodeFailed, "decode transaction fail: " + err.Error()
+	}
+	return policyReject(chainName, d.policy.Evaluate(chainName, intents))
+}
+
+// checkRawMessage signTransactionMessage 只有消息哈希，无法解码，由策略决定是否允许
+func (d *ChainDispatcher) checkRawMessage(chainName string) (string, string) {
+	if d.policy == nil {
+		return "", ""
+	}
+	return policyReject(chainName, d.policy.CheckRawMessage(chainName))
+}
+
+func policyReject(chainName string, err error) (string, string) {
+	if err == nil {
+		return "", ""
+	}
+	log.Warn("transaction rejected by policy", "chain", chainName, "err", err)
+	var violation *policy.Violation
+	if errors.As(err, &violation) {
+		return violation.Reason, err.Error()
+	}
+	return policy.ReasonDecodeFailed, err.Error()
+}
@@ -0,0 +1,97 @@
+package chaindispatcher
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/chain/solana"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+func TestTransactionPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	err := os.WriteFile(path, []byte(`
+chains:
+  Ethereum:
+    max_fee: "1000000000000000"
+    assets:
+      native: {max_amount: "1000000000000000000"}
+      "0xdac17f958d2ee523a2206206994597c13d831ec7": {}
+    contract_methods:
+      "0xdac17f958d2ee523a2206206994597c13d831ec7": [a9059cbb]
+  Solana:
+    allow_raw_messages: true
+    methods: [sol_transfer]
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &config.Config{Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}}}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethAdaptor, _ := ethereum.NewChainAdaptor(conf, db, nil)
+	solAdaptor, _ := solana.NewChainAdaptor(conf, db, nil)
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: ethAdaptor, solana.ChainName: solAdaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.policy, err = policy.NewEngine(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// ethKey 对应地址 0x2c7536E3605D9C16a7a3D7b1898e529396a65c23，solKey 对应 9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM
+	const ethKey = "044e3b81af9c2234cad09d679ce6035ed1392347ce64ce405f5dcd36228a25de6e47fd35c4215d1edf53e6f83de344615ce719bdb0fd878f6ed76f06dd277956de"
+	const solKey = "7e8c088760bfde1dddcf32c17f209b8242ee52aaf131facd88d0ea2c6d0b06f2"
+	ethTxFrom := func(from, amount, maxFee, contract string) string {
+		return base64.StdEncoding.EncodeToString([]byte(`{"chain_id":"1","nonce":0,"from_address":"` + from + `","to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+			`"gas_limit":21000,"max_fee_per_gas":"` + maxFee + `","max_priority_fee_per_gas":"1","amount":"` + amount + `","contract_address":"` + contract + `"}`))
+	}
+	ethTx := func(amount, maxFee, contract string) string {
+		return ethTxFrom("0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", amount, maxFee, contract)
+	}
+	solTx := func(action string) string {
+		return base64.StdEncoding.EncodeToString([]byte(`{"nonce":"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM","from_address":"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM",` +
+			`"actions":[{"type":"` + action + `","to_address":"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM","value":"1","memo":"m"}]}`))
+	}
+	cases := []struct {
+		chain     string
+		publicKey string
+		body      string
+		reason    string
+	}{
+		{ethereum.ChainName, ethKey, ethTx("1000000000000000000", "30000000000", "0x00"), ""},
+		{ethereum.ChainName, ethKey, ethTx("1000000000000000001", "30000000000", "0x00"), policy.ReasonAmountLimit},
+		// gas_limit * max_fee_per_gas 超过上限
+		{ethereum.ChainName, ethKey, ethTx("1", "50000000000", "0x00"), policy.ReasonFeeCap},
+		{ethereum.ChainName, ethKey, ethTx("1", "30000000000", "0xdAC17F958D2ee523a2206206994597C13D831ec7"), ""},
+		{ethereum.ChainName, ethKey, ethTx("1", "30000000000", "0x6B175474E89094C44Da98b954EedeAC495271d0F"), policy.ReasonAssetNotAllowed},
+		{ethereum.ChainName, ethKey, "not base64", policy.ReasonDecodeFailed},
+		// from_address 不是签名密钥的地址
+		{ethereum.ChainName, ethKey, ethTxFrom("0x35096AD62E57e86032a3Bb35aDaCF2240d55421D", "1", "30000000000", "0x00"), policy.ReasonDecodeFailed},
+		{solana.ChainName, solKey, solTx("sol_transfer"), ""},
+		{solana.ChainName, solKey, solTx("memo"), policy.ReasonMethodNotAllowed},
+		{solana.ChainName, "0000000000000000000000000000000000000000000000000000000000000001", solTx("sol_transfer"), policy.ReasonDecodeFailed},
+	}
+	for i, c := range cases {
+		if reason, message := d.checkPolicy(c.chain, c.publicKey, c.body); reason != c.reason {
+			t.Fatalf("case %d: reason %q (%s), want %q", i, reason, message, c.reason)
+		}
+	}
+
+	// Ethereum 的策略不允许对原始消息签名
+	resp, err := d.SignTransactionMessage(context.Background(), &wallet.SignTransactionMessageRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, MessageHash: "00"})
+	if err != nil || resp.Code != wallet.ReturnCode_ERROR || resp.RejectReason != policy.ReasonRawMessage {
+		t.Fatalf("raw message = %v, err %v", resp, err)
+	}
+}
@@ -0,0 +1,396 @@
+package chaindispatcher
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/quorum"
+	"github.com/0xshin-chan/wallet-sign/velocity"
+)
+
+// ReasonApprovalRequired 交易超过审批阈值，需要通过 submitPendingTransaction 多方审批
+const ReasonApprovalRequired = "approval_required"
+
+var errQuorumDisabled = errors.New("multi-party approval is not configured")
+
+// newQuorumWorkflow 按配置创建多方审批流程，没有配置审批人时返回 nil
+func newQuorumWorkflow(db *leveldb.Keys, conf config.MultiApprovalConfig) (*quorum.Workflow, error) {
+	if len(conf.Approvers) == 0 {
+		return nil, nil
+	}
+	approvers, err := parseApprovalKeys("approver", conf.Approvers)
+	if err != nil {
+		return nil, err
+	}
+	return quorum.NewWorkflow(db, approvers, conf.Quorum, conf.Ttl)
+}
+
+// checkQuorum 策略要求多方审批的交易不能直接签名，返回原因码和错误信息
+func (d *ChainDispatcher) checkQuorum(chainName string, publicKey string, txBase64Body string) (string, string) {
+	if d.policy == nil {
+		return "", ""
+	}
+	intents, err := d.registry[chainName].DecodeIntents(publicKey, txBase64Body)
+	if err != nil || !d.policy.RequiresApproval(chainName, intents) {
+		// 解码失败已经在 checkPolicy 中拒绝
+		return "", ""
+	}
+	if d.quorum == nil {
+		return ReasonApprovalRequired, "transaction needs multi-party approval: " + errQuorumDisabled.Error()
+	}
+	return ReasonApprovalRequired, "transaction needs multi-party approval, submit it with submitPendingTransaction"
+}
+
+func (d *ChainDispatcher) SubmitPendingTransaction(ctx context.Context, request *wallet.SubmitPendingTransactionRequest) (*wallet.SubmitPendingTransactionResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if d.quorum == nil {
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: errQuorumDisabled.Error(),
+		}, nil
+	}
+	if message := d.verifyApprovals(ctx, request.TxBase64Body, request.WalletKeyHash, request.RiskKeyHash); message != "" {
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: message,
+		}, nil
+	}
+	if reason, message := d.checkPolicy(request.ChainName, request.PublicKey, request.TxBase64Body); reason != "" {
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:         wallet.ReturnCode_ERROR,
+			Message:      message,
+			RejectReason: reason,
+		}, nil
+	}
+	if _, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey); err != nil {
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	body, _ := base64.StdEncoding.DecodeString(request.TxBase64Body)
+	tx := &leveldb.PendingTransaction{
+		Consumer:      consumer.FromContext(ctx),
+		ChainName:     request.ChainName,
+		Network:       request.Network,
+		PublicKey:     request.PublicKey,
+		TxBase64Body:  request.TxBase64Body,
+		WalletKeyHash: request.WalletKeyHash,
+		RiskKeyHash:   request.RiskKeyHash,
+		Replacement:   request.Replacement,
+	}
+	if err := d.quorum.Submit(tx, body); err != nil {
+		log.Error("submit pending transaction fail", "err", err)
+		return &wallet.SubmitPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "submit pending transaction fail",
+		}, nil
+	}
+	log.Info("pending transaction submitted", "id", tx.Id, "consumer", tx.Consumer, "chain", tx.ChainName)
+	return &wallet.SubmitPendingTransactionResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "submit pending transaction success",
+		Pending: d.toPendingInfo(ctx, tx),
+	}, nil
+}
+
+func (d *ChainDispatcher) ListPendingTransactions(ctx context.Context, request *wallet.ListPendingTransactionsRequest) (*wallet.ListPendingTransactionsResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ListPendingTransactionsResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if d.quorum == nil {
+		return &wallet.ListPendingTransactionsResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: errQuorumDisabled.Error(),
+		}, nil
+	}
+	txs, err := d.quorum.List(consumer.FromContext(ctx), request.ChainName, request.Status)
+	if err != nil {
+		log.Error("list pending transactions fail", "err", err)
+		return &wallet.ListPendingTransactionsResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "list pending transactions fail",
+		}, nil
+	}
+	pending := make([]*wallet.PendingTransaction, 0, len(txs))
+	for _, tx := range txs {
+		pending = append(pending, d.toPendingInfo(ctx, tx))
+	}
+	return &wallet.ListPendingTransactionsResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "list pending transactions success",
+		Pending: pending,
+	}, nil
+}
+
+// ListPendingApprovals 审批人核对所有调用方的待审批交易，返回交易请求体和解码后的转账目的
+func (d *ChainDispatcher) ListPendingApprovals(ctx context.Context, request *wallet.ListPendingApprovalsRequest) (*wallet.ListPendingApprovalsResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ListPendingApprovalsResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if d.quorum == nil {
+		return &wallet.ListPendingApprovalsResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: errQuorumDisabled.Error(),
+		}, nil
+	}
+	txs, err := d.quorum.ListForApproval(request.ChainName, request.Status)
+	if err != nil {
+		log.Error("list pending approvals fail", "err", err)
+		return &wallet.ListPendingApprovalsResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "list pending approvals fail",
+		}, nil
+	}
+	pending := make([]*wallet.PendingTransaction, 0, len(txs))
+	for _, tx := range txs {
+		info := d.toPendingInfo(ctx, tx)
+		info.TxBase64Body = tx.TxBase64Body
+		info.Intent = d.intentSummary(tx.ChainName, &wallet.TransactionMessage{PublicKey: tx.PublicKey, TxBase64Body: tx.TxBase64Body})
+		pending = append(pending, info)
+	}
+	return &wallet.ListPendingApprovalsResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "list pending approvals success",
+		Pending: pending,
+	}, nil
+}
+
+// ApprovePendingTransaction 审批人可以通过任意有权限的调用方提交签名，达到法定人数时立即签名
+func (d *ChainDispatcher) ApprovePendingTransaction(ctx context.Context, request *wallet.ApprovePendingTransactionRequest) (*wallet.ApprovePendingTransactionResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ApprovePendingTransactionResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if err := d.checkPendingChain(request.RequestId, request.ChainName); err != nil {
+		return &wallet.ApprovePendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	tx, release, err := d.quorum.Approve(request.RequestId, request.ApproverId, request.Signature)
+	if err != nil {
+		log.Warn("approve pending transaction fail", "id", request.RequestId, "approver", request.ApproverId, "err", err)
+		return &wallet.ApprovePendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	log.Info("pending transaction approved", "id", tx.Id, "approver", request.ApproverId, "approvals", len(tx.Approvals))
+	if release {
+		signErr := d.releasePending(ctx, tx)
+		if signErr != nil {
+			log.Error("sign approved transaction fail", "id", tx.Id, "err", signErr)
+		}
+		if err := d.quorum.Finish(tx, signErr); err != nil {
+			log.Error("save signed pending transaction fail", "id", tx.Id, "err", err)
+			return &wallet.ApprovePendingTransactionResponse{
+				Code:    wallet.ReturnCode_ERROR,
+				Message: "save pending transaction fail",
+			}, nil
+		}
+	}
+	return &wallet.ApprovePendingTransactionResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "approve pending transaction success",
+		Pending: d.toPendingInfo(ctx, tx),
+	}, nil
+}
+
+// releasePending 以提交方的身份签名，签名前重新检查提交方的权限、当前的交易策略和限额
+func (d *ChainDispatcher) releasePending(ctx context.Context, tx *leveldb.PendingTransaction) (err error) {
+	// 策略和审计使用提交方的调用方记录，提交方已停用或不再有权限时不签名
+	submitter, submitterErr := d.consumers.Lookup(tx.Consumer)
+	if submitterErr == nil {
+		submitterErr = submitter.Allow(tx.ChainName, "submitPendingTransaction")
+	}
+	if submitterErr != nil {
+		submitterErr = fmt.Errorf("submitter %s: %w", tx.Consumer, submitterErr)
+		ctx = consumer.NewContext(ctx, tx.Consumer)
+	} else {
+		ctx = consumer.WithConsumer(ctx, submitter)
+	}
+	entry := &audit.Entry{Chain: tx.ChainName, PublicKey: tx.PublicKey, Method: "approvePendingTransaction", Intent: d.intentSummary(tx.ChainName, &wallet.TransactionMessage{PublicKey: tx.PublicKey, TxBase64Body: tx.TxBase64Body})}
+	held := &reservations{}
+	// adaptor panic 时同样写审计记录并归还占用的 nonce 和限额
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("sign approved transaction panic", "id", tx.Id, "panic", r, "stack", string(debug.Stack()))
+			tx.SignedTx, tx.TxHash, tx.TxMessageHash = "", "", ""
+			err = errSignPanic
+		}
+		auditResult(entry, wallet.ReturnCode_SUCCESS, "", "", err, tx.SignedTx)
+		if auditErr := d.writeAudit(ctx, entry); auditErr != nil && err == nil {
+			// 没有审计记录的签名不返回，归还占用的 nonce 和限额
+			held.settle(ctx, false)
+			tx.SignedTx, tx.TxHash, tx.TxMessageHash = "", "", ""
+			err = auditErr
+			return
+		}
+		held.settle(ctx, err == nil)
+	}()
+	if submitterErr != nil {
+		return submitterErr
+	}
+	return d.signPending(ctx, tx, held)
+}
+
+func (d *ChainDispatcher) signPending(ctx context.Context, tx *leveldb.PendingTransaction, held *reservations) error {
+	if reason, message := d.checkPolicy(tx.ChainName, tx.PublicKey, tx.TxBase64Body); reason != "" {
+		return errors.New(message)
+	}
+	nonces, reason, message := d.reserveNonces(ctx, tx.ChainName, []nonceTx{{tx.PublicKey, tx.TxBase64Body, tx.Replacement}})
+	if reason != "" {
+		return errors.New(message)
+	}
+	held.nonces = nonces
+	reservation, reason, message := d.reserveVelocity(ctx, tx.ChainName, tx.PublicKey, tx.TxBase64Body)
+	if reason != "" {
+		return errors.New(message)
+	}
+	held.velocity = []*velocity.Reservation{reservation}
+	signResp, err := d.registry[tx.ChainName].BuildAndSignTransaction(ctx, &wallet.BuildAndSignTransactionRequest{
+		ChainName:     tx.ChainName,
+		Network:       tx.Network,
+		PublicKey:     tx.PublicKey,
+		WalletKeyHash: tx.WalletKeyHash,
+		RiskKeyHash:   tx.RiskKeyHash,
+		TxBase64Body:  tx.TxBase64Body,
+	})
+	if err != nil {
+		return err
+	}
+	if signResp.Code != wallet.ReturnCode_SUCCESS {
+		return errors.New(signResp.Message)
+	}
+	tx.SignedTx, tx.TxHash, tx.TxMessageHash = signResp.SignedTx, signResp.TxHash, signResp.TxMessageHash
+	return nil
+}
+
+func (d *ChainDispatcher) RejectPendingTransaction(ctx context.Context, request *wallet.RejectPendingTransactionRequest) (*wallet.RejectPendingTransactionResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.RejectPendingTransactionResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if err := d.checkPendingChain(request.RequestId, request.ChainName); err != nil {
+		return &wallet.RejectPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	tx, err := d.quorum.Reject(request.RequestId, request.ApproverId, request.Signature, request.Reason)
+	if err != nil {
+		log.Warn("reject pending transaction fail", "id", request.RequestId, "approver", request.ApproverId, "err", err)
+		return &wallet.RejectPendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	log.Info("pending transaction rejected", "id", tx.Id, "approver", request.ApproverId)
+	return &wallet.RejectPendingTransactionResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "reject pending transaction success",
+		Pending: d.toPendingInfo(ctx, tx),
+	}, nil
+}
+
+// ExpirePendingTransaction 提交方撤回还在等待审批的交易
+func (d *ChainDispatcher) ExpirePendingTransaction(ctx context.Context, request *wallet.ExpirePendingTransactionRequest) (*wallet.ExpirePendingTransactionResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.ExpirePendingTransactionResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	if err := d.checkPendingChain(request.RequestId, request.ChainName); err != nil {
+		return &wallet.ExpirePendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	tx, err := d.quorum.Expire(request.RequestId, consumer.FromContext(ctx))
+	if err != nil {
+		return &wallet.ExpirePendingTransactionResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+	return &wallet.ExpirePendingTransactionResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "expire pending transaction success",
+		Pending: d.toPendingInfo(ctx, tx),
+	}, nil
+}
+
+// checkPendingChain 调用方的链权限按请求中的链检查，这里确认记录属于该链
+func (d *ChainDispatcher) checkPendingChain(id string, chainName string) error {
+	if d.quorum == nil {
+		return errQuorumDisabled
+	}
+	tx, err := d.quorum.Get(id)
+	if err != nil {
+		return err
+	}
+	if tx.ChainName != chainName {
+		return leveldb.ErrPendingNotFound
+	}
+	return nil
+}
+
+// toPendingInfo 签名结果只返回给提交方
+func (d *ChainDispatcher) toPendingInfo(ctx context.Context, tx *leveldb.PendingTransaction) *wallet.PendingTransaction {
+	info := &wallet.PendingTransaction{
+		RequestId:  tx.Id,
+		ChainName:  tx.ChainName,
+		Network:    tx.Network,
+		PublicKey:  tx.PublicKey,
+		Consumer:   tx.Consumer,
+		IntentHash: tx.IntentHash,
+		Status:     tx.Status,
+		Quorum:     uint32(d.quorum.Quorum()),
+		CreatedAt:  tx.CreatedAt,
+		ExpiresAt:  tx.ExpiresAt,
+		RejectedBy: tx.RejectedBy,
+		Reason:     tx.Reason,
+	}
+	for approver := range tx.Approvals {
+		info.ApprovedBy = append(info.ApprovedBy, approver)
+	}
+	sort.Strings(info.ApprovedBy)
+	if tx.Consumer == consumer.FromContext(ctx) {
+		info.SignedTx, info.TxHash, info.TxMessageHash = tx.SignedTx, tx.TxHash, tx.TxMessageHash
+	}
+	return info
+}
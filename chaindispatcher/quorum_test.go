@@ -0,0 +1,180 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+func TestMultiPartyApproval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	err := os.WriteFile(path, []byte(`
+chains:
+  Ethereum:
+    assets:
+      native: {approval_amount: "1000"}
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approverKeys := make([]ed25519.PrivateKey, 3)
+	var approvers []config.ApprovalKeyConfig
+	for i, id := range []string{"alice", "bob", "carol"} {
+		pub, pri, _ := ed25519.GenerateKey(rand.Reader)
+		approverKeys[i] = pri
+		approvers = append(approvers, config.ApprovalKeyConfig{Id: id, Type: riskkey.TypeEd25519, PublicKey: hex.EncodeToString(pub)})
+	}
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{
+			{Name: "exchange", TokenHash: consumer.HashToken("token")},
+			{Name: "ops", TokenHash: consumer.HashToken("ops-token")},
+		},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+		MultiApproval: config.MultiApprovalConfig{Quorum: 2, Ttl: time.Hour, Approvers: approvers},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.policy, err = policy.NewEngine(path); err != nil {
+		t.Fatal(err)
+	}
+	if d.quorum, err = newQuorumWorkflow(db, conf.MultiApproval); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	publicKey := keys.PublicKeyAddresses[0].PublicKey
+	body := []byte(`{"chain_id":"1","nonce":0,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+		`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"5000"}`)
+	txBase64Body := base64.StdEncoding.EncodeToString(body)
+	walletApproval := riskkey.HmacApproval("w1", walletSecret, body)
+	riskApproval := riskkey.HmacApproval("r1", riskSecret, body)
+
+	// 超过审批阈值的交易不能直接签名
+	direct, err := d.BuildAndSignTransaction(ctx, &wallet.BuildAndSignTransactionRequest{
+		ConsumerToken: "token", ChainName: ethereum.ChainName, PublicKey: publicKey,
+		WalletKeyHash: walletApproval, RiskKeyHash: riskApproval, TxBase64Body: txBase64Body,
+	})
+	if err != nil || direct.Code != wallet.ReturnCode_ERROR || direct.RejectReason != ReasonApprovalRequired {
+		t.Fatalf("direct signing = %v, err %v", direct, err)
+	}
+
+	submitted, err := d.SubmitPendingTransaction(ctx, &wallet.SubmitPendingTransactionRequest{
+		ConsumerToken: "token", ChainName: ethereum.ChainName, Network: "mainnet", PublicKey: publicKey,
+		WalletKeyHash: walletApproval, RiskKeyHash: riskApproval, TxBase64Body: txBase64Body,
+	})
+	if err != nil || submitted.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("submit = %v, err %v", submitted, err)
+	}
+	pending := submitted.Pending
+	if pending.TxBase64Body != "" || pending.Intent != "" {
+		t.Fatalf("submit response carries approval details: %v", pending)
+	}
+	// 审批人通过 listPendingApprovals 核对其他调用方提交的交易内容
+	approvals, err := d.ListPendingApprovals(ctx, &wallet.ListPendingApprovalsRequest{ConsumerToken: "ops-token", ChainName: ethereum.ChainName, Status: leveldb.PendingStatusPending})
+	if err != nil || approvals.Code != wallet.ReturnCode_SUCCESS || len(approvals.Pending) != 1 {
+		t.Fatalf("approval list = %v, err %v", approvals, err)
+	}
+	if p := approvals.Pending[0]; p.TxBase64Body != txBase64Body || !strings.Contains(p.Intent, "to=0x35096AD62E57e86032a3Bb35aDaCF2240d55421D") || !strings.Contains(p.Intent, "amount=5000") {
+		t.Fatalf("approval details = %v", p)
+	}
+	intentHash, _ := hex.DecodeString(pending.IntentHash)
+	approve := func(token string, approver int, id string) *wallet.ApprovePendingTransactionResponse {
+		resp, err := d.ApprovePendingTransaction(ctx, &wallet.ApprovePendingTransactionRequest{
+			ConsumerToken: token, ChainName: ethereum.ChainName, RequestId: pending.RequestId,
+			ApproverId: id, Signature: hex.EncodeToString(ed25519.Sign(approverKeys[approver], intentHash)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if resp := approve("ops-token", 0, "alice"); resp.Code != wallet.ReturnCode_SUCCESS || resp.Pending.Status != leveldb.PendingStatusPending {
+		t.Fatalf("first approval = %v", resp)
+	}
+	if resp := approve("ops-token", 0, "bob"); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatal("expected a signature under another approver's id to fail")
+	}
+	// 审批人可以通过其他调用方提交，签名结果只返回给提交方
+	released := approve("ops-token", 1, "bob")
+	if released.Code != wallet.ReturnCode_SUCCESS || released.Pending.Status != leveldb.PendingStatusSigned || released.Pending.SignedTx != "" {
+		t.Fatalf("quorum approval = %v", released)
+	}
+	list, err := d.ListPendingTransactions(ctx, &wallet.ListPendingTransactionsRequest{ConsumerToken: "token", ChainName: ethereum.ChainName})
+	if err != nil || len(list.Pending) != 1 {
+		t.Fatalf("list = %v, err %v", list, err)
+	}
+	if p := list.Pending[0]; p.SignedTx == "" || p.TxHash == "" || len(p.ApprovedBy) != 2 || p.Quorum != 2 {
+		t.Fatalf("signed pending = %v", p)
+	}
+	if other, _ := d.ListPendingTransactions(ctx, &wallet.ListPendingTransactionsRequest{ConsumerToken: "ops-token", ChainName: ethereum.ChainName}); len(other.Pending) != 0 {
+		t.Fatalf("other consumer sees %v", other.Pending)
+	}
+	// 已签名的记录不能再审批
+	if resp := approve("ops-token", 2, "carol"); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatalf("approval of a signed tx = %v", resp)
+	}
+
+	// 审批期间提交方被停用时，达到法定人数后不再签名
+	body = bytes.Replace(body, []byte(`"nonce":0`), []byte(`"nonce":1`), 1)
+	submitted, err = d.SubmitPendingTransaction(ctx, &wallet.SubmitPendingTransactionRequest{
+		ConsumerToken: "token", ChainName: ethereum.ChainName, Network: "mainnet", PublicKey: publicKey,
+		WalletKeyHash: riskkey.HmacApproval("w1", walletSecret, body), RiskKeyHash: riskkey.HmacApproval("r1", riskSecret, body),
+		TxBase64Body: base64.StdEncoding.EncodeToString(body),
+	})
+	if err != nil || submitted.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("second submit = %v, err %v", submitted, err)
+	}
+	pending = submitted.Pending
+	intentHash, _ = hex.DecodeString(pending.IntentHash)
+	conf.Consumers[0].Disabled = true
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	approve("ops-token", 0, "alice")
+	if resp := approve("ops-token", 1, "bob"); resp.Pending.Status != leveldb.PendingStatusFailed || !strings.Contains(resp.Pending.Reason, consumer.ErrDisabled.Error()) {
+		t.Fatalf("approval for a disabled submitter = %v", resp)
+	}
+}
@@ -0,0 +1,105 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/grpc"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/common/redact"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+// TestLogsHaveNoSecrets 签名流程的日志中不能出现私钥、token 和审批 hash
+func TestLogsHaveNoSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	previous := log.Root()
+	log.SetDefault(log.NewLogger(redact.NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	defer log.SetDefault(previous)
+
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("consumer-token-value")}},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+	call := func(method string, req interface{}, handler func(ctx context.Context, req interface{}) (interface{}, error)) interface{} {
+		resp, err := d.Interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/dapplink.wallet.WalletService/" + method}, handler)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	keys := call("createKeyPairsWithAddresses", &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "consumer-token-value", ChainName: ethereum.ChainName, KeyNum: 1},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return d.CreateKeyPairsWithAddresses(ctx, req.(*wallet.CreateKeyPairsWithAddressesRequest))
+		}).(*wallet.CreateKeyPairsWithAddressesResponse)
+	publicKey := keys.PublicKeyAddresses[0].PublicKey
+	record, err := db.GetKey(context.Background(), ethereum.ChainName, publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"chain_id":"1","nonce":0,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+		`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"1"}`)
+	walletApproval := riskkey.HmacApproval("w1", walletSecret, body)
+	riskApproval := riskkey.HmacApproval("r1", riskSecret, body)
+	signed := call("buildAndSignTransaction", &wallet.BuildAndSignTransactionRequest{
+		ConsumerToken: "consumer-token-value",
+		ChainName:     ethereum.ChainName,
+		PublicKey:     publicKey,
+		WalletKeyHash: walletApproval,
+		RiskKeyHash:   riskApproval,
+		TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+	}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return d.BuildAndSignTransaction(ctx, req.(*wallet.BuildAndSignTransactionRequest))
+	}).(*wallet.BuildAndSignTransactionResponse)
+	if signed.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("sign = %v", signed)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{record.PrivateKey, "consumer-token-value", walletApproval, riskApproval} {
+		if strings.Contains(out, secret) {
+			t.Fatalf("log output contains %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "buildAndSignTransaction") {
+		t.Fatalf("request was not logged:\n%s", out)
+	}
+}
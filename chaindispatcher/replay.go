@@ -0,0 +1,251 @@
+package chaindispatcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+const (
+	// ReasonRequestIDReused 同一个 request_id 用于了不同的请求
+	ReasonRequestIDReused = "request_id_reused"
+	// ReasonNonceReused 同一 nonce 已经签名过另一笔交易，且请求没有标记为替换交易
+	ReasonNonceReused = "nonce_reused"
+
+	defaultIdempotencyTtl = 24 * time.Hour
+)
+
+// replayGuard 按 request_id 去重签名请求，并记录每个 (链, 公钥, nonce) 签名过的交易
+type replayGuard struct {
+	db  *leveldb.Keys
+	ttl time.Duration
+
+	// requests 为正在处理的 request_id，nonces 为正在签名的 nonce -> 请求体 hash
+	mu       sync.Mutex
+	requests map[string]bool
+	nonces   map[string]string
+	now      func() time.Time
+}
+
+func newReplayGuard(db *leveldb.Keys, ttl time.Duration) *replayGuard {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTtl
+	}
+	return &replayGuard{
+		db:       db,
+		ttl:      ttl,
+		requests: make(map[string]bool),
+		nonces:   make(map[string]string),
+		now:      time.Now,
+	}
+}
+
+// requestFingerprint 去掉 consumer_token 后请求的 hash，换用证书或新 token 重试时结果不变
+func requestFingerprint(request proto.Message) (string, error) {
+	clone := proto.Clone(request).ProtoReflect()
+	if field := clone.Descriptor().Fields().ByName("consumer_token"); field != nil {
+		clone.Clear(field)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(clone.Interface())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type signingResponse interface {
+	proto.Message
+	GetCode() wallet.ReturnCode
+}
+
+// signOnce requestID 不为空时，同一调用方重复的请求直接返回第一次成功签名的结果。
+// 失败的结果不缓存，没有签名任何内容，调用方可以用同一个 request_id 重试
+func signOnce[T signingResponse](ctx context.Context, d *ChainDispatcher, method string, requestID string, request proto.Message,
+	fail func(message string, reason string) T, sign func() (T, error)) (T, error) {
+	g := d.replay
+	if g == nil || requestID == "" {
+		return sign()
+	}
+	consumerName := consumer.FromContext(ctx)
+	fingerprint, err := requestFingerprint(request)
+	if err != nil {
+		log.Error("marshal signing request fail", "err", err)
+		return fail("marshal request fail", ""), nil
+	}
+
+	inflight := consumerName + "\x00" + method + "\x00" + requestID
+	g.mu.Lock()
+	if g.requests[inflight] {
+		g.mu.Unlock()
+		return fail(fmt.Sprintf("request %s is in progress", requestID), ""), nil
+	}
+	record, err := g.db.GetIdempotencyRecord(ctx, consumerName, method, requestID)
+	if err == nil && record != nil && g.now().Sub(time.Unix(record.CreatedAt, 0)) >= g.ttl {
+		record = nil
+	}
+	if err != nil || record == nil {
+		g.requests[inflight] = true
+	}
+	g.mu.Unlock()
+	if err != nil {
+		log.Error("get idempotency record fail", "err", err)
+		return fail("get idempotency record fail", ""), nil
+	}
+	if record != nil {
+		if record.Fingerprint != fingerprint {
+			return fail(fmt.Sprintf("request_id %s was used for a different request", requestID), ReasonRequestIDReused), nil
+		}
+		var zero T
+		cached := zero.ProtoReflect().New().Interface().(T)
+		if err := proto.Unmarshal(record.Response, cached); err != nil {
+			log.Error("decode cached response fail", "err", err)
+			return fail("decode cached response fail", ""), nil
+		}
+		log.Info("return cached signing result", "consumer", consumerName, "method", method, "requestId", requestID)
+		return cached, nil
+	}
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.requests, inflight)
+		g.mu.Unlock()
+	}()
+	resp, err := sign()
+	if err != nil || resp.GetCode() != wallet.ReturnCode_SUCCESS {
+		return resp, err
+	}
+	data, err := proto.Marshal(resp)
+	if err == nil {
+		err = g.db.PutIdempotencyRecord(ctx, consumerName, method, requestID, &leveldb.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Response:    data,
+			CreatedAt:   g.now().Unix(),
+		})
+	}
+	if err != nil {
+		// 已经签名成功，仍然返回结果
+		log.Error("save idempotency record fail", "requestId", requestID, "err", err)
+	}
+	return resp, nil
+}
+
+// nonceTx 一笔待签名交易，replacement 为 true 时允许覆盖同一 nonce 已签名的交易
+type nonceTx struct {
+	publicKey    string
+	txBase64Body string
+	replacement  bool
+}
+
+// nonceReservation 签名期间占用的 nonce，签名结束后调用 settle
+type nonceReservation struct {
+	guard   *replayGuard
+	records []leveldb.NonceRecord
+}
+
+// reserveNonces 检查交易的 nonce 没有签名过其他交易，并在签名期间占用，防止并发请求对同一 nonce 签名不同的交易。
+// 拒绝时返回原因码和错误信息
+func (d *ChainDispatcher) reserveNonces(ctx context.Context, chainName string, txs []nonceTx) (*nonceReservation, string, string) {
+	g := d.replay
+	if g == nil {
+		return nil, "", ""
+	}
+	var records []leveldb.NonceRecord
+	var replacements []bool
+	for _, tx := range txs {
+		intents, err := d.registry[chainName].DecodeIntents(tx.publicKey, tx.txBase64Body)
+		if err != nil {
+			// 无法解码的交易由 adaptor 拒绝
+			continue
+		}
+		bodyHash, err := intentsHash(intents)
+		if err != nil {
+			log.Error("hash transaction intents fail", "err", err)
+			return nil, policy.ReasonDecodeFailed, "hash transaction fail"
+		}
+		for _, intent := range intents {
+			if intent.Nonce == "" {
+				continue
+			}
+			record := leveldb.NonceRecord{Chain: chainName, PublicKey: tx.publicKey, Nonce: intent.Nonce, BodyHash: bodyHash}
+			// 同一批次中也不能对同一 nonce 签名两笔不同的交易
+			for _, other := range records {
+				if nonceGuardKey(other) == nonceGuardKey(record) && other.BodyHash != bodyHash && !tx.replacement {
+					return nil, ReasonNonceReused, fmt.Sprintf("nonce %s is used by two transactions in the batch", intent.Nonce)
+				}
+			}
+			records = append(records, record)
+			replacements = append(replacements, tx.replacement)
+		}
+	}
+	if len(records) == 0 {
+		return nil, "", ""
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i, record := range records {
+		if _, ok := g.nonces[nonceGuardKey(record)]; ok {
+			return nil, ReasonNonceReused, fmt.Sprintf("nonce %s is being signed by another request", record.Nonce)
+		}
+		if replacements[i] {
+			continue
+		}
+		signed, err := g.db.GetNonceBodyHash(ctx, record.Chain, record.PublicKey, record.Nonce)
+		if err != nil {
+			log.Error("get nonce record fail", "err", err)
+			return nil, ReasonNonceReused, "get nonce record fail"
+		}
+		if signed != "" && signed != record.BodyHash {
+			log.Warn("nonce already signed", "chain", chainName, "publicKey", record.PublicKey, "nonce", record.Nonce)
+			return nil, ReasonNonceReused, fmt.Sprintf("nonce %s already signed a different transaction, set replacement to replace it", record.Nonce)
+		}
+	}
+	for _, record := range records {
+		g.nonces[nonceGuardKey(record)] = record.BodyHash
+	}
+	return &nonceReservation{guard: g, records: records}, "", ""
+}
+
+// intentsHash 解码后交易内容的 hash，同一笔交易换一种 JSON 写法或字段顺序重试时 hash 不变
+func intentsHash(intents []policy.Intent) (string, error) {
+	data, err := json.Marshal(intents)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func nonceGuardKey(record leveldb.NonceRecord) string {
+	return record.Chain + "\x00" + record.PublicKey + "\x00" + record.Nonce
+}
+
+// settle 签名成功时记录 nonce，然后释放占用，nil 可以安全调用
+func (r *nonceReservation) settle(ctx context.Context, signed bool) {
+	if r == nil {
+		return
+	}
+	r.guard.mu.Lock()
+	defer r.guard.mu.Unlock()
+	if signed {
+		if err := r.guard.db.PutNonceRecords(ctx, r.records); err != nil {
+			log.Error("save nonce records fail", "err", err)
+		}
+	}
+	for _, record := range r.records {
+		delete(r.guard.nonces, nonceGuardKey(record))
+	}
+}
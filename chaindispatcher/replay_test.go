@@ -0,0 +1,182 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+	"github.com/0xshin-chan/wallet-sign/velocity"
+)
+
+func TestIdempotencyAndNonceReplay(t *testing.T) {
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+		VelocityLimits: []config.VelocityLimitConfig{
+			{Name: "rate", Scope: velocity.ScopeConsumer, MaxCount: 100, Window: time.Hour},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db, replay: newReplayGuard(db, 0)}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.limiter, err = newLimiter(db, conf.VelocityLimits); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	signBody := func(requestID string, body []byte, replacement bool) *wallet.BuildAndSignTransactionResponse {
+		resp, err := d.BuildAndSignTransaction(ctx, &wallet.BuildAndSignTransactionRequest{
+			ConsumerToken: "token",
+			ChainName:     ethereum.ChainName,
+			PublicKey:     keys.PublicKeyAddresses[0].PublicKey,
+			WalletKeyHash: riskkey.HmacApproval("w1", walletSecret, body),
+			RiskKeyHash:   riskkey.HmacApproval("r1", riskSecret, body),
+			TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+			RequestId:     requestID,
+			Replacement:   replacement,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	sign := func(requestID string, amount string, replacement bool) *wallet.BuildAndSignTransactionResponse {
+		return signBody(requestID, []byte(`{"chain_id":"1","nonce":7,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",`+
+			`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"`+amount+`"}`), replacement)
+	}
+	signedCount := func() int {
+		usages, err := d.limiter.Usage(ethereum.ChainName, "", "exchange")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return usages[0].UsedCount
+	}
+
+	first := sign("req-1", "1", false)
+	if first.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("first = %v", first)
+	}
+	// 重试返回缓存的结果，不再签名
+	if retry := sign("req-1", "1", false); retry.Code != wallet.ReturnCode_SUCCESS || retry.SignedTx != first.SignedTx || signedCount() != 1 {
+		t.Fatalf("retry = %v, signed %d times", retry, signedCount())
+	}
+	if resp := sign("req-1", "2", true); resp.RejectReason != ReasonRequestIDReused {
+		t.Fatalf("reused request id = %v", resp)
+	}
+	// 同一 nonce 的另一笔交易需要标记为替换
+	if resp := sign("req-2", "2", false); resp.RejectReason != ReasonNonceReused {
+		t.Fatalf("same nonce = %v", resp)
+	}
+	if resp := sign("req-2", "2", true); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("replacement = %v", resp)
+	}
+	if resp := sign("", "1", false); resp.RejectReason != ReasonNonceReused {
+		t.Fatalf("replaced transaction signed again = %v", resp)
+	}
+	if resp := sign("", "2", false); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("same transaction signed again = %v", resp)
+	}
+	// 同一笔交易换一种 JSON 写法不算另一笔交易
+	reordered := []byte(`{ "amount": "2", "nonce": 7, "chain_id": "1", "gas_limit": 21000, "max_priority_fee_per_gas": "1000000000",
+		"max_fee_per_gas": "30000000000", "to_address": "0x35096ad62e57e86032a3bb35adacf2240d55421d" }`)
+	if resp := signBody("", reordered, false); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("same transaction in another encoding = %v", resp)
+	}
+
+	// 签名后审计记录写入失败时不返回签名，也不占用 nonce
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog.Close()
+	d.audit = auditLog
+	nextNonce := func(amount string) []byte {
+		return []byte(`{"chain_id":"1","nonce":8,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+			`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"` + amount + `"}`)
+	}
+	if resp := signBody("req-3", nextNonce("1"), false); resp.Code != wallet.ReturnCode_ERROR || resp.SignedTx != "" {
+		t.Fatalf("audit failure = %v", resp)
+	}
+	d.audit = nil
+	if resp := signBody("req-4", nextNonce("3"), false); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("another transaction after audit failure = %v", resp)
+	}
+	if resp := signBody("req-3", nextNonce("1"), false); resp.RejectReason != ReasonNonceReused {
+		t.Fatalf("unaudited transaction after the nonce was used = %v", resp)
+	}
+
+	// adaptor panic 时记录审计并归还 nonce 和限额
+	panicLog := filepath.Join(t.TempDir(), "panic.log")
+	if d.audit, err = audit.Open(panicLog); err != nil {
+		t.Fatal(err)
+	}
+	defer d.audit.Close()
+	d.registry[ethereum.ChainName] = panicAdaptor{adaptor}
+	nonce9 := func(amount string) []byte {
+		return []byte(`{"chain_id":"1","nonce":9,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+			`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"` + amount + `"}`)
+	}
+	used := signedCount()
+	if resp := signBody("", nonce9("1"), false); resp.Code != wallet.ReturnCode_ERROR || resp.Message != errSignPanic.Error() {
+		t.Fatalf("panicking adaptor = %v", resp)
+	}
+	if signedCount() != used {
+		t.Fatal("panicking adaptor used velocity quota")
+	}
+	d.registry[ethereum.ChainName] = adaptor
+	if resp := signBody("", nonce9("2"), false); resp.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("nonce after panic = %v", resp)
+	}
+	data, _ := os.ReadFile(panicLog)
+	if !strings.Contains(string(data), `"decision":"denied","reason":"`+errSignPanic.Error()) {
+		t.Fatalf("panic was not audited:\n%s", data)
+	}
+}
+
+// panicAdaptor 模拟在签名时 panic 的 adaptor
+type panicAdaptor struct {
+	chain.IChainAdaptor
+}
+
+func (panicAdaptor) BuildAndSignTransaction(context.Context, *wallet.BuildAndSignTransactionRequest) (*wallet.BuildAndSignTransactionResponse, error) {
+	panic("implement me")
+}
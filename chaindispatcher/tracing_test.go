@@ -0,0 +1,118 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+// TestSigningTrace 一次签名请求的 span 继续上游的 trace，key store、交易构建和 KMS 调用都在同一个 trace 中
+func TestSigningTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.Install(exporter, config.TracingConfig{})
+	defer provider.Shutdown(context.Background())
+
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := hsm.NewSigner(hsm.Instrument(hsm.NewMemoryBackend()), ssm.CurveSecp256k1, &ssm.ECDSASigner{})
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db, replay: newReplayGuard(db, 0)}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := d.CreateKeyPairsWithAddresses(context.Background(), &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	provider.ForceFlush(context.Background())
+	exporter.Reset()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01"))
+	body := []byte(`{"chain_id":"1","nonce":3,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",` +
+		`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"1"}`)
+	resp, err := d.Interceptor(ctx, &wallet.BuildAndSignTransactionRequest{
+		ConsumerToken: "token",
+		ChainName:     ethereum.ChainName,
+		PublicKey:     keys.PublicKeyAddresses[0].PublicKey,
+		WalletKeyHash: riskkey.HmacApproval("w1", walletSecret, body),
+		RiskKeyHash:   riskkey.HmacApproval("r1", riskSecret, body),
+		TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+		RequestId:     "trace-1",
+	}, &grpc.UnaryServerInfo{FullMethod: "/dapplink.wallet.WalletService/buildAndSignTransaction"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return d.BuildAndSignTransaction(ctx, req.(*wallet.BuildAndSignTransactionRequest))
+		})
+	if err != nil || resp.(*wallet.BuildAndSignTransactionResponse).Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("sign = %v, err %v", resp, err)
+	}
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		if span.SpanContext.TraceID().String() != traceID {
+			t.Fatalf("span %s is not in the incoming trace", span.Name)
+		}
+		spans[span.Name] = span
+	}
+	server, ok := spans["/dapplink.wallet.WalletService/buildAndSignTransaction"]
+	if !ok || server.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("server span does not continue the incoming trace: %v", spans)
+	}
+	for _, name := range []string{"leveldb.GetIdempotencyRecord", "leveldb.GetKey", "Ethereum.build", "Ethereum.sign", "hsm.sign", "leveldb.PutIdempotencyRecord"} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("missing span %s, got %v", name, names(exporter.GetSpans()))
+		}
+	}
+	if spans["hsm.sign"].Parent.SpanID() != spans["Ethereum.sign"].SpanContext.SpanID() {
+		t.Fatalf("kms call is not a child of the sign phase")
+	}
+}
+
+func names(spans tracetest.SpanStubs) []string {
+	out := make([]string, 0, len(spans))
+	for _, span := range spans {
+		out = append(out, span.Name)
+	}
+	return out
+}
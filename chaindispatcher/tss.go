@@ -0,0 +1,107 @@
+package chaindispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/chain/bitcoin"
+	"github.com/0xshin-chan/wallet-sign/chain/solana"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/frost"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tss"
+)
+
+// errTssInProcess 参与方运行在同一进程、分片在同一 key store 中，任何一方都能拿到足够的分片，不能用于生产环境
+var errTssInProcess = errors.New("all threshold signing parties run in this process with their shares in one key store, " +
+	"which is for testing only; set insecure_in_process to enable it")
+
+// newTssSigner 在本进程内启动所有门限签名参与方，通过内存 transport 通信，只用于测试。
+// 各参与方的分片分别保存，但仍在同一个 key store 中；要真正隔离分片需要把参与方部署到不同进程并实现网络 transport
+func newTssSigner(conf config.TssConfig, db *leveldb.Keys) (ssm.Signer, error) {
+	if !conf.InsecureInProcess {
+		return nil, errTssInProcess
+	}
+	if conf.Threshold < 2 || conf.Threshold > conf.Parties {
+		return nil, fmt.Errorf("invalid tss threshold %d of %d parties", conf.Threshold, conf.Parties)
+	}
+	coordinator, parties := startParties(conf.Parties, conf.PaillierBits, db)
+	log.Info("tss signer started", "threshold", conf.Threshold, "parties", conf.Parties)
+	return tss.NewSigner(coordinator, conf.Threshold, parties, &ssm.ECDSASigner{}), nil
+}
+
+// chainFrostSuites 支持 FROST 的链使用的密码套件
+var chainFrostSuites = map[string]string{
+	solana.ChainName:  frost.SuiteEd25519,
+	bitcoin.ChainName: frost.SuiteSecp256k1TR,
+}
+
+// newFrostSigners 与 newTssSigner 一样在本进程内启动所有参与方，只用于测试，返回 conf.Chains 中每条链的 FROST 签名器
+func newFrostSigners(conf config.FrostConfig, db *leveldb.Keys) (map[string]ssm.Signer, error) {
+	if !conf.InsecureInProcess {
+		return nil, errTssInProcess
+	}
+	if conf.Threshold < 2 || conf.Threshold > conf.Parties {
+		return nil, fmt.Errorf("invalid frost threshold %d of %d parties", conf.Threshold, conf.Parties)
+	}
+	coordinator, parties := startParties(conf.Parties, 0, db)
+	signers := make(map[string]ssm.Signer, len(conf.Chains))
+	for _, chainName := range conf.Chains {
+		suite, ok := chainFrostSuites[chainName]
+		if !ok {
+			return nil, fmt.Errorf("chain %s does not support frost", chainName)
+		}
+		signers[chainName] = tss.NewFrostSigner(coordinator, suite, conf.Threshold, parties, chainSigners[chainName])
+	}
+	log.Info("frost signer started", "threshold", conf.Threshold, "parties", conf.Parties, "chains", conf.Chains)
+	return signers, nil
+}
+
+// RefreshFrostKey 在本进程内启动所有 FROST 参与方，刷新 key store 中一个 FROST 密钥的全部分片，群公钥和地址不变。
+// 参与方与 rpc 服务共用 key store，需要在 rpc 服务停止时执行
+func RefreshFrostKey(ctx context.Context, conf config.FrostConfig, db *leveldb.Keys, chainName string, publicKey string) error {
+	if !conf.Enabled {
+		return errors.New("frost is not enabled")
+	}
+	record, err := db.GetKey(ctx, chainName, publicKey)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(record.PrivateKey, tss.FrostKeyIDPrefix) {
+		return fmt.Errorf("key %s is not a frost key", publicKey)
+	}
+	signers, err := newFrostSigners(conf, db)
+	if err != nil {
+		return err
+	}
+	signer, ok := signers[chainName].(*tss.FrostSigner)
+	if !ok {
+		return fmt.Errorf("frost is not enabled for chain %s", chainName)
+	}
+	return signer.Refresh(record.PrivateKey)
+}
+
+// startParties 启动 1..count 号参与方，返回作为发起方的 1 号参与方
+func startParties(count int, paillierBits int, db *leveldb.Keys) (*tss.Node, []int) {
+	network := tss.NewMemoryNetwork()
+	parties := make([]int, 0, count)
+	var coordinator *tss.Node
+	for id := 1; id <= count; id++ {
+		node := tss.NewNode(id, network.Join(id), tss.NewLevelShareStore(db, id), paillierBits)
+		go func() {
+			if err := node.Run(context.Background()); err != nil {
+				log.Error("tss party stopped", "party", node.ID(), "err", err)
+			}
+		}()
+		if coordinator == nil {
+			coordinator = node
+		}
+		parties = append(parties, id)
+	}
+	return coordinator, parties
+}
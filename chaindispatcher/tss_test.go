@@ -0,0 +1,68 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/0xshin-chan/wallet-sign/chain/solana"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tss"
+)
+
+func TestRefreshFrostKey(t *testing.T) {
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := config.FrostConfig{Enabled: true, Threshold: 2, Parties: 3, Chains: []string{solana.ChainName}}
+	// 单进程部署不提供门限安全性，需要显式确认
+	if _, err := newFrostSigners(conf, db); err != errTssInProcess {
+		t.Fatalf("err = %v, want errTssInProcess", err)
+	}
+	conf.InsecureInProcess = true
+	signers, err := newFrostSigners(conf, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := signers[solana.ChainName]
+	keyID, pubKey, _, err := signer.CreateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if !db.StoreKeys(ctx, []leveldb.Key{{Chain: solana.ChainName, Curve: ssm.CurveEd25519, PublicKey: pubKey, PrivateKey: keyID}}) {
+		t.Fatal("store key fail")
+	}
+	before, err := tss.NewLevelShareStore(db, 1).LoadFrostShare(keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RefreshFrostKey(ctx, conf, db, solana.ChainName, pubKey); err != nil {
+		t.Fatal(err)
+	}
+	after, err := tss.NewLevelShareStore(db, 1).LoadFrostShare(keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Secret.Cmp(before.Secret) == 0 || !bytes.Equal(after.GroupKey, before.GroupKey) {
+		t.Fatal("share was not refreshed under the same group key")
+	}
+	// 刷新后的分片仍然产生同一公钥的签名
+	msg := hex.EncodeToString([]byte("refreshed"))
+	sig, err := signer.SignMessage(keyID, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := signer.VerifySignature(pubKey, msg, sig); err != nil || !ok {
+		t.Fatalf("verify = %v, err %v", ok, err)
+	}
+
+	if err := RefreshFrostKey(ctx, config.FrostConfig{}, db, solana.ChainName, pubKey); err == nil {
+		t.Fatal("expected refresh to fail when frost is disabled")
+	}
+}
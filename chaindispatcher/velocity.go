@@ -0,0 +1,158 @@
+package chaindispatcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+	"github.com/0xshin-chan/wallet-sign/velocity"
+)
+
+// newLimiter 按配置创建滚动窗口限额，没有配置时返回 nil
+func newLimiter(db *leveldb.Keys, limits []config.VelocityLimitConfig) (*velocity.Limiter, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+	parsed := make([]velocity.Limit, 0, len(limits))
+	for _, limitConf := range limits {
+		limit := velocity.Limit{
+			Name:     limitConf.Name,
+			Scope:    limitConf.Scope,
+			Chain:    limitConf.Chain,
+			Consumer: limitConf.Consumer,
+			Asset:    limitConf.Asset,
+			MaxCount: limitConf.MaxCount,
+			Window:   limitConf.Window,
+		}
+		if limitConf.MaxAmount != "" {
+			amount, ok := new(big.Int).SetString(limitConf.MaxAmount, 10)
+			if !ok || amount.Sign() < 0 {
+				return nil, fmt.Errorf("velocity limit %s: invalid max_amount %q", limitConf.Name, limitConf.MaxAmount)
+			}
+			limit.MaxAmount = amount
+		}
+		parsed = append(parsed, limit)
+	}
+	return velocity.NewLimiter(db, parsed)
+}
+
+// reserveVelocity 签名前预占限额，txBase64Body 为空表示原始消息签名，只计次数。
+// 拒绝时返回原因码和错误信息；签名失败时调用方需要撤销返回的预占
+func (d *ChainDispatcher) reserveVelocity(ctx context.Context, chainName string, publicKey string, txBase64Body string) (*velocity.Reservation, string, string) {
+	if d.limiter == nil {
+		return nil, "", ""
+	}
+	signing := velocity.Signing{Chain: chainName, PublicKey: publicKey, Consumer: consumer.FromContext(ctx)}
+	if txBase64Body != "" {
+		intents, err := d.registry[chainName].DecodeIntents(publicKey, txBase64Body)
+		if err != nil {
+			log.Warn("decode transaction intents fail", "chain", chainName, "err", err)
+			return nil, policy.ReasonDecodeFailed, "decode transaction fail: " + err.Error()
+		}
+		signing.Intents = intents
+	}
+	// 限额计数读写 key store
+	_, span := tracing.Start(ctx, "velocity.Reserve")
+	reservation, err := d.limiter.Reserve(signing)
+	tracing.End(span, err)
+	if err != nil {
+		log.Warn("velocity limit check fail", "chain", chainName, "consumer", signing.Consumer, "err", err)
+		return nil, velocity.ReasonLimitExceeded, err.Error()
+	}
+	return reservation, "", ""
+}
+
+// reserveBatchVelocity 批量交易每笔按一次签名预占，任何一笔被拒绝时撤销已经预占的额度
+func (d *ChainDispatcher) reserveBatchVelocity(ctx context.Context, chainName string, txMsgs []*wallet.TransactionMessage) ([]*velocity.Reservation, string, string) {
+	reservations := make([]*velocity.Reservation, 0, len(txMsgs))
+	for _, txMsg := range txMsgs {
+		reservation, reason, message := d.reserveVelocity(ctx, chainName, txMsg.PublicKey, txMsg.TxBase64Body)
+		if reason != "" {
+			settleBatchVelocity(reservations, false)
+			return nil, reason, message
+		}
+		reservations = append(reservations, reservation)
+	}
+	return reservations, "", ""
+}
+
+func settleBatchVelocity(reservations []*velocity.Reservation, signed bool) {
+	for _, reservation := range reservations {
+		settleVelocity(reservation, signed)
+	}
+}
+
+// settleVelocity 签名没有成功时归还预占的额度
+func settleVelocity(reservation *velocity.Reservation, signed bool) {
+	if signed {
+		return
+	}
+	if err := reservation.Cancel(); err != nil {
+		log.Error("cancel velocity reservation fail", "err", err)
+	}
+}
+
+func (d *ChainDispatcher) GetLimitUsage(ctx context.Context, request *wallet.LimitUsageRequest) (*wallet.LimitUsageResponse, error) {
+	ctx, resp := d.preHandler(ctx, request)
+	if resp != nil {
+		return &wallet.LimitUsageResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+	// 只能查询自己的密钥
+	if request.PublicKey != "" {
+		if _, err := d.loadOwnedKey(ctx, request.ChainName, request.PublicKey); err != nil {
+			return &wallet.LimitUsageResponse{
+				Code:    wallet.ReturnCode_ERROR,
+				Message: err.Error(),
+			}, nil
+		}
+	}
+	if d.limiter == nil {
+		return &wallet.LimitUsageResponse{
+			Code:    wallet.ReturnCode_SUCCESS,
+			Message: "no velocity limit configured",
+		}, nil
+	}
+	usages, err := d.limiter.Usage(request.ChainName, request.PublicKey, consumer.FromContext(ctx))
+	if err != nil {
+		log.Error("get velocity usage fail", "err", err)
+		return &wallet.LimitUsageResponse{
+			Code:    wallet.ReturnCode_ERROR,
+			Message: "get limit usage fail",
+		}, nil
+	}
+	result := make([]*wallet.LimitUsage, 0, len(usages))
+	for _, usage := range usages {
+		item := &wallet.LimitUsage{
+			Name:           usage.Limit.Name,
+			Scope:          usage.Limit.Scope,
+			Subject:        usage.Subject,
+			Asset:          usage.Limit.Asset,
+			WindowSeconds:  int64(usage.Limit.Window.Seconds()),
+			UsedCount:      uint64(usage.UsedCount),
+			MaxCount:       uint64(usage.Limit.MaxCount),
+			RemainingCount: uint64(usage.RemainCount),
+			UsedAmount:     usage.UsedAmount.String(),
+		}
+		if usage.Limit.MaxAmount != nil {
+			item.MaxAmount = usage.Limit.MaxAmount.String()
+			item.RemainingAmount = usage.RemainAmount.String()
+		}
+		result = append(result, item)
+	}
+	return &wallet.LimitUsageResponse{
+		Code:    wallet.ReturnCode_SUCCESS,
+		Message: "get limit usage success",
+		Usages:  result,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package chaindispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/chain"
+	"github.com/0xshin-chan/wallet-sign/chain/ethereum"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+	"github.com/0xshin-chan/wallet-sign/velocity"
+)
+
+func TestVelocityLimitOnSigning(t *testing.T) {
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}},
+		VelocityLimits: []config.VelocityLimitConfig{
+			{Name: "per-minute", Scope: velocity.ScopeConsumer, MaxCount: 2, Window: time.Minute},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.limiter, err = newLimiter(db, conf.VelocityLimits); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	sign := func(publicKey string) *wallet.SignTransactionMessageResponse {
+		resp, err := d.SignTransactionMessage(ctx, &wallet.SignTransactionMessageRequest{
+			ConsumerToken: "token",
+			ChainName:     ethereum.ChainName,
+			PublicKey:     publicKey,
+			MessageHash:   hex.EncodeToString(crypto.Keccak256([]byte("tx"))),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	publicKey := keys.PublicKeyAddresses[0].PublicKey
+	// 签名失败不占用次数
+	if resp := sign("04unknown"); resp.Code != wallet.ReturnCode_ERROR {
+		t.Fatal("expected an unknown key to fail")
+	}
+	// 审计记录写入失败时不返回签名，归还占用的次数并计入指标
+	auditLog, err := audit.Open(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog.Close()
+	d.audit = auditLog
+	failures := metricValue(t, "wallet_sign_audit_write_failures_total", map[string]string{"method": "signTransactionMessage"})
+	if resp := sign(publicKey); resp.Code != wallet.ReturnCode_ERROR || resp.Signature != "" {
+		t.Fatalf("audit failure = %v", resp)
+	}
+	if got := metricValue(t, "wallet_sign_audit_write_failures_total", map[string]string{"method": "signTransactionMessage"}); got != failures+1 {
+		t.Fatalf("audit failures = %v, want %v", got, failures+1)
+	}
+	d.audit = nil
+	for i := 0; i < 2; i++ {
+		if resp := sign(publicKey); resp.Code != wallet.ReturnCode_SUCCESS {
+			t.Fatalf("signature %d: %s", i, resp.Message)
+		}
+	}
+	if resp := sign(publicKey); resp.Code != wallet.ReturnCode_ERROR || resp.RejectReason != velocity.ReasonLimitExceeded {
+		t.Fatalf("over limit = %v", resp)
+	}
+
+	usage, err := d.GetLimitUsage(ctx, &wallet.LimitUsageRequest{ConsumerToken: "token", ChainName: ethereum.ChainName})
+	if err != nil || usage.Code != wallet.ReturnCode_SUCCESS || len(usage.Usages) != 1 {
+		t.Fatalf("usage = %v, err %v", usage, err)
+	}
+	if u := usage.Usages[0]; u.Subject != "exchange" || u.UsedCount != 2 || u.RemainingCount != 0 || u.WindowSeconds != 60 {
+		t.Fatalf("usage = %v", u)
+	}
+}
+
+// TestVelocityLimitOnBatchSigning 批量签名每笔交易计一次签名，超过限额时整批拒绝且不占用额度
+func TestVelocityLimitOnBatchSigning(t *testing.T) {
+	walletSecret, riskSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	t.Setenv("TEST_WALLET_APPROVAL_KEY", hex.EncodeToString(walletSecret))
+	t.Setenv("TEST_RISK_APPROVAL_KEY", hex.EncodeToString(riskSecret))
+	conf := &config.Config{
+		Consumers: []consumer.Consumer{{Name: "exchange", TokenHash: consumer.HashToken("token")}},
+		Approval: config.ApprovalConfig{
+			WalletKeys: []config.ApprovalKeyConfig{{Id: "w1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_WALLET_APPROVAL_KEY"}},
+			RiskKeys:   []config.ApprovalKeyConfig{{Id: "r1", Type: riskkey.TypeHmacSha256, SecretEnv: "TEST_RISK_APPROVAL_KEY"}},
+		},
+		VelocityLimits: []config.VelocityLimitConfig{
+			{Name: "per-minute", Scope: velocity.ScopeConsumer, MaxCount: 2, Window: time.Minute},
+		},
+	}
+	db, err := leveldb.NewKeyStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptor, err := ethereum.NewChainAdaptor(conf, db, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &ChainDispatcher{registry: map[string]chain.IChainAdaptor{ethereum.ChainName: adaptor}, db: db}
+	if d.consumers, err = loadConsumers(conf, db); err != nil {
+		t.Fatal(err)
+	}
+	if d.walletKeys, err = newApprovalVerifier("wallet", conf.Approval.WalletKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.riskKeys, err = newApprovalVerifier("risk", conf.Approval.RiskKeys); err != nil {
+		t.Fatal(err)
+	}
+	if d.limiter, err = newLimiter(db, conf.VelocityLimits); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	keys, err := d.CreateKeyPairsWithAddresses(ctx, &wallet.CreateKeyPairsWithAddressesRequest{ConsumerToken: "token", ChainName: ethereum.ChainName, KeyNum: 1})
+	if err != nil || keys.Code != wallet.ReturnCode_SUCCESS {
+		t.Fatalf("create key = %v, err %v", keys, err)
+	}
+	publicKey := keys.PublicKeyAddresses[0].PublicKey
+	batch := func(n int) *wallet.BuildAndSignBatchTransactionResponse {
+		request := &wallet.BuildAndSignBatchTransactionRequest{ConsumerToken: "token", ChainName: ethereum.ChainName}
+		for i := 0; i < n; i++ {
+			body := []byte(fmt.Sprintf(`{"chain_id":"1","nonce":%d,"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D",`+
+				`"gas_limit":21000,"max_fee_per_gas":"30000000000","max_priority_fee_per_gas":"1000000000","amount":"1"}`, i))
+			request.TxMsg = append(request.TxMsg, &wallet.TransactionMessage{
+				PublicKey:     publicKey,
+				WalletKeyHash: riskkey.HmacApproval("w1", walletSecret, body),
+				RiskKeyHash:   riskkey.HmacApproval("r1", riskSecret, body),
+				TxBase64Body:  base64.StdEncoding.EncodeToString(body),
+			})
+		}
+		resp, err := d.BuildAndSignBatchTransaction(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	usedCount := func() uint64 {
+		usage, err := d.GetLimitUsage(ctx, &wallet.LimitUsageRequest{ConsumerToken: "token", ChainName: ethereum.ChainName})
+		if err != nil || len(usage.Usages) != 1 {
+			t.Fatalf("usage = %v, err %v", usage, err)
+		}
+		return usage.Usages[0].UsedCount
+	}
+
+	// adaptor 没有实现批量签名时在预占之前拒绝
+	if resp := batch(1); resp.Code != wallet.ReturnCode_ERROR || resp.Message != chain.UnsupportedBatchSigning || usedCount() != 0 {
+		t.Fatalf("unsupported batch = %v, used %d", resp, usedCount())
+	}
+	d.registry[ethereum.ChainName] = batchAdaptor{adaptor}
+	if resp := batch(1); resp.Code != wallet.ReturnCode_SUCCESS || len(resp.TxWithSign) != 1 || usedCount() != 1 {
+		t.Fatalf("batch = %v, used %d", resp, usedCount())
+	}
+	// 三笔超过每分钟两次，整批拒绝，前两笔的预占被撤销
+	if resp := batch(3); resp.Code != wallet.ReturnCode_ERROR || resp.RejectReason != velocity.ReasonLimitExceeded {
+		t.Fatalf("batch over limit = %v", resp)
+	}
+	if used := usedCount(); used != 1 {
+		t.Fatalf("rejected batch used %d signatures", used-1)
+	}
+	// 单笔签名用完额度后，批量签名同样被拒绝
+	for i := 0; i < 1; i++ {
+		resp, err := d.SignTransactionMessage(ctx, &wallet.SignTransactionMessageRequest{
+			ConsumerToken: "token",
+			ChainName:     ethereum.ChainName,
+			PublicKey:     publicKey,
+			MessageHash:   hex.EncodeToString(crypto.Keccak256([]byte("tx"))),
+		})
+		if err != nil || resp.Code != wallet.ReturnCode_SUCCESS {
+			t.Fatalf("signature %d = %v, err %v", i, resp, err)
+		}
+	}
+	if resp := batch(1); resp.Code != wallet.ReturnCode_ERROR || resp.RejectReason != velocity.ReasonLimitExceeded {
+		t.Fatalf("batch after limit exhausted = %v", resp)
+	}
+}
+
+// batchAdaptor 模拟实现了批量签名的 adaptor，每笔交易返回一个假的签名结果
+type batchAdaptor struct {
+	chain.IChainAdaptor
+}
+
+func (batchAdaptor) SupportsBatchSigning() bool {
+	return true
+}
+
+func (batchAdaptor) BuildAndSignBatchTransaction(_ context.Context, request *wallet.BuildAndSignBatchTransactionRequest) (*wallet.BuildAndSignBatchTransactionResponse, error) {
+	resp := &wallet.BuildAndSignBatchTransactionResponse{Code: wallet.ReturnCode_SUCCESS}
+	for i := range request.TxMsg {
+		resp.TxWithSign = append(resp.TxWithSign, &wallet.TransactionWithSign{SignedTx: fmt.Sprintf("signed-%d", i)})
+	}
+	return resp, nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/audit"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+)
+
+// openAuditLog 只读打开审计日志，可以在 rpc 服务运行时执行
+func openAuditLog(ctx *cli.Context) (*os.File, error) {
+	path := ctx.String(flags.AuditFileFlag.Name)
+	if path == "" {
+		cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		path = cfg.AuditLog
+	}
+	if path == "" {
+		return nil, errors.New("audit_log is not configured")
+	}
+	return os.Open(path)
+}
+
+func runAuditVerify(ctx *cli.Context) error {
+	file, err := openAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	count, last, err := audit.Verify(file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("audit log ok: %d entries, last hash %s\n", count, last)
+	return nil
+}
+
+func parseTime(ctx *cli.Context, flag *cli.StringFlag) (time.Time, error) {
+	value := ctx.String(flag.Name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s: %w", flag.Name, err)
+	}
+	return at, nil
+}
+
+func runAuditExport(ctx *cli.Context) error {
+	rg := audit.Range{
+		FromSeq: ctx.Uint64(flags.FromSeqFlag.Name),
+		ToSeq:   ctx.Uint64(flags.ToSeqFlag.Name),
+	}
+	var err error
+	if rg.Since, err = parseTime(ctx, flags.SinceFlag); err != nil {
+		return err
+	}
+	if rg.Until, err = parseTime(ctx, flags.UntilFlag); err != nil {
+		return err
+	}
+	file, err := openAuditLog(ctx)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var out io.Writer = os.Stdout
+	if path := ctx.String(flags.ExportOutFlag.Name); path != "" {
+		outFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer outFile.Close()
+		out = outFile
+	}
+	count, err := audit.Export(file, out, rg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d entries\n", count)
+	return nil
+}
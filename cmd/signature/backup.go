@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/shamir"
+)
+
+// 分享内容的第一个字节标记秘密类型，恢复时据此决定写回的位置
+const (
+	secretKindSeed       byte = 1
+	secretKindKekFile    byte = 2
+	secretKindPassphrase byte = 3
+)
+
+// runBackupSplit 把主种子或 KEK 拆成 M-of-N 份额，每份写入单独的文件，份额内容不输出到终端和日志
+func runBackupSplit(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	kind, secret, err := loadMasterSecret(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	total := ctx.Int(flags.SharesFlag.Name)
+	shares, err := shamir.Split(append([]byte{kind}, secret...), ctx.Int(flags.ThresholdFlag.Name), total)
+	if err != nil {
+		return err
+	}
+	outDir := ctx.String(flags.OutDirFlag.Name)
+	if err := os.MkdirAll(outDir, 0o700); err != nil {
+		return err
+	}
+	for _, share := range shares {
+		path := filepath.Join(outDir, fmt.Sprintf("share-%d-of-%d.txt", share.Index, total))
+		if err := writeNewFile(path, []byte(share.Encode()+"\n")); err != nil {
+			return err
+		}
+		fmt.Println("wrote share", path)
+	}
+	return nil
+}
+
+// loadMasterSecret 读取要拆分的秘密：种子为解密后的原始种子，KEK 为文件中的 32 字节或口令本身，KMS 的 KEK 无法导出
+func loadMasterSecret(ctx *cli.Context, cfg *config.Config) (byte, []byte, error) {
+	switch ctx.String(flags.SecretFlag.Name) {
+	case "seed":
+		chainName := ctx.String(flags.SeedChainFlag.Name)
+		if chainName == "" {
+			return 0, nil, errors.New("--chain is required to split a seed")
+		}
+		passphrase := os.Getenv(cfg.HdWallet.PassphraseEnv)
+		if passphrase == "" {
+			return 0, nil, fmt.Errorf("seed passphrase is empty, set %s", cfg.HdWallet.PassphraseEnv)
+		}
+		db, err := leveldb.OpenKeyStore(cfg)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer db.Close()
+		seedName := cfg.SeedName(chainName)
+		encryptedSeed, ok := db.GetSeed(seedName)
+		if !ok {
+			return 0, nil, fmt.Errorf("seed %s not found", seedName)
+		}
+		seed, err := hdwallet.DecryptSeed(encryptedSeed, passphrase)
+		if err != nil {
+			return 0, nil, err
+		}
+		return secretKindSeed, seed, nil
+	case "kek":
+		switch cfg.KeyStore.Encryption {
+		case leveldb.EncryptionFile:
+			data, err := os.ReadFile(cfg.KeyStore.KekFile)
+			if err != nil {
+				return 0, nil, err
+			}
+			kek := data
+			if decoded, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil {
+				kek = decoded
+			}
+			return secretKindKekFile, kek, nil
+		case leveldb.EncryptionPassphrase:
+			passphrase := os.Getenv(cfg.KeyStore.PassphraseEnv)
+			if passphrase == "" {
+				return 0, nil, fmt.Errorf("key store passphrase is empty, set %s", cfg.KeyStore.PassphraseEnv)
+			}
+			return secretKindPassphrase, []byte(passphrase), nil
+		case leveldb.EncryptionKms:
+			return 0, nil, errors.New("kms kek never leaves the kms and cannot be split")
+		default:
+			return 0, nil, errors.New("key store encryption is not enabled")
+		}
+	default:
+		return 0, nil, fmt.Errorf("unsupported secret %q, use seed or kek", ctx.String(flags.SecretFlag.Name))
+	}
+}
+
+// runBackupRestore 用足够数量的份额恢复秘密：种子重新加密写回 key store，KEK 写到 --kek-out 指定的文件
+func runBackupRestore(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	var shares []*shamir.Share
+	for _, path := range ctx.StringSlice(flags.ShareFileFlag.Name) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		share, err := shamir.Decode(string(data))
+		if err != nil {
+			return fmt.Errorf("share %s: %w", path, err)
+		}
+		shares = append(shares, share)
+	}
+	payload, err := shamir.Combine(shares)
+	if err != nil {
+		return err
+	}
+	kind, secret := payload[0], payload[1:]
+
+	switch kind {
+	case secretKindSeed:
+		chainName := ctx.String(flags.SeedChainFlag.Name)
+		if chainName == "" {
+			return errors.New("--chain is required to restore a seed")
+		}
+		passphrase := os.Getenv(cfg.HdWallet.PassphraseEnv)
+		if passphrase == "" {
+			return fmt.Errorf("seed passphrase is empty, set %s", cfg.HdWallet.PassphraseEnv)
+		}
+		encryptedSeed, err := hdwallet.EncryptSeed(secret, passphrase)
+		if err != nil {
+			return err
+		}
+		db, err := leveldb.OpenKeyStore(cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		seedName := cfg.SeedName(chainName)
+		if err := db.StoreSeed(seedName, encryptedSeed); err != nil {
+			return fmt.Errorf("store seed %s: %w", seedName, err)
+		}
+		fmt.Println("seed restored:", seedName)
+	case secretKindKekFile, secretKindPassphrase:
+		out := ctx.String(flags.KekOutFlag.Name)
+		if out == "" {
+			return errors.New("--kek-out is required to restore a kek")
+		}
+		data := secret
+		if kind == secretKindKekFile {
+			data = []byte(hex.EncodeToString(secret))
+		}
+		if err := writeNewFile(out, data); err != nil {
+			return err
+		}
+		if kind == secretKindKekFile {
+			fmt.Println("kek file restored:", out)
+		} else {
+			fmt.Println("key store passphrase restored:", out)
+		}
+	default:
+		return fmt.Errorf("unknown secret kind %d", kind)
+	}
+	return nil
+}
+
+// writeNewFile 只创建新文件，避免覆盖已有的份额或密钥
+func writeNewFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -39,6 +39,175 @@ func NewCli() *cli.App {
 				Description: "Run rpc services",
 				Action:      cliapp.LifecycleCmd(runRpc),
 			},
+			{
+				Name:        "seed",
+				Description: "Manage hd wallet master seeds",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "init",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.MnemonicFileFlag},
+						Description: "Create or import the master seed of a chain",
+						Action:      runSeedInit,
+					},
+				},
+			},
+			{
+				Name:        "keystore",
+				Description: "Manage the local key store",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "migrate",
+						Flags:       []cli.Flag{flags2.ConfigFlag},
+						Description: "Encrypt plaintext private keys in place with the configured key encryption key",
+						Action:      runKeyStoreMigrate,
+					},
+					{
+						Name:        "migrate-legacy",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.PublicKeysFileFlag},
+						Description: "Copy keys stored under bare public keys into the namespace of a chain",
+						Action:      runKeyStoreMigrateLegacy,
+					},
+					{
+						Name:        "purge-legacy",
+						Flags:       []cli.Flag{flags2.ConfigFlag},
+						Description: "Delete bare public key records that have been migrated to a chain",
+						Action:      runKeyStorePurgeLegacy,
+					},
+				},
+			},
+			{
+				Name:        "key",
+				Description: "Import private keys and export encrypted backups",
+				Subcommands: []*cli.Command{
+					{
+						Name: "import",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.KeyFormatFlag, flags2.InputFileFlag,
+							flags2.NetworkFlag, flags2.LabelFlag, flags2.ConsumerFlag, flags2.PasswordEnvFlag},
+						Description: "Import existing private keys into the key store of a chain",
+						Action:      runKeyImport,
+					},
+					{
+						Name: "export",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.PublicKeysFileFlag, flags2.OutputFileFlag,
+							flags2.ConsumerFlag, flags2.PasswordEnvFlag},
+						Description: "Export the listed keys to a password protected backup file",
+						Action:      runKeyExport,
+					},
+				},
+			},
+			{
+				Name:        "backup",
+				Description: "Split the master seed or kek into shamir shares and restore it",
+				Subcommands: []*cli.Command{
+					{
+						Name: "split",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.SecretFlag, flags2.SeedChainFlag, flags2.ThresholdFlag,
+							flags2.SharesFlag, flags2.OutDirFlag},
+						Description: "Split a master secret into M-of-N shares, one file per share",
+						Action:      runBackupSplit,
+					},
+					{
+						Name:        "restore",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ShareFileFlag, flags2.SeedChainFlag, flags2.KekOutFlag},
+						Description: "Rebuild a master secret from a quorum of shares",
+						Action:      runBackupRestore,
+					},
+				},
+			},
+			{
+				Name:        "consumer",
+				Description: "Manage rpc consumers and their tokens",
+				Subcommands: []*cli.Command{
+					{
+						Name: "add",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.ConsumerNameFlag, flags2.AllowedChainsFlag, flags2.AllowedMethodsFlag,
+							flags2.TokenTtlFlag},
+						Description: "Create a consumer and print its token",
+						Action:      runConsumerAdd,
+					},
+					{
+						Name:        "list",
+						Flags:       []cli.Flag{flags2.ConfigFlag},
+						Description: "List consumers from the config file and the key store",
+						Action:      runConsumerList,
+					},
+					{
+						Name:        "disable",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ConsumerNameFlag},
+						Description: "Disable a consumer stored in the key store",
+						Action:      runConsumerDisable,
+					},
+				},
+			},
+			{
+				Name:        "hsm",
+				Description: "Provision Cloud KMS keys and register them in the key store",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "create-key-ring",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ProjectFlag, flags2.LocationFlag, flags2.KeyRingIdFlag},
+						Description: "Create a key ring",
+						Action:      runHsmCreateKeyRing,
+					},
+					{
+						Name: "create-key",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.ProjectFlag, flags2.LocationFlag, flags2.KeyRingIdFlag,
+							flags2.KeyIdFlag, flags2.AlgorithmFlag},
+						Description: "Create an HSM protected signing key in a key ring",
+						Action:      runHsmCreateKey,
+					},
+					{
+						Name:        "list-versions",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.KmsKeyFlag},
+						Description: "List the versions of a key",
+						Action:      runHsmListVersions,
+					},
+					{
+						Name:        "public-key",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.KeyVersionFlag, flags2.AddressChainFlag, flags2.AddressFormatFlag},
+						Description: "Print the public key of a key version and optionally its chain address",
+						Action:      runHsmPublicKey,
+					},
+					{
+						Name: "register",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.KeyVersionFlag, flags2.AddressFormatFlag,
+							flags2.NetworkFlag, flags2.LabelFlag, flags2.ConsumerFlag},
+						Description: "Register a key version and its chain address in the key store of a chain",
+						Action:      runHsmRegister,
+					},
+				},
+			},
+			{
+				Name:        "frost",
+				Description: "Maintain threshold schnorr keys",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "refresh",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.ChainFlag, flags2.PublicKeyFlag},
+						Description: "Replace every share of a frost key, the group public key and address stay the same",
+						Action:      runFrostRefresh,
+					},
+				},
+			},
+			{
+				Name:        "audit",
+				Description: "Verify and export the hash chained audit log",
+				Subcommands: []*cli.Command{
+					{
+						Name:        "verify",
+						Flags:       []cli.Flag{flags2.ConfigFlag, flags2.AuditFileFlag},
+						Description: "Check that no entry of the audit log was modified, removed or reordered",
+						Action:      runAuditVerify,
+					},
+					{
+						Name: "export",
+						Flags: []cli.Flag{flags2.ConfigFlag, flags2.AuditFileFlag, flags2.FromSeqFlag, flags2.ToSeqFlag,
+							flags2.SinceFlag, flags2.UntilFlag, flags2.ExportOutFlag},
+						Description: "Verify the audit log and export a range of entries as JSONL",
+						Action:      runAuditExport,
+					},
+				},
+			},
 			{
 				Name:        "version",
 				Description: "Show project version",
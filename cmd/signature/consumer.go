@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// runConsumerAdd 在数据库中创建调用方并输出 token，token 只在此时显示一次，需要在 rpc 服务停止时执行
+func runConsumerAdd(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	name := ctx.String(flags.ConsumerNameFlag.Name)
+	if _, err := db.GetConsumer(name); err == nil {
+		return fmt.Errorf("consumer %s already exists", name)
+	} else if !errors.Is(err, leveldb.ErrConsumerNotFound) {
+		return err
+	}
+	for _, c := range cfg.Consumers {
+		if c.Name == name {
+			return fmt.Errorf("consumer %s is defined in the config file", name)
+		}
+	}
+	token, err := consumer.GenerateToken()
+	if err != nil {
+		return err
+	}
+	c := &consumer.Consumer{
+		Name:      name,
+		TokenHash: consumer.HashToken(token),
+		Chains:    ctx.StringSlice(flags.AllowedChainsFlag.Name),
+		Methods:   ctx.StringSlice(flags.AllowedMethodsFlag.Name),
+	}
+	if ttl := ctx.Duration(flags.TokenTtlFlag.Name); ttl > 0 {
+		c.ExpiresAt = time.Now().Add(ttl).Unix()
+	}
+	if err := db.PutConsumer(c); err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+func runConsumerList(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	stored, err := db.ListConsumers()
+	if err != nil {
+		return err
+	}
+	printConsumer := func(source string, c consumer.Consumer) {
+		expires := "never"
+		if c.ExpiresAt != 0 {
+			expires = time.Unix(c.ExpiresAt, 0).UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("%s\t%s\tdisabled=%v\texpires=%s\tchains=%s\tmethods=%s\n", c.Name, source, c.Disabled, expires,
+			strings.Join(c.Chains, ","), strings.Join(c.Methods, ","))
+	}
+	for _, c := range cfg.Consumers {
+		printConsumer("config", c)
+	}
+	for _, c := range stored {
+		printConsumer("db", c)
+	}
+	return nil
+}
+
+// runConsumerDisable 停用数据库中的调用方，配置文件中的调用方需要修改配置
+func runConsumerDisable(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	c, err := db.GetConsumer(ctx.String(flags.ConsumerNameFlag.Name))
+	if err != nil {
+		return err
+	}
+	c.Disabled = true
+	if err := db.PutConsumer(c); err != nil {
+		return err
+	}
+	fmt.Println("disabled consumer", c.Name)
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/chaindispatcher"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// runFrostRefresh 刷新一个 FROST 密钥的全部分片，需要在 rpc 服务停止时执行
+func runFrostRefresh(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	chainName, publicKey := ctx.String(flags.ChainFlag.Name), ctx.String(flags.PublicKeyFlag.Name)
+	if err := chaindispatcher.RefreshFrostKey(ctx.Context, cfg.Frost, db, chainName, publicKey); err != nil {
+		return err
+	}
+	fmt.Println("refreshed frost key:", publicKey)
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/chaindispatcher"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// newHsmClient 使用 key_path 中的凭据连接 Cloud KMS
+func newHsmClient(ctx *cli.Context) (*config.Config, *hsm.HsmClient, error) {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := hsm.NewHSMClient(context.Background(), cfg.KeyPath, cfg.KeyName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, client, nil
+}
+
+func runHsmCreateKeyRing(ctx *cli.Context) error {
+	_, client, err := newHsmClient(ctx)
+	if err != nil {
+		return err
+	}
+	project, location := ctx.String(flags.ProjectFlag.Name), ctx.String(flags.LocationFlag.Name)
+	keyRing, err := client.CreateKeyRing(project, location, ctx.String(flags.KeyRingIdFlag.Name))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("projects/%s/locations/%s/keyRings/%s\n", project, location, keyRing)
+	return nil
+}
+
+func runHsmCreateKey(ctx *cli.Context) error {
+	_, client, err := newHsmClient(ctx)
+	if err != nil {
+		return err
+	}
+	keyName, err := client.CreateKeyPair(ctx.String(flags.ProjectFlag.Name), ctx.String(flags.LocationFlag.Name),
+		ctx.String(flags.KeyRingIdFlag.Name), ctx.String(flags.KeyIdFlag.Name), ctx.String(flags.AlgorithmFlag.Name))
+	if err != nil {
+		return err
+	}
+	fmt.Println(keyName)
+	return nil
+}
+
+func runHsmListVersions(ctx *cli.Context) error {
+	_, client, err := newHsmClient(ctx)
+	if err != nil {
+		return err
+	}
+	versions, err := client.ListKeyVersions(ctx.String(flags.KmsKeyFlag.Name))
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		fmt.Println(version.Name, version.State, version.Algorithm)
+	}
+	return nil
+}
+
+func runHsmPublicKey(ctx *cli.Context) error {
+	_, client, err := newHsmClient(ctx)
+	if err != nil {
+		return err
+	}
+	pubKey, err := client.PublicKey(ctx.String(flags.KeyVersionFlag.Name))
+	if err != nil {
+		return err
+	}
+	fmt.Println(hex.EncodeToString(pubKey))
+	if chainName := ctx.String(flags.AddressChainFlag.Name); chainName != "" {
+		address, err := chaindispatcher.ChainAddress(chainName, pubKey, ctx.String(flags.AddressFormatFlag.Name))
+		if err != nil {
+			return err
+		}
+		fmt.Println(address)
+	}
+	return nil
+}
+
+// runHsmRegister 把 KMS 密钥版本登记到链的 key store，需要在 rpc 服务停止时执行
+func runHsmRegister(ctx *cli.Context) error {
+	cfg, client, err := newHsmClient(ctx)
+	if err != nil {
+		return err
+	}
+	keyVersion := ctx.String(flags.KeyVersionFlag.Name)
+	pubKey, err := client.PublicKey(keyVersion)
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	record, err := chaindispatcher.RegisterHsmKey(ctx.Context, db, ctx.String(flags.ChainFlag.Name), chaindispatcher.HsmKeyOptions{
+		Backend:       hsm.BackendGcp,
+		KeyName:       keyVersion,
+		PublicKey:     pubKey,
+		AddressFormat: ctx.String(flags.AddressFormatFlag.Name),
+		Consumer:      ctx.String(flags.ConsumerFlag.Name),
+		Network:       ctx.String(flags.NetworkFlag.Name),
+		Label:         ctx.String(flags.LabelFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(record.PublicKey, record.Address)
+	return nil
+}
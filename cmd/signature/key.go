@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/chaindispatcher"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/keybackup"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// runKeyImport 把外部私钥导入指定链的 key store，需要在 rpc 服务停止时执行
+func runKeyImport(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(ctx.String(flags.InputFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	format := ctx.String(flags.KeyFormatFlag.Name)
+	var keys []string
+	switch format {
+	case keybackup.FormatKeystore, keybackup.FormatBackup:
+		keys = []string{string(data)}
+	default:
+		keys = strings.Fields(string(data))
+	}
+
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	records, err := chaindispatcher.ImportKeys(ctx.Context, db, ctx.String(flags.ChainFlag.Name), chaindispatcher.ImportOptions{
+		Consumer: ctx.String(flags.ConsumerFlag.Name),
+		Network:  ctx.String(flags.NetworkFlag.Name),
+		Format:   format,
+		Keys:     keys,
+		Password: os.Getenv(ctx.String(flags.PasswordEnvFlag.Name)),
+		Label:    ctx.String(flags.LabelFlag.Name),
+	})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		fmt.Println(record.PublicKey)
+	}
+	fmt.Println("imported keys:", len(records))
+	return nil
+}
+
+// runKeyExport 把指定公钥对应的私钥导出为口令加密的备份文件，需要在 rpc 服务停止时执行
+func runKeyExport(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	passwordEnv := ctx.String(flags.PasswordEnvFlag.Name)
+	password := os.Getenv(passwordEnv)
+	if password == "" {
+		return fmt.Errorf("backup password is empty, set %s", passwordEnv)
+	}
+	path := ctx.String(flags.PublicKeysFileFlag.Name)
+	if path == "" {
+		return errors.New("--public-keys-file is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	publicKeys := strings.Fields(string(data))
+	backup, err := chaindispatcher.ExportKeys(ctx.Context, db, ctx.String(flags.ChainFlag.Name), ctx.String(flags.ConsumerFlag.Name), publicKeys, password)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ctx.String(flags.OutputFileFlag.Name), backup, 0o600); err != nil {
+		return err
+	}
+	fmt.Println("exported keys:", len(publicKeys))
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/chaindispatcher"
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// runKeyStoreMigrate 按配置的 KEK 把已有的明文 key store 就地加密，需要在 rpc 服务停止时执行
+func runKeyStoreMigrate(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if !db.Encrypted() {
+		return errors.New("key_store.encryption is not configured")
+	}
+	count, err := db.MigrateEncryption()
+	if err != nil {
+		return err
+	}
+	fmt.Println("encrypted entries:", count)
+	return nil
+}
+
+// runKeyStoreMigrateLegacy 把旧版本以裸公钥存储的密钥复制到指定链的命名空间，需要在 rpc 服务停止时执行。
+// secp256k1 链必须用 --public-keys-file 列出属于该链的公钥
+func runKeyStoreMigrateLegacy(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	chainName := ctx.String(flags.ChainFlag.Name)
+	curve, ok := chaindispatcher.ChainCurve(chainName)
+	if !ok {
+		return fmt.Errorf("unsupported chain %s", chainName)
+	}
+	publicKeys := make(map[string]bool)
+	if path := ctx.String(flags.PublicKeysFileFlag.Name); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, publicKey := range strings.Fields(string(data)) {
+			publicKeys[publicKey] = true
+		}
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	count, err := db.MigrateLegacyKeys(chainName, curve, publicKeys)
+	if err != nil {
+		return err
+	}
+	fmt.Println("migrated keys:", count)
+	return nil
+}
+
+// runKeyStorePurgeLegacy 所有链都迁移完成后删除已经迁移过的旧版本记录，需要在 rpc 服务停止时执行
+func runKeyStorePurgeLegacy(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	count, err := db.PurgeLegacyKeys()
+	if err != nil {
+		return err
+	}
+	fmt.Println("purged legacy keys:", count)
+	return nil
+}
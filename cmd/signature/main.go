@@ -3,16 +3,17 @@ package main
 import (
 	"context"
 	"github.com/0xshin-chan/wallet-sign/common/opio"
+	"github.com/0xshin-chan/wallet-sign/common/redact"
 	"github.com/ethereum/go-ethereum/log"
 	"os"
 )
 
 func main() {
-	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+	log.SetDefault(log.NewLogger(redact.NewHandler(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true))))
 	app := NewCli()
 	ctx := opio.WithInterruptBlocker(context.Background())
 	if err := app.RunContext(ctx, os.Args); err != nil {
-		log.Error("Application failed")
+		log.Error("Application failed", "err", err)
 		os.Exit(1)
 	}
 }
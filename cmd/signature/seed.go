@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/flags"
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+// runSeedInit 为一条链创建或导入 HD 钱包主种子，需要在 rpc 服务停止时执行
+func runSeedInit(ctx *cli.Context) error {
+	cfg, err := config.NewConfig(ctx.String(flags.ConfigFlag.Name))
+	if err != nil {
+		return err
+	}
+	passphrase := os.Getenv(cfg.HdWallet.PassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("seed passphrase is empty, set %s", cfg.HdWallet.PassphraseEnv)
+	}
+
+	generated := false
+	var mnemonic string
+	if path := ctx.String(flags.MnemonicFileFlag.Name); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		mnemonic = strings.Join(strings.Fields(string(data)), " ")
+	} else {
+		mnemonic, err = hdwallet.NewMnemonic()
+		if err != nil {
+			return err
+		}
+		generated = true
+	}
+	seed, err := hdwallet.SeedFromMnemonic(mnemonic, "")
+	if err != nil {
+		return errors.New("invalid mnemonic")
+	}
+	encryptedSeed, err := hdwallet.EncryptSeed(seed, passphrase)
+	if err != nil {
+		return err
+	}
+
+	db, err := leveldb.OpenKeyStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	seedName := cfg.SeedName(ctx.String(flags.ChainFlag.Name))
+	if err := db.StoreSeed(seedName, encryptedSeed); err != nil {
+		return fmt.Errorf("store seed %s: %w", seedName, err)
+	}
+
+	fmt.Println("seed initialized:", seedName)
+	if generated {
+		fmt.Println("write down the mnemonic below, it is the only backup of every key derived from this seed:")
+		fmt.Println(mnemonic)
+	}
+	return nil
+}
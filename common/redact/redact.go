@@ -0,0 +1,172 @@
+// Package redact 在日志输出前去掉敏感字段。
+//
+// Handler 包装 slog.Handler，属性名是私钥、token、口令、种子或审批 hash 的值一律替换为 <redacted>；
+// Message 把 proto 请求和响应概括成一行，敏感字段不输出，交易请求体只输出长度和 hash 前缀。
+package redact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	Redacted = "<redacted>"
+
+	// 超过该长度的字符串只输出长度和 hash 前缀
+	maxValueLen = 80
+)
+
+// secretNames 敏感的日志属性名和 proto 字段名，比较时忽略大小写、下划线和连字符
+var secretNames = map[string]bool{
+	"privatekey":    true,
+	"privkey":       true,
+	"prikey":        true,
+	"privatekeys":   true,
+	"seed":          true,
+	"seedmaterial":  true,
+	"mnemonic":      true,
+	"secret":        true,
+	"password":      true,
+	"passphrase":    true,
+	"token":         true,
+	"consumertoken": true,
+	"admintoken":    true,
+	"walletkeyhash": true,
+	"riskkeyhash":   true,
+	"dek":           true,
+	"kek":           true,
+	// importKeys 的私钥列表和 exportKeys 的备份文件
+	"keys":   true,
+	"backup": true,
+}
+
+func normalize(name string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(name))
+}
+
+// IsSecret 属性名或字段名是否为敏感字段
+func IsSecret(name string) bool {
+	return secretNames[normalize(name)]
+}
+
+// Handler 输出前替换敏感属性的值
+type Handler struct {
+	next slog.Handler
+}
+
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, redactAttr(attr))
+	}
+	return &Handler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	if IsSecret(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, 0, len(group))
+		for _, a := range group {
+			redacted = append(redacted, redactAttr(a))
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		// 直接传入的 proto 请求或响应也按摘要输出
+		if m, ok := value.Any().(proto.Message); ok {
+			return slog.String(attr.Key, Message(m).String())
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// Summary proto 消息的日志摘要，可以直接作为日志属性的值
+type Summary struct {
+	m proto.Message
+}
+
+func Message(m proto.Message) Summary {
+	return Summary{m: m}
+}
+
+func (s Summary) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+func (s Summary) String() string {
+	if s.m == nil {
+		return "<nil>"
+	}
+	msg := s.m.ProtoReflect()
+	if !msg.IsValid() {
+		return "<nil>"
+	}
+	var parts []string
+	msg.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		parts = append(parts, string(field.Name())+"="+summarizeField(field, value))
+		return true
+	})
+	return string(msg.Descriptor().Name()) + "{" + strings.Join(parts, " ") + "}"
+}
+
+func summarizeField(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch {
+	case IsSecret(string(field.Name())):
+		return Redacted
+	case field.IsList():
+		return fmt.Sprintf("[%d items]", value.List().Len())
+	case field.IsMap():
+		return fmt.Sprintf("[%d entries]", value.Map().Len())
+	}
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return Message(value.Message().Interface()).String()
+	case protoreflect.BytesKind:
+		return summarize(value.Bytes())
+	case protoreflect.StringKind:
+		if len(value.String()) > maxValueLen {
+			return summarize([]byte(value.String()))
+		}
+		return value.String()
+	default:
+		return value.String()
+	}
+}
+
+// summarize 长内容只输出长度和 sha256 前缀，便于和其他记录对照
+func summarize(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("<%d bytes sha256:%s>", len(data), hex.EncodeToString(sum[:4]))
+}
@@ -0,0 +1,45 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewLogger(NewHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	body := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte(`{"to_address":"0x35096AD62E57e86032a3Bb35aDaCF2240d55421D"}`), 4))
+	secrets := []string{"supersecretprivatekey", "consumer-token-value", "risk:abcdef", "wallet:abcdef", "hunter2", "L1aW4aubDFB7yfras2S1mN3bqg9nwySY8nkoLmJebSLD5BWv3ENZ", body}
+
+	logger.Info("signing", "privKey", secrets[0], "consumer_token", secrets[1], "chain", "Ethereum")
+	logger.With("riskKeyHash", secrets[2]).Warn("approval", "wallet-key-hash", secrets[3])
+	logger.Debug("request", "req", Message(&wallet.BuildAndSignTransactionRequest{
+		ConsumerToken: secrets[1],
+		ChainName:     "Ethereum",
+		PublicKey:     "04ab",
+		WalletKeyHash: secrets[3],
+		RiskKeyHash:   secrets[2],
+		TxBase64Body:  body,
+	}))
+	logger.Info("import", "req", &wallet.ImportKeysRequest{AdminToken: secrets[1], Keys: []string{secrets[5]}, Password: secrets[4]})
+	logger.Info("group", slog.Group("key", "private_key", secrets[0], "public_key", "04ab"))
+
+	out := buf.String()
+	for _, secret := range secrets {
+		if strings.Contains(out, secret) {
+			t.Fatalf("log output contains %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"chain=Ethereum", "public_key=04ab", "tx_base64_body=<", "keys=<redacted>", "privKey=<redacted>"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("log output does not contain %q:\n%s", want, out)
+		}
+	}
+}
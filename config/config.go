@@ -2,17 +2,155 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/common/redact"
+	"github.com/0xshin-chan/wallet-sign/consumer"
 )
 
 type ServerConfig struct {
+	Host string    `yaml:"host"`
+	Port int       `yaml:"port"`
+	Tls  TlsConfig `yaml:"tls"`
+}
+
+// TracingConfig 通过 OTLP/gRPC 导出 span，未开启时不产生 span
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// OTLP collector 地址，例如 localhost:4317
+	Endpoint string `yaml:"endpoint"`
+	// 为 true 时不使用 TLS 连接 collector
+	Insecure    bool   `yaml:"insecure"`
+	ServiceName string `yaml:"service_name"`
+	// 没有上游 trace 时的采样比例，0 到 1；上游已采样的请求总是采样
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// MetricsServerConfig 指标的 HTTP 服务，不加密也不认证，应只监听内网地址
+type MetricsServerConfig struct {
 	Host string `yaml:"host"`
 	Port int    `yaml:"port"`
 }
 
+// TlsConfig gRPC 服务的证书，cert_file 为空时不开启 TLS。证书文件变化后自动重新加载
+type TlsConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// 校验客户端证书的 CA，配置后客户端证书的 subject 映射到 consumers 的 cert_subjects
+	ClientCaFile string `yaml:"client_ca_file"`
+	// 为 true 时拒绝没有客户端证书的连接
+	RequireClientCert bool `yaml:"require_client_cert"`
+}
+
+type HdWalletConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// 种子按链区分，配置 tenant 时按 链/tenant 区分
+	Tenant string `yaml:"tenant"`
+	// 保存种子加密口令的环境变量名
+	PassphraseEnv string `yaml:"passphrase_env"`
+}
+
+type KeyStoreConfig struct {
+	// none, passphrase, file 或 kms
+	Encryption string `yaml:"encryption"`
+	// 口令模式下的 KDF：argon2id（默认）或 scrypt
+	Kdf string `yaml:"kdf"`
+	// 口令模式下保存口令的环境变量名
+	PassphraseEnv string `yaml:"passphrase_env"`
+	// 文件模式下 32 字节 KEK 的路径
+	KekFile string `yaml:"kek_file"`
+	// kms 模式下用于包装 DEK 的对称密钥
+	KmsKeyName string `yaml:"kms_key_name"`
+}
+
+// TssConfig 门限 ECDSA。所有参与方运行在本进程中，通过内存通信，分片保存在同一个 key store，
+// 拿到 key store 就能恢复私钥，不提供门限签名的安全性，只用于测试
+type TssConfig struct {
+	// 开启后 secp256k1 链的新密钥由门限签名参与方联合生成
+	Enabled bool `yaml:"enabled"`
+	// 签名需要的参与方数量
+	Threshold int `yaml:"threshold"`
+	// 参与方总数，当前所有参与方运行在本进程中
+	Parties      int `yaml:"parties"`
+	PaillierBits int `yaml:"paillier_bits"`
+	// 确认在单进程测试环境中使用，未设置时拒绝开启
+	InsecureInProcess bool `yaml:"insecure_in_process"`
+}
+
+// FrostConfig FROST 门限 Schnorr，与 TssConfig 一样所有参与方运行在本进程中，只用于测试
+type FrostConfig struct {
+	// 开启后 chains 中各链的新密钥由 FROST 参与方联合生成：Solana 使用 Ed25519，Bitcoin 使用 BIP340 Schnorr（只支持 p2tr 地址）。
+	// p2tr 地址直接以群公钥作为输出公钥，没有 BIP86 tweak，签名也不带 tweak，不能导入按 BIP86 派生地址的钱包
+	Enabled   bool     `yaml:"enabled"`
+	Threshold int      `yaml:"threshold"`
+	Parties   int      `yaml:"parties"`
+	Chains    []string `yaml:"chains"`
+	// 确认在单进程测试环境中使用，未设置时拒绝开启
+	InsecureInProcess bool `yaml:"insecure_in_process"`
+}
+
+type Pkcs11Config struct {
+	// PKCS#11 库路径，例如 /usr/lib/softhsm/libsofthsm2.so
+	Library    string `yaml:"library"`
+	TokenLabel string `yaml:"token_label"`
+	// 保存用户 PIN 的环境变量名
+	PinEnv string `yaml:"pin_env"`
+}
+
+type VaultConfig struct {
+	Address string `yaml:"address"`
+	// Transit 引擎的挂载路径，默认 transit
+	Mount string `yaml:"mount"`
+	// 保存 token 的环境变量名，变量为空时使用 AppRole 登录
+	TokenEnv    string `yaml:"token_env"`
+	RoleId      string `yaml:"role_id"`
+	SecretIdEnv string `yaml:"secret_id_env"`
+	// secp256k1 密钥的类型，由插件提供，默认 ecdsa-secp256k1
+	Secp256k1KeyType string `yaml:"secp256k1_key_type"`
+}
+
+type ApprovalKeyConfig struct {
+	Id string `yaml:"id"`
+	// hmac-sha256、ed25519 或 secp256k1
+	Type string `yaml:"type"`
+	// hmac-sha256 共享密钥（hex）所在的环境变量名
+	SecretEnv string `yaml:"secret_env"`
+	// ed25519、secp256k1 的 hex 公钥
+	PublicKey string `yaml:"public_key"`
+}
+
+type ApprovalConfig struct {
+	// 钱包后端和风控系统的审批密钥，轮换期间可以同时配置多个版本
+	WalletKeys []ApprovalKeyConfig `yaml:"wallet_keys"`
+	RiskKeys   []ApprovalKeyConfig `yaml:"risk_keys"`
+}
+
+// MultiApprovalConfig 超过策略 approval_amount 的交易需要 quorum 个审批人同意，审批人只能使用 ed25519 或 secp256k1 公钥
+type MultiApprovalConfig struct {
+	Quorum    int                 `yaml:"quorum"`
+	Ttl       time.Duration       `yaml:"ttl"`
+	Approvers []ApprovalKeyConfig `yaml:"approvers"`
+}
+
+// VelocityLimitConfig 滚动窗口限额，max_count 和 max_amount 至少设置一个
+type VelocityLimitConfig struct {
+	Name string `yaml:"name"`
+	// key：每个签名公钥分别计数；consumer：每个调用方分别计数
+	Scope string `yaml:"scope"`
+	// 为空表示所有链、所有调用方
+	Chain    string `yaml:"chain"`
+	Consumer string `yaml:"consumer"`
+	// native 或代币合约地址，max_amount 为该资产最小单位的十进制字符串
+	Asset     string        `yaml:"asset"`
+	MaxAmount string        `yaml:"max_amount"`
+	MaxCount  int           `yaml:"max_count"`
+	Window    time.Duration `yaml:"window"`
+}
+
 type Config struct {
 	LevelDbPath     string       `yaml:"level_db_path"`
 	RpcServer       ServerConfig `yaml:"rpc_server"`
@@ -20,12 +158,44 @@ type Config struct {
 	KeyName         string       `yaml:"key_name"`
 	KeyPath         string       `yaml:"key_path"`
 	HsmEnabled      bool         `yaml:"hsm_enabled"`
-	Chains          []string     `yaml:"chains"`
+	// 开启 HSM 时使用的签名后端：gcp（默认）、pkcs11、vault 或 memory（仅用于测试）
+	HsmBackend string `yaml:"hsm_backend"`
+	// 按链覆盖 hsm_backend，例如 Solana: vault
+	HsmChains map[string]string `yaml:"hsm_chains"`
+	Pkcs11    Pkcs11Config      `yaml:"pkcs11"`
+	Vault     VaultConfig       `yaml:"vault"`
+	// gcp 后端创建新密钥的 key ring，格式 projects/*/locations/*/keyRings/*
+	KeyRing  string         `yaml:"key_ring"`
+	Chains   []string       `yaml:"chains"`
+	HdWallet HdWalletConfig `yaml:"hd_wallet"`
+	KeyStore KeyStoreConfig `yaml:"key_store"`
+	Tss      TssConfig      `yaml:"tss"`
+	Frost    FrostConfig    `yaml:"frost"`
+	// 管理接口（导入导出私钥）token 的 sha256 hex，为空时关闭管理接口
+	AdminTokenHash string `yaml:"admin_token_hash"`
+	// 调用方及其权限，与 signature consumer add 写入数据库的调用方合并使用
+	Consumers []consumer.Consumer `yaml:"consumers"`
+	// buildAndSignTransaction 需要的审批
+	Approval ApprovalConfig `yaml:"approval"`
+	// 交易策略文件（YAML 或 JSON），为空时不检查；文件修改后自动重新加载
+	PolicyFile string `yaml:"policy_file"`
+	// 签名次数和转出金额的滚动窗口限额
+	VelocityLimits []VelocityLimitConfig `yaml:"velocity_limits"`
+	// 大额交易的多方审批
+	MultiApproval MultiApprovalConfig `yaml:"multi_approval"`
+	// 签名请求 request_id 的结果缓存时间，为 0 时使用 24h
+	IdempotencyTtl time.Duration `yaml:"idempotency_ttl"`
+	// 审计日志文件，记录每一次密钥创建和签名，为空时不记录
+	AuditLog string `yaml:"audit_log"`
+	// Prometheus 指标的 HTTP 监听地址，port 为 0 时不开启
+	MetricsServer MetricsServerConfig `yaml:"metrics_server"`
+	// OpenTelemetry 链路追踪
+	Tracing TracingConfig `yaml:"tracing"`
 }
 
 func NewConfig(path string) (*Config, error) {
 	var config = new(Config)
-	h := log.NewTerminalHandler(os.Stdout, true)
+	h := redact.NewHandler(log.NewTerminalHandler(os.Stdout, true))
 	log.SetDefault(log.NewLogger(h))
 
 	data, err := os.ReadFile(path)
@@ -39,8 +209,27 @@ func NewConfig(path string) (*Config, error) {
 		log.Error("unmarshal config file error", "err", err)
 		return nil, err
 	}
+	if config.HdWallet.PassphraseEnv == "" {
+		config.HdWallet.PassphraseEnv = DefaultSeedPassphraseEnv
+	}
+	if config.KeyStore.PassphraseEnv == "" {
+		config.KeyStore.PassphraseEnv = DefaultKeyStorePassphraseEnv
+	}
 	return config, nil
 }
 
+const (
+	DefaultSeedPassphraseEnv     = "SIGNATURE_SEED_PASSPHRASE"
+	DefaultKeyStorePassphraseEnv = "SIGNATURE_KEYSTORE_PASSPHRASE"
+)
+
+// SeedName 返回某条链在 key store 中的种子名
+func (c *Config) SeedName(chainName string) string {
+	if c.HdWallet.Tenant == "" {
+		return chainName
+	}
+	return chainName + "/" + c.HdWallet.Tenant
+}
+
 const UnsupportedChain = "Unsupport chain"
 const UnsupportedOperation = UnsupportedChain
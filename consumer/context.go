@@ -0,0 +1,34 @@
+package consumer
+
+import "context"
+
+// Default 没有区分调用方时使用的 consumer 名
+const Default = "default"
+
+type contextKey struct{}
+
+type consumerKey struct{}
+
+// NewContext 把调用方身份放入请求上下文
+func NewContext(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, contextKey{}, name)
+}
+
+// FromContext 取出调用方身份，没有时返回 Default
+func FromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(contextKey{}).(string); ok && name != "" {
+		return name
+	}
+	return Default
+}
+
+// WithConsumer 放入通过认证的调用方，同时设置调用方身份
+func WithConsumer(ctx context.Context, c *Consumer) context.Context {
+	return context.WithValue(NewContext(ctx, c.Name), consumerKey{}, c)
+}
+
+// ConsumerFromContext 取出通过认证的调用方，审计和策略检查据此获取权限
+func ConsumerFromContext(ctx context.Context) (*Consumer, bool) {
+	c, ok := ctx.Value(consumerKey{}).(*Consumer)
+	return c, ok
+}
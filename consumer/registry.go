@@ -0,0 +1,159 @@
+package consumer
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidToken       = errors.New("invalid consumer token")
+	ErrUnknownCertificate = errors.New("client certificate is not mapped to a consumer")
+	ErrIdentityMismatch   = errors.New("token and client certificate belong to different consumers")
+	ErrDisabled           = errors.New("consumer is disabled")
+	ErrExpired            = errors.New("consumer credential expired")
+	ErrChainNotAllowed    = errors.New("chain not allowed for consumer")
+	ErrMethodNotAllowed   = errors.New("method not allowed for consumer")
+)
+
+// Consumer 一个调用方的凭据和权限。TokenHash 为 token 的 sha256 hex，服务端不保存 token 明文；
+// CertSubjects 为映射到该调用方的 TLS 客户端证书，可以写完整的 subject（CN=exchange,O=Acme）或只写 CN。
+// Chains、Methods 为空表示不限制，ExpiresAt 为 0 表示不过期
+type Consumer struct {
+	Name         string   `json:"name" yaml:"name"`
+	TokenHash    string   `json:"token_hash" yaml:"token_hash"`
+	CertSubjects []string `json:"cert_subjects" yaml:"cert_subjects"`
+	Chains       []string `json:"chains" yaml:"chains"`
+	Methods      []string `json:"methods" yaml:"methods"`
+	Disabled     bool     `json:"disabled" yaml:"disabled"`
+	ExpiresAt    int64    `json:"expires_at" yaml:"expires_at"`
+}
+
+// Allow 检查调用方能否在 chain 上调用 method（gRPC 方法名，例如 signTransactionMessage）
+func (c *Consumer) Allow(chain string, method string) error {
+	if len(c.Chains) > 0 && !contains(c.Chains, chain) {
+		return fmt.Errorf("%w: %s", ErrChainNotAllowed, chain)
+	}
+	if len(c.Methods) > 0 && !contains(c.Methods, method) {
+		return fmt.Errorf("%w: %s", ErrMethodNotAllowed, method)
+	}
+	return nil
+}
+
+func contains(list []string, item string) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry 按 token 哈希和证书 subject 索引的调用方列表，创建后只读，可以并发使用
+type Registry struct {
+	byName    map[string]*Consumer
+	byHash    map[string]*Consumer
+	bySubject map[string]*Consumer
+}
+
+// NewRegistry 校验名字、token 哈希和证书 subject 都不重复
+func NewRegistry(consumers []Consumer) (*Registry, error) {
+	r := &Registry{
+		byName:    make(map[string]*Consumer, len(consumers)),
+		byHash:    make(map[string]*Consumer, len(consumers)),
+		bySubject: make(map[string]*Consumer),
+	}
+	names := make(map[string]bool, len(consumers))
+	for i := range consumers {
+		c := consumers[i]
+		if c.Name == "" {
+			return nil, errors.New("consumer name is empty")
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate consumer %s", c.Name)
+		}
+		names[c.Name] = true
+		r.byName[c.Name] = &c
+		if c.TokenHash == "" && len(c.CertSubjects) == 0 {
+			return nil, fmt.Errorf("consumer %s has neither a token nor a certificate", c.Name)
+		}
+		if c.TokenHash != "" {
+			if hash, err := hex.DecodeString(c.TokenHash); err != nil || len(hash) != sha256.Size {
+				return nil, fmt.Errorf("consumer %s: token hash must be a sha256 hex", c.Name)
+			}
+			if _, ok := r.byHash[c.TokenHash]; ok {
+				return nil, fmt.Errorf("consumer %s: token is shared with another consumer", c.Name)
+			}
+			r.byHash[c.TokenHash] = &c
+		}
+		for _, subject := range c.CertSubjects {
+			if _, ok := r.bySubject[subject]; ok {
+				return nil, fmt.Errorf("consumer %s: certificate subject %s is shared with another consumer", c.Name, subject)
+			}
+			r.bySubject[subject] = &c
+		}
+	}
+	return r, nil
+}
+
+// Authenticate 返回 token 对应的调用方，已停用或过期的调用方返回错误
+func (r *Registry) Authenticate(token string) (*Consumer, error) {
+	if token == "" {
+		return nil, ErrInvalidToken
+	}
+	c, ok := r.byHash[HashToken(token)]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return c, c.check()
+}
+
+// Lookup 按名字返回调用方，用于以提交方身份执行延后的签名；已停用或过期的调用方返回错误
+func (r *Registry) Lookup(name string) (*Consumer, error) {
+	c, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown consumer %s", name)
+	}
+	return c, c.check()
+}
+
+// AuthenticateCertificate 返回客户端证书对应的调用方，先按完整 subject 查找，再按 CN 查找
+func (r *Registry) AuthenticateCertificate(cert *x509.Certificate) (*Consumer, error) {
+	c, ok := r.bySubject[cert.Subject.String()]
+	if !ok && cert.Subject.CommonName != "" {
+		c, ok = r.bySubject[cert.Subject.CommonName]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCertificate, cert.Subject)
+	}
+	return c, c.check()
+}
+
+func (c *Consumer) check() error {
+	if c.Disabled {
+		return ErrDisabled
+	}
+	if c.ExpiresAt != 0 && time.Now().Unix() >= c.ExpiresAt {
+		return ErrExpired
+	}
+	return nil
+}
+
+// HashToken 返回 token 的 sha256 hex，即配置和数据库中保存的 token_hash
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateToken 生成 32 字节随机 token
+func GenerateToken() (string, error) {
+	var token [32]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token[:]), nil
+}
@@ -0,0 +1,59 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	registry, err := NewRegistry([]Consumer{
+		{Name: "exchange", TokenHash: HashToken("t1"), Chains: []string{"Ethereum"}, Methods: []string{"signTransactionMessage"}},
+		{Name: "disabled", TokenHash: HashToken("t2"), Disabled: true},
+		{Name: "expired", TokenHash: HashToken("t3"), ExpiresAt: time.Now().Add(-time.Minute).Unix()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := registry.Authenticate("t1")
+	if err != nil || c.Name != "exchange" {
+		t.Fatalf("authenticate = %v, err %v", c, err)
+	}
+	if err := c.Allow("Ethereum", "signTransactionMessage"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Allow("Solana", "signTransactionMessage"); !errors.Is(err, ErrChainNotAllowed) {
+		t.Fatalf("other chain: err = %v", err)
+	}
+	if err := c.Allow("Ethereum", "exportKeys"); !errors.Is(err, ErrMethodNotAllowed) {
+		t.Fatalf("other method: err = %v", err)
+	}
+	for token, want := range map[string]error{"": ErrInvalidToken, "wrong": ErrInvalidToken, "t2": ErrDisabled, "t3": ErrExpired} {
+		if _, err := registry.Authenticate(token); !errors.Is(err, want) {
+			t.Fatalf("token %q: err = %v, want %v", token, err, want)
+		}
+	}
+
+	if got, err := registry.Lookup("exchange"); err != nil || got != c {
+		t.Fatalf("lookup = %v, err %v", got, err)
+	}
+	if _, err := registry.Lookup("disabled"); !errors.Is(err, ErrDisabled) {
+		t.Fatalf("lookup disabled: err = %v", err)
+	}
+	if _, err := registry.Lookup("unknown"); err == nil {
+		t.Fatal("expected an unknown consumer to fail lookup")
+	}
+
+	ctx := WithConsumer(context.Background(), c)
+	if got, ok := ConsumerFromContext(ctx); !ok || got != c || FromContext(ctx) != "exchange" {
+		t.Fatal("consumer does not travel in the context")
+	}
+
+	if _, err := NewRegistry([]Consumer{{Name: "a", TokenHash: HashToken("x")}, {Name: "b", TokenHash: HashToken("x")}}); err == nil {
+		t.Fatal("expected a shared token to be rejected")
+	}
+	if _, err := NewRegistry([]Consumer{{Name: "a", TokenHash: "plaintext"}}); err == nil {
+		t.Fatal("expected a plaintext token to be rejected")
+	}
+}
@@ -16,6 +16,232 @@ var (
 		EnvVars: prefixEnvVars("LEVEL_DB_PATH"),
 		Value:   "./data",
 	}
+	// ConfigFlag Config file
+	ConfigFlag = &cli.StringFlag{
+		Name:    "config",
+		Aliases: []string{"c"},
+		Usage:   "The path of the config file",
+		EnvVars: prefixEnvVars("CONFIG"),
+		Value:   "config.yml",
+	}
+	// ChainFlag Chain name
+	ChainFlag = &cli.StringFlag{
+		Name:     "chain",
+		Usage:    "The chain name, e.g. Bitcoin, Ethereum, Solana",
+		Required: true,
+	}
+	// MnemonicFileFlag Mnemonic
+	MnemonicFileFlag = &cli.StringFlag{
+		Name:  "mnemonic-file",
+		Usage: "Import the seed from the BIP-39 mnemonic in this file instead of generating a new one",
+	}
+	// PublicKeysFileFlag Public key filter
+	PublicKeysFileFlag = &cli.StringFlag{
+		Name:  "public-keys-file",
+		Usage: "Only process the hex public keys listed in this file, separated by whitespace",
+	}
+	// KeyFormatFlag Import format
+	KeyFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "The format of the imported keys: hex, wif, base58, keystore or backup",
+		Value: "hex",
+	}
+	// InputFileFlag Import source
+	InputFileFlag = &cli.StringFlag{
+		Name:     "in",
+		Usage:    "The file to import: whitespace separated keys for hex/wif/base58, a single keystore or backup file otherwise",
+		Required: true,
+	}
+	// OutputFileFlag Export target
+	OutputFileFlag = &cli.StringFlag{
+		Name:     "out",
+		Usage:    "The path of the encrypted backup file to write",
+		Required: true,
+	}
+	// NetworkFlag Network of imported keys
+	NetworkFlag = &cli.StringFlag{
+		Name:  "network",
+		Usage: "The network recorded on imported keys",
+	}
+	// LabelFlag Label of imported keys
+	LabelFlag = &cli.StringFlag{
+		Name:  "label",
+		Usage: "The label recorded on imported keys",
+	}
+	// ConsumerFlag Key owner
+	ConsumerFlag = &cli.StringFlag{
+		Name:  "consumer",
+		Usage: "The consumer that owns the keys",
+		Value: "default",
+	}
+	// PasswordEnvFlag Backup password
+	PasswordEnvFlag = &cli.StringFlag{
+		Name:  "password-env",
+		Usage: "The environment variable holding the keystore or backup password",
+		Value: envVarPrefix + "_BACKUP_PASSWORD",
+	}
+)
+
+var (
+	// SecretFlag Master secret to back up
+	SecretFlag = &cli.StringFlag{
+		Name:  "secret",
+		Usage: "The master secret to split: seed or kek",
+		Value: "seed",
+	}
+	// SeedChainFlag Seed owner
+	SeedChainFlag = &cli.StringFlag{
+		Name:  "chain",
+		Usage: "The chain whose master seed is split or restored, required for seeds",
+	}
+	// ThresholdFlag Shares needed for recovery
+	ThresholdFlag = &cli.IntFlag{
+		Name:     "threshold",
+		Usage:    "The number of shares needed to restore the secret",
+		Required: true,
+	}
+	// SharesFlag Shares to create
+	SharesFlag = &cli.IntFlag{
+		Name:     "shares",
+		Usage:    "The number of shares to create",
+		Required: true,
+	}
+	// OutDirFlag Share directory
+	OutDirFlag = &cli.StringFlag{
+		Name:     "out-dir",
+		Usage:    "The directory to write one file per share into",
+		Required: true,
+	}
+	// ShareFileFlag Shares to restore from
+	ShareFileFlag = &cli.StringSliceFlag{
+		Name:     "share",
+		Usage:    "A file holding one share, repeat for each share",
+		Required: true,
+	}
+	// KekOutFlag Restored kek
+	KekOutFlag = &cli.StringFlag{
+		Name:  "kek-out",
+		Usage: "The path to write a restored kek file or passphrase to",
+	}
+)
+
+var (
+	// ProjectFlag GCP project
+	ProjectFlag = &cli.StringFlag{
+		Name:     "project",
+		Usage:    "The GCP project id of the key ring",
+		Required: true,
+	}
+	// LocationFlag KMS location
+	LocationFlag = &cli.StringFlag{
+		Name:  "location",
+		Usage: "The KMS location of the key ring, e.g. global or us-east1",
+		Value: "global",
+	}
+	// KeyRingIdFlag Key ring id
+	KeyRingIdFlag = &cli.StringFlag{
+		Name:     "key-ring",
+		Usage:    "The id of the key ring",
+		Required: true,
+	}
+	// KeyIdFlag Crypto key id
+	KeyIdFlag = &cli.StringFlag{
+		Name:     "key-id",
+		Usage:    "The id of the crypto key to create",
+		Required: true,
+	}
+	// AlgorithmFlag Key algorithm
+	AlgorithmFlag = &cli.StringFlag{
+		Name:  "algorithm",
+		Usage: "The key algorithm: secp256k1 or ed25519",
+		Value: "secp256k1",
+	}
+	// KmsKeyFlag Crypto key
+	KmsKeyFlag = &cli.StringFlag{
+		Name:     "key",
+		Usage:    "The crypto key resource name, projects/*/locations/*/keyRings/*/cryptoKeys/*",
+		Required: true,
+	}
+	// KeyVersionFlag Crypto key version
+	KeyVersionFlag = &cli.StringFlag{
+		Name:     "key-version",
+		Usage:    "The crypto key version resource name, projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*",
+		Required: true,
+	}
+	// AddressChainFlag Chain to derive the address for
+	AddressChainFlag = &cli.StringFlag{
+		Name:  "chain",
+		Usage: "Also print the address of the public key on this chain",
+	}
+	// AddressFormatFlag Bitcoin address format
+	AddressFormatFlag = &cli.StringFlag{
+		Name:  "address-format",
+		Usage: "The bitcoin address format: p2wpkh or p2pkh",
+		Value: "p2wpkh",
+	}
+)
+
+var (
+	// ConsumerNameFlag Consumer to manage
+	ConsumerNameFlag = &cli.StringFlag{
+		Name:     "name",
+		Usage:    "The consumer name",
+		Required: true,
+	}
+	// AllowedChainsFlag Chains a consumer may use
+	AllowedChainsFlag = &cli.StringSliceFlag{
+		Name:  "chains",
+		Usage: "The chains the consumer may use, all chains when empty",
+	}
+	// AllowedMethodsFlag RPC methods a consumer may call
+	AllowedMethodsFlag = &cli.StringSliceFlag{
+		Name:  "methods",
+		Usage: "The rpc methods the consumer may call, e.g. signTransactionMessage, all methods when empty",
+	}
+	// TokenTtlFlag Credential lifetime
+	TokenTtlFlag = &cli.DurationFlag{
+		Name:  "ttl",
+		Usage: "How long the token stays valid, never expires when zero",
+	}
+	// AuditFileFlag Audit log
+	AuditFileFlag = &cli.StringFlag{
+		Name:  "file",
+		Usage: "The audit log to read, audit_log from the config file when empty",
+	}
+	// FromSeqFlag First exported entry
+	FromSeqFlag = &cli.Uint64Flag{
+		Name:  "from-seq",
+		Usage: "Export entries from this sequence number (inclusive)",
+	}
+	// ToSeqFlag Last exported entry
+	ToSeqFlag = &cli.Uint64Flag{
+		Name:  "to-seq",
+		Usage: "Export entries up to this sequence number (inclusive)",
+	}
+	// SinceFlag Export start time
+	SinceFlag = &cli.StringFlag{
+		Name:  "since",
+		Usage: "Export entries at or after this RFC3339 time",
+	}
+	// UntilFlag Export end time
+	UntilFlag = &cli.StringFlag{
+		Name:  "until",
+		Usage: "Export entries before this RFC3339 time",
+	}
+	// ExportOutFlag Export target
+	ExportOutFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "The JSONL file to write, stdout when empty",
+	}
+)
+
+var (
+	// PublicKeyFlag Key to operate on
+	PublicKeyFlag = &cli.StringFlag{
+		Name:     "public-key",
+		Usage:    "The hex public key of the key in the key store",
+		Required: true,
+	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -0,0 +1,163 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// runDealers 在本地模拟一轮密钥生成或刷新的消息交换
+func runDealers(t *testing.T, dealers map[int]*Dealer, round1 map[int]*Round1) map[int]*KeyShare {
+	shares := make(map[int]*KeyShare)
+	for id, dealer := range dealers {
+		received := make(map[int]*big.Int)
+		for from, other := range dealers {
+			if from != id {
+				received[from] = other.Share(id)
+			}
+		}
+		share, err := dealer.Finish(round1, received)
+		if err != nil {
+			t.Fatalf("party %d: %v", id, err)
+		}
+		shares[id] = share
+	}
+	return shares
+}
+
+func keygen(t *testing.T, suite string, threshold int, parties []int) map[int]*KeyShare {
+	dealers := make(map[int]*Dealer)
+	round1 := make(map[int]*Round1)
+	for _, id := range parties {
+		dealer, msg, err := NewKeygen(suite, id, threshold, parties)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealers[id] = dealer
+		round1[id] = msg
+	}
+	return runDealers(t, dealers, round1)
+}
+
+func sign(t *testing.T, shares map[int]*KeyShare, signers []int, msg []byte) []byte {
+	nonces := make(map[int]*Nonce)
+	var commitments []*Commitment
+	for _, id := range signers {
+		nonce, commitment, err := shares[id].Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces[id] = nonce
+		commitments = append(commitments, commitment)
+	}
+	zs := make(map[int]*big.Int)
+	for _, id := range signers {
+		z, err := shares[id].Sign(nonces[id], msg, commitments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zs[id] = z
+	}
+	sig, err := shares[signers[0]].Aggregate(msg, commitments, zs)
+	if err != nil {
+		t.Fatalf("signers %v: %v", signers, err)
+	}
+	return sig
+}
+
+func TestEd25519(t *testing.T) {
+	parties := []int{1, 2, 3}
+	shares := keygen(t, SuiteEd25519, 2, parties)
+	msg := []byte("solana transaction message")
+	for _, signers := range [][]int{{1, 2}, {2, 3}, {3, 1}, {1, 2, 3}} {
+		sig := sign(t, shares, signers, msg)
+		if !ed25519.Verify(shares[1].GroupKey, msg, sig) {
+			t.Fatalf("signers %v: signature does not verify with crypto/ed25519", signers)
+		}
+	}
+}
+
+func TestSecp256k1TR(t *testing.T) {
+	parties := []int{1, 2, 3}
+	shares := keygen(t, SuiteSecp256k1TR, 2, parties)
+	if shares[1].GroupKey[0] != 0x02 {
+		t.Fatalf("group key %x does not have an even y", shares[1].GroupKey)
+	}
+	pubKey, err := schnorr.ParsePubKey(shares[1].GroupKey[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 多签几次以覆盖 R 的 y 为奇数和偶数两种情况
+	for i := 0; i < 8; i++ {
+		hash := sha256.Sum256([]byte{byte(i)})
+		sig := sign(t, shares, []int{3, 1}, hash[:])
+		parsed, err := schnorr.ParseSignature(sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.Verify(hash[:], pubKey) {
+			t.Fatalf("signature %x does not verify as BIP340", sig)
+		}
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	parties := []int{1, 2, 3}
+	for _, suite := range []string{SuiteEd25519, SuiteSecp256k1TR} {
+		shares := keygen(t, suite, 2, parties)
+		dealers := make(map[int]*Dealer)
+		round1 := make(map[int]*Round1)
+		for id, share := range shares {
+			dealer, msg, err := NewRefresh(share)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dealers[id] = dealer
+			round1[id] = msg
+		}
+		refreshed := runDealers(t, dealers, round1)
+		for id := range shares {
+			if string(refreshed[id].GroupKey) != string(shares[id].GroupKey) {
+				t.Fatalf("%s: refresh changed the group key", suite)
+			}
+			if refreshed[id].Secret.Cmp(shares[id].Secret) == 0 {
+				t.Fatalf("%s: refresh did not change the share of party %d", suite, id)
+			}
+		}
+		msg := sha256.Sum256([]byte(suite))
+		sig := sign(t, refreshed, []int{2, 3}, msg[:])
+		if ok, err := Verify(suite, shares[1].GroupKey, msg[:], sig); err != nil || !ok {
+			t.Fatalf("%s: refreshed signature does not verify: %v", suite, err)
+		}
+
+		// 新旧分片混用无法得到有效签名
+		mixed := map[int]*KeyShare{1: shares[1], 2: refreshed[2]}
+		nonce1, c1, _ := mixed[1].Commit()
+		nonce2, c2, _ := mixed[2].Commit()
+		commitments := []*Commitment{c1, c2}
+		z1, _ := mixed[1].Sign(nonce1, msg[:], commitments)
+		z2, _ := mixed[2].Sign(nonce2, msg[:], commitments)
+		if _, err := refreshed[2].Aggregate(msg[:], commitments, map[int]*big.Int{1: z1, 2: z2}); err == nil {
+			t.Fatalf("%s: expected mixing old and refreshed shares to fail", suite)
+		}
+	}
+}
+
+func TestNonceReuse(t *testing.T) {
+	shares := keygen(t, SuiteEd25519, 2, []int{1, 2})
+	nonce, commitment, err := shares[1].Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, _ := shares[2].Commit()
+	commitments := []*Commitment{commitment, other}
+	if _, err := shares[1].Sign(nonce, []byte("a"), commitments); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := shares[1].Sign(nonce, []byte("b"), commitments); err != ErrNonceUsed {
+		t.Fatalf("expected ErrNonceUsed, got %v", err)
+	}
+}
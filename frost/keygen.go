@@ -0,0 +1,358 @@
+package frost
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// KeyShare 一个参与方持有的 FROST 私钥分片
+type KeyShare struct {
+	Suite     string `json:"suite"`
+	ID        int    `json:"id"`
+	Threshold int    `json:"threshold"`
+	Parties   []int  `json:"parties"`
+	// Secret 私钥多项式在 ID 处的取值
+	Secret *big.Int `json:"secret"`
+	// GroupKey 群公钥，VerificationShares 为每个参与方的 Secret·G，用来校验签名分片
+	GroupKey           []byte         `json:"group_key"`
+	VerificationShares map[int][]byte `json:"verification_shares"`
+}
+
+// Fingerprint 群公钥和所有验证分片的摘要，各参与方用它确认得到了一致的结果
+func (s *KeyShare) Fingerprint() []byte {
+	h := sha256.New()
+	h.Write([]byte(s.Suite))
+	h.Write(s.GroupKey)
+	parties := append([]int(nil), s.Parties...)
+	sort.Ints(parties)
+	var buf [4]byte
+	for _, party := range parties {
+		binary.BigEndian.PutUint32(buf[:], uint32(party))
+		h.Write(buf[:])
+		h.Write(s.VerificationShares[party])
+	}
+	return h.Sum(nil)
+}
+
+// Round1 第一轮广播：多项式系数的承诺，密钥生成时还带有常数项的知识证明
+type Round1 struct {
+	Commitments [][]byte `json:"commitments"`
+	ProofR      []byte   `json:"proof_r,omitempty"`
+	ProofZ      *big.Int `json:"proof_z,omitempty"`
+}
+
+// Dealer 一个参与方在分布式密钥生成或分片刷新中的状态。
+//
+// 密钥生成按 FROST 论文的 Pedersen DKG：每方选 t-1 次多项式，广播系数承诺和常数项的 Schnorr 证明，
+// 再把多项式取值私下发给其它参与方。刷新时多项式常数项为 0（只广播 1 次及以上系数的承诺），
+// 各方把收到的取值加到原分片上，群公钥不变而旧分片失效
+type Dealer struct {
+	suite        Ciphersuite
+	id           int
+	threshold    int
+	parties      []int
+	coefficients []*big.Int
+	commitments  []Element
+	// refresh 非空表示这是对该分片的刷新
+	refresh *KeyShare
+}
+
+// NewKeygen 开始一次分布式密钥生成，返回需要广播的第一轮消息
+func NewKeygen(suiteName string, id int, threshold int, parties []int) (*Dealer, *Round1, error) {
+	suite, err := SuiteByName(suiteName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateParties(id, parties); err != nil {
+		return nil, nil, err
+	}
+	if threshold < 2 || threshold > len(parties) {
+		return nil, nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, len(parties))
+	}
+	d := &Dealer{suite: suite, id: id, threshold: threshold, parties: append([]int(nil), parties...)}
+	if err := d.samplePolynomial(false); err != nil {
+		return nil, nil, err
+	}
+	// 常数项的知识证明，防止恶意参与方用其它参与方的承诺构造出自己掌握的群公钥
+	k, err := randomScalar(suite)
+	if err != nil {
+		return nil, nil, err
+	}
+	R := suite.BaseMult(k)
+	c := suite.hdkg(d.proofInput(id, d.commitments[0], R))
+	z := new(big.Int).Mul(d.coefficients[0], c)
+	z.Add(z, k).Mod(z, suite.Order())
+	return d, &Round1{Commitments: encodeElements(d.commitments), ProofR: R.Bytes(), ProofZ: z}, nil
+}
+
+// NewRefresh 开始对已有分片的刷新，share 的所有参与方都需要参加
+func NewRefresh(share *KeyShare) (*Dealer, *Round1, error) {
+	suite, err := SuiteByName(share.Suite)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := &Dealer{suite: suite, id: share.ID, threshold: share.Threshold, parties: append([]int(nil), share.Parties...), refresh: share}
+	if err := d.samplePolynomial(true); err != nil {
+		return nil, nil, err
+	}
+	return d, &Round1{Commitments: encodeElements(d.commitments[1:])}, nil
+}
+
+// Share 本方多项式在参与方 to 处的取值，只能经加密信道发给 to
+func (d *Dealer) Share(to int) *big.Int {
+	return evaluatePolynomial(d.suite, d.coefficients, to)
+}
+
+// Finish 校验其它参与方的第一轮消息和发给本方的取值，计算新的分片
+func (d *Dealer) Finish(round1 map[int]*Round1, shares map[int]*big.Int) (*KeyShare, error) {
+	q := d.suite.Order()
+	secret := d.Share(d.id)
+	all := map[int][]Element{d.id: d.commitments}
+	for _, party := range d.parties {
+		if party == d.id {
+			continue
+		}
+		msg, ok := round1[party]
+		if !ok {
+			return nil, fmt.Errorf("missing round 1 message from party %d", party)
+		}
+		commitments, err := d.decodeRound1(party, msg)
+		if err != nil {
+			return nil, err
+		}
+		share := shares[party]
+		if share == nil || share.Sign() < 0 || share.Cmp(q) >= 0 {
+			return nil, fmt.Errorf("party %d sent an invalid share", party)
+		}
+		// Feldman 校验：share·G == Σ φ_k · id^k
+		if !d.suite.BaseMult(share).Equal(evaluateCommitments(d.suite, commitments, d.id)) {
+			return nil, fmt.Errorf("share from party %d does not match its commitments", party)
+		}
+		secret.Add(secret, share)
+		all[party] = commitments
+	}
+
+	groupKey := d.suite.Identity()
+	for _, commitments := range all {
+		groupKey = groupKey.Add(commitments[0])
+	}
+	verification := make(map[int]Element, len(d.parties))
+	for _, party := range d.parties {
+		v := d.suite.Identity()
+		for _, commitments := range all {
+			v = v.Add(evaluateCommitments(d.suite, commitments, party))
+		}
+		verification[party] = v
+	}
+
+	if d.refresh != nil {
+		old, err := d.refresh.decode(d.suite)
+		if err != nil {
+			return nil, err
+		}
+		secret.Add(secret, d.refresh.Secret)
+		groupKey = old.groupKey
+		for party := range verification {
+			verification[party] = verification[party].Add(old.verification[party])
+		}
+	} else {
+		if groupKey.IsIdentity() {
+			return nil, errors.New("group public key is the identity")
+		}
+		// x-only 公钥只能表示偶数 y，y 为奇数时所有分片取负，对应的群公钥变为 -PK
+		if d.suite.evenY() && !d.suite.hasEvenY(groupKey) {
+			secret.Neg(secret)
+			groupKey = groupKey.Negate()
+			for party := range verification {
+				verification[party] = verification[party].Negate()
+			}
+		}
+	}
+	secret.Mod(secret, q)
+	if !d.suite.BaseMult(secret).Equal(verification[d.id]) {
+		return nil, errors.New("secret share does not match its verification share")
+	}
+
+	share := &KeyShare{
+		Suite:              d.suite.Name(),
+		ID:                 d.id,
+		Threshold:          d.threshold,
+		Parties:            append([]int(nil), d.parties...),
+		Secret:             secret,
+		GroupKey:           groupKey.Bytes(),
+		VerificationShares: make(map[int][]byte, len(verification)),
+	}
+	for party, v := range verification {
+		share.VerificationShares[party] = v.Bytes()
+	}
+	return share, nil
+}
+
+// decodeRound1 解码承诺并校验知识证明；刷新时补上常数项的单位元承诺
+func (d *Dealer) decodeRound1(party int, msg *Round1) ([]Element, error) {
+	want := d.threshold
+	if d.refresh != nil {
+		want = d.threshold - 1
+	}
+	if len(msg.Commitments) != want {
+		return nil, fmt.Errorf("party %d committed to %d coefficients, want %d", party, len(msg.Commitments), want)
+	}
+	commitments, err := decodeElements(d.suite, msg.Commitments)
+	if err != nil {
+		return nil, fmt.Errorf("party %d: %w", party, err)
+	}
+	if d.refresh != nil {
+		return append([]Element{d.suite.Identity()}, commitments...), nil
+	}
+	R, err := d.suite.DecodeElement(msg.ProofR)
+	if err != nil || msg.ProofZ == nil || msg.ProofZ.Cmp(d.suite.Order()) >= 0 {
+		return nil, fmt.Errorf("party %d sent an invalid proof of knowledge", party)
+	}
+	c := d.suite.hdkg(d.proofInput(party, commitments[0], R))
+	// z·G - c·φ_0 == R
+	if !d.suite.BaseMult(msg.ProofZ).Add(commitments[0].ScalarMult(c).Negate()).Equal(R) {
+		return nil, fmt.Errorf("party %d proof of knowledge does not verify", party)
+	}
+	return commitments, nil
+}
+
+func (d *Dealer) proofInput(party int, phi0, R Element) []byte {
+	input := d.suite.EncodeScalar(big.NewInt(int64(party)))
+	input = append(input, phi0.Bytes()...)
+	return append(input, R.Bytes()...)
+}
+
+// samplePolynomial 随机选 t-1 次多项式，zeroConstant 时常数项为 0
+func (d *Dealer) samplePolynomial(zeroConstant bool) error {
+	d.coefficients = make([]*big.Int, d.threshold)
+	d.commitments = make([]Element, d.threshold)
+	for i := range d.coefficients {
+		if i == 0 && zeroConstant {
+			d.coefficients[i] = new(big.Int)
+		} else {
+			c, err := randomScalar(d.suite)
+			if err != nil {
+				return err
+			}
+			d.coefficients[i] = c
+		}
+		d.commitments[i] = d.suite.BaseMult(d.coefficients[i])
+	}
+	return nil
+}
+
+// decodedShare KeyShare 中的公开部分解码后的形式
+type decodedShare struct {
+	groupKey     Element
+	verification map[int]Element
+}
+
+func (s *KeyShare) decode(suite Ciphersuite) (*decodedShare, error) {
+	groupKey, err := suite.DecodeElement(s.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("group key: %w", err)
+	}
+	decoded := &decodedShare{groupKey: groupKey, verification: make(map[int]Element, len(s.Parties))}
+	for _, party := range s.Parties {
+		v, err := suite.DecodeElement(s.VerificationShares[party])
+		if err != nil {
+			return nil, fmt.Errorf("verification share of party %d: %w", party, err)
+		}
+		decoded.verification[party] = v
+	}
+	return decoded, nil
+}
+
+func evaluatePolynomial(suite Ciphersuite, coefficients []*big.Int, x int) *big.Int {
+	q := suite.Order()
+	bx := big.NewInt(int64(x))
+	y := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, bx)
+		y.Add(y, coefficients[i])
+		y.Mod(y, q)
+	}
+	return y
+}
+
+func evaluateCommitments(suite Ciphersuite, commitments []Element, x int) Element {
+	result := suite.Identity()
+	power := big.NewInt(1)
+	bx := big.NewInt(int64(x))
+	for _, c := range commitments {
+		result = result.Add(c.ScalarMult(power))
+		power = new(big.Int).Mod(new(big.Int).Mul(power, bx), suite.Order())
+	}
+	return result
+}
+
+// lagrangeAtZero 参与方 id 在 ids 集合上的拉格朗日系数 Π j / (j - id)
+func lagrangeAtZero(suite Ciphersuite, id int, ids []int) *big.Int {
+	q := suite.Order()
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	for _, j := range ids {
+		if j == id {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(j)))
+		den.Mul(den, big.NewInt(int64(j-id)))
+	}
+	den.Mod(den, q)
+	den.ModInverse(den, q)
+	return num.Mul(num, den).Mod(num, q)
+}
+
+func randomScalar(suite Ciphersuite) (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, suite.Order())
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+func encodeElements(elements []Element) [][]byte {
+	out := make([][]byte, len(elements))
+	for i, e := range elements {
+		out[i] = e.Bytes()
+	}
+	return out
+}
+
+func decodeElements(suite Ciphersuite, data [][]byte) ([]Element, error) {
+	out := make([]Element, len(data))
+	for i, d := range data {
+		e, err := suite.DecodeElement(d)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = e
+	}
+	return out, nil
+}
+
+func validateParties(self int, parties []int) error {
+	seen := make(map[int]bool)
+	for _, party := range parties {
+		if party <= 0 {
+			return fmt.Errorf("invalid party id %d", party)
+		}
+		if seen[party] {
+			return fmt.Errorf("duplicate party id %d", party)
+		}
+		seen[party] = true
+	}
+	if !seen[self] {
+		return fmt.Errorf("party %d is not in the party list", self)
+	}
+	return nil
+}
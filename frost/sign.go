@@ -0,0 +1,228 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+var ErrNonceUsed = errors.New("signing nonce has already been used")
+
+// Nonce 第一轮生成的一次性随机数，签名后即清除，不能重复使用
+type Nonce struct {
+	hiding  *big.Int
+	binding *big.Int
+}
+
+// Commitment 第一轮广播的随机数承诺 (D, E)
+type Commitment struct {
+	ID      int    `json:"id"`
+	Hiding  []byte `json:"hiding"`
+	Binding []byte `json:"binding"`
+}
+
+// Commit 签名第一轮：生成一对随机数和对应的承诺
+func (s *KeyShare) Commit() (*Nonce, *Commitment, error) {
+	suite, err := SuiteByName(s.Suite)
+	if err != nil {
+		return nil, nil, err
+	}
+	hiding, err := generateNonce(suite, s.Secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	binding, err := generateNonce(suite, s.Secret)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Nonce{hiding: hiding, binding: binding}, &Commitment{
+		ID:      s.ID,
+		Hiding:  suite.BaseMult(hiding).Bytes(),
+		Binding: suite.BaseMult(binding).Bytes(),
+	}, nil
+}
+
+// Sign 签名第二轮：用本方随机数和所有签名方的承诺计算签名分片 z_i
+func (s *KeyShare) Sign(nonce *Nonce, msg []byte, commitments []*Commitment) (*big.Int, error) {
+	if nonce.hiding == nil || nonce.binding == nil {
+		return nil, ErrNonceUsed
+	}
+	hiding, binding := nonce.hiding, nonce.binding
+	nonce.hiding, nonce.binding = nil, nil
+
+	ctx, err := s.signingContext(msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	own, ok := ctx.commitments[s.ID]
+	if !ok {
+		return nil, fmt.Errorf("party %d is not in the signing set", s.ID)
+	}
+	if !ctx.suite.BaseMult(hiding).Equal(own.hiding) || !ctx.suite.BaseMult(binding).Equal(own.binding) {
+		return nil, errors.New("own commitment does not match the nonce")
+	}
+	q := ctx.suite.Order()
+	// R 为奇数 y 时签名实际使用 -R，各方的随机数同时取负
+	if !ctx.evenR {
+		hiding = new(big.Int).Neg(hiding)
+		binding = new(big.Int).Neg(binding)
+	}
+	z := new(big.Int).Mul(binding, ctx.bindingFactors[s.ID])
+	z.Add(z, hiding)
+	sk := new(big.Int).Mul(lagrangeAtZero(ctx.suite, s.ID, ctx.ids), s.Secret)
+	sk.Mul(sk, ctx.challenge)
+	return z.Add(z, sk).Mod(z, q), nil
+}
+
+// Aggregate 校验每个签名分片并合成最终签名。Ed25519 为 R || z（RFC 8032），secp256k1-tr 为 BIP340 的 x(R) || z
+func (s *KeyShare) Aggregate(msg []byte, commitments []*Commitment, shares map[int]*big.Int) ([]byte, error) {
+	ctx, err := s.signingContext(msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	public, err := s.decode(ctx.suite)
+	if err != nil {
+		return nil, err
+	}
+	q := ctx.suite.Order()
+	z := new(big.Int)
+	for _, id := range ctx.ids {
+		zi := shares[id]
+		if zi == nil || zi.Sign() < 0 || zi.Cmp(q) >= 0 {
+			return nil, fmt.Errorf("missing or invalid signature share from party %d", id)
+		}
+		// z_i·G == (D_i + ρ_i·E_i) + c·λ_i·Y_i
+		comm := ctx.commitments[id]
+		commShare := comm.hiding.Add(comm.binding.ScalarMult(ctx.bindingFactors[id]))
+		if !ctx.evenR {
+			commShare = commShare.Negate()
+		}
+		lambda := lagrangeAtZero(ctx.suite, id, ctx.ids)
+		expected := commShare.Add(public.verification[id].ScalarMult(new(big.Int).Mul(ctx.challenge, lambda)))
+		if !ctx.suite.BaseMult(zi).Equal(expected) {
+			return nil, fmt.Errorf("signature share from party %d does not verify", id)
+		}
+		z.Add(z, zi)
+	}
+	z.Mod(z, q)
+	sig := ctx.suite.encodeSignature(ctx.groupCommitment, z)
+	if !ctx.suite.verify(public.groupKey, msg, sig) {
+		return nil, errors.New("aggregated signature does not verify")
+	}
+	return sig, nil
+}
+
+// Verify 用群公钥校验签名
+func Verify(suiteName string, groupKey []byte, msg, sig []byte) (bool, error) {
+	suite, err := SuiteByName(suiteName)
+	if err != nil {
+		return false, err
+	}
+	PK, err := suite.DecodeElement(groupKey)
+	if err != nil {
+		return false, err
+	}
+	return suite.verify(PK, msg, sig), nil
+}
+
+type decodedCommitment struct {
+	hiding, binding Element
+}
+
+// signingContext 一次签名中所有签名方都能算出的公共值
+type signingContext struct {
+	suite           Ciphersuite
+	ids             []int
+	commitments     map[int]decodedCommitment
+	bindingFactors  map[int]*big.Int
+	groupCommitment Element
+	evenR           bool
+	challenge       *big.Int
+}
+
+func (s *KeyShare) signingContext(msg []byte, commitments []*Commitment) (*signingContext, error) {
+	suite, err := SuiteByName(s.Suite)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitments) < s.Threshold {
+		return nil, fmt.Errorf("need at least %d signers, got %d", s.Threshold, len(commitments))
+	}
+	sorted := append([]*Commitment(nil), commitments...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	ctx := &signingContext{
+		suite:          suite,
+		commitments:    make(map[int]decodedCommitment, len(sorted)),
+		bindingFactors: make(map[int]*big.Int, len(sorted)),
+	}
+	// encode_group_commitment_list：按 id 升序拼接 id || D || E
+	var encoded []byte
+	for _, c := range sorted {
+		if _, dup := ctx.commitments[c.ID]; dup {
+			return nil, fmt.Errorf("duplicate commitment from party %d", c.ID)
+		}
+		if !containsParty(s.Parties, c.ID) {
+			return nil, fmt.Errorf("party %d does not hold a share of this key", c.ID)
+		}
+		hiding, err := suite.DecodeElement(c.Hiding)
+		if err != nil {
+			return nil, fmt.Errorf("commitment of party %d: %w", c.ID, err)
+		}
+		binding, err := suite.DecodeElement(c.Binding)
+		if err != nil {
+			return nil, fmt.Errorf("commitment of party %d: %w", c.ID, err)
+		}
+		ctx.ids = append(ctx.ids, c.ID)
+		ctx.commitments[c.ID] = decodedCommitment{hiding: hiding, binding: binding}
+		encoded = append(encoded, suite.EncodeScalar(big.NewInt(int64(c.ID)))...)
+		encoded = append(encoded, c.Hiding...)
+		encoded = append(encoded, c.Binding...)
+	}
+
+	groupKey, err := suite.DecodeElement(s.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("group key: %w", err)
+	}
+	prefix := append([]byte(nil), s.GroupKey...)
+	prefix = append(prefix, suite.h4(msg)...)
+	prefix = append(prefix, suite.h5(encoded)...)
+	R := suite.Identity()
+	for _, id := range ctx.ids {
+		input := append(append([]byte(nil), prefix...), suite.EncodeScalar(big.NewInt(int64(id)))...)
+		rho := suite.h1(input)
+		ctx.bindingFactors[id] = rho
+		comm := ctx.commitments[id]
+		R = R.Add(comm.hiding).Add(comm.binding.ScalarMult(rho))
+	}
+	if R.IsIdentity() {
+		return nil, errors.New("group commitment is the identity")
+	}
+	ctx.evenR = !suite.evenY() || suite.hasEvenY(R)
+	if !ctx.evenR {
+		R = R.Negate()
+	}
+	ctx.groupCommitment = R
+	ctx.challenge = suite.challenge(R, groupKey, msg)
+	return ctx, nil
+}
+
+// generateNonce RFC 9591 nonce_generate：H3(random_bytes(32) || SerializeScalar(secret))
+func generateNonce(suite Ciphersuite, secret *big.Int) (*big.Int, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	return suite.h3(append(random, suite.EncodeScalar(secret)...)), nil
+}
+
+func containsParty(parties []int, party int) bool {
+	for _, p := range parties {
+		if p == party {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,390 @@
+// Package frost 实现 FROST（RFC 9591）门限 Schnorr 签名的密码学部分：分布式密钥生成、两轮签名、
+// 签名分片校验与合成，以及不改变群公钥的分片刷新。消息收发由调用方负责（见 tss 包）。
+//
+// 支持两个密码套件：ed25519 即 RFC 9591 的 FROST(Ed25519, SHA-512)，输出标准 Ed25519 签名；
+// secp256k1-tr 在 FROST(secp256k1, SHA-256) 的基础上改用 BIP340 的挑战和 x-only 公钥，输出 Taproot 可用的 Schnorr 签名。
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"filippo.io/edwards25519"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+const (
+	SuiteEd25519     = "ed25519"
+	SuiteSecp256k1TR = "secp256k1-tr"
+)
+
+var ErrInvalidElement = errors.New("invalid group element")
+
+// Element 群元素
+type Element interface {
+	Add(other Element) Element
+	ScalarMult(k *big.Int) Element
+	Negate() Element
+	Equal(other Element) bool
+	IsIdentity() bool
+	Bytes() []byte
+}
+
+// Ciphersuite 对应 RFC 9591 第 6 节中的一个密码套件：素数阶群、标量编码以及 H1 ~ H5 哈希
+type Ciphersuite interface {
+	Name() string
+	Order() *big.Int
+	Identity() Element
+	BaseMult(k *big.Int) Element
+	DecodeElement(data []byte) (Element, error)
+	EncodeScalar(k *big.Int) []byte
+
+	h1(m []byte) *big.Int
+	h3(m []byte) *big.Int
+	h4(m []byte) []byte
+	h5(m []byte) []byte
+	// hdkg 分布式密钥生成中知识证明的挑战
+	hdkg(m []byte) *big.Int
+	challenge(R, PK Element, msg []byte) *big.Int
+	// evenY 为 true 时群公钥和 R 都规范为偶数 y（BIP340 的 x-only 约定）
+	evenY() bool
+	hasEvenY(e Element) bool
+	encodeSignature(R Element, z *big.Int) []byte
+	verify(PK Element, msg, sig []byte) bool
+}
+
+// SuiteByName 返回名字对应的密码套件
+func SuiteByName(name string) (Ciphersuite, error) {
+	switch name {
+	case SuiteEd25519:
+		return ed25519Suite{}, nil
+	case SuiteSecp256k1TR:
+		return secp256k1TRSuite{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported frost ciphersuite %q", name)
+	}
+}
+
+// ed25519Suite 即 RFC 9591 的 FROST(Ed25519, SHA-512)，签名可以直接用 ed25519.Verify 校验
+type ed25519Suite struct{}
+
+const ed25519Context = "FROST-ED25519-SHA512-v1"
+
+var ed25519Order, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+type edElement struct {
+	p *edwards25519.Point
+}
+
+func (ed25519Suite) Name() string      { return SuiteEd25519 }
+func (ed25519Suite) Order() *big.Int   { return ed25519Order }
+func (ed25519Suite) Identity() Element { return edElement{p: edwards25519.NewIdentityPoint()} }
+
+func (ed25519Suite) BaseMult(k *big.Int) Element {
+	return edElement{p: new(edwards25519.Point).ScalarBaseMult(edScalar(k))}
+}
+
+// DecodeElement 拒绝非规范编码、单位元以及不在素数阶子群中的点
+func (ed25519Suite) DecodeElement(data []byte) (Element, error) {
+	p, err := new(edwards25519.Point).SetBytes(data)
+	if err != nil || string(p.Bytes()) != string(data) {
+		return nil, ErrInvalidElement
+	}
+	if p.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, ErrInvalidElement
+	}
+	// L·P = (L-1)·P + P 为单位元说明 P 在素数阶子群中
+	lMinusOne := new(big.Int).Sub(ed25519Order, big.NewInt(1))
+	check := new(edwards25519.Point).ScalarMult(edScalar(lMinusOne), p)
+	if check.Add(check, p).Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return nil, ErrInvalidElement
+	}
+	return edElement{p: p}, nil
+}
+
+// EncodeScalar 32 字节小端
+func (ed25519Suite) EncodeScalar(k *big.Int) []byte {
+	out := make([]byte, 32)
+	new(big.Int).Mod(k, ed25519Order).FillBytes(out)
+	reverse(out)
+	return out
+}
+
+func (s ed25519Suite) h1(m []byte) *big.Int {
+	return s.hashToScalar([]byte(ed25519Context), []byte("rho"), m)
+}
+func (s ed25519Suite) h3(m []byte) *big.Int {
+	return s.hashToScalar([]byte(ed25519Context), []byte("nonce"), m)
+}
+func (ed25519Suite) h4(m []byte) []byte { return sha512Sum([]byte(ed25519Context), []byte("msg"), m) }
+func (ed25519Suite) h5(m []byte) []byte { return sha512Sum([]byte(ed25519Context), []byte("com"), m) }
+func (s ed25519Suite) hdkg(m []byte) *big.Int {
+	return s.hashToScalar([]byte(ed25519Context), []byte("dkg"), m)
+}
+
+// challenge 即 H2，与 RFC 8032 一致不带上下文串
+func (s ed25519Suite) challenge(R, PK Element, msg []byte) *big.Int {
+	return s.hashToScalar(R.Bytes(), PK.Bytes(), msg)
+}
+
+func (ed25519Suite) evenY() bool             { return false }
+func (ed25519Suite) hasEvenY(e Element) bool { return true }
+
+func (s ed25519Suite) encodeSignature(R Element, z *big.Int) []byte {
+	return append(R.Bytes(), s.EncodeScalar(z)...)
+}
+
+func (ed25519Suite) verify(PK Element, msg, sig []byte) bool {
+	return ed25519.Verify(PK.Bytes(), msg, sig)
+}
+
+// hashToScalar SHA-512 的结果按小端解释后模 L
+func (ed25519Suite) hashToScalar(parts ...[]byte) *big.Int {
+	digest := sha512Sum(parts...)
+	reverse(digest)
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest), ed25519Order)
+}
+
+func edScalar(k *big.Int) *edwards25519.Scalar {
+	buf := ed25519Suite{}.EncodeScalar(k)
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(buf)
+	if err != nil {
+		// EncodeScalar 已经模 L，不会出现非规范编码
+		panic(err)
+	}
+	return s
+}
+
+func (e edElement) Add(other Element) Element {
+	return edElement{p: new(edwards25519.Point).Add(e.p, other.(edElement).p)}
+}
+
+func (e edElement) ScalarMult(k *big.Int) Element {
+	return edElement{p: new(edwards25519.Point).ScalarMult(edScalar(k), e.p)}
+}
+
+func (e edElement) Negate() Element {
+	return edElement{p: new(edwards25519.Point).Negate(e.p)}
+}
+
+func (e edElement) Equal(other Element) bool {
+	return e.p.Equal(other.(edElement).p) == 1
+}
+
+func (e edElement) IsIdentity() bool {
+	return e.p.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func (e edElement) Bytes() []byte {
+	return e.p.Bytes()
+}
+
+// secp256k1TRSuite 沿用 RFC 9591 FROST(secp256k1, SHA-256) 的哈希，挑战改为 BIP340 的 tagged hash，
+// 群公钥和 R 规范为偶数 y，得到的签名是可用于 Taproot key path 的 BIP340 Schnorr 签名
+type secp256k1TRSuite struct{}
+
+const secp256k1TRContext = "FROST-secp256k1-SHA256-TR-v1"
+
+var secp256k1 = btcec.S256()
+
+// secpElement 仿射坐标，X 为 nil 表示无穷远点
+type secpElement struct {
+	x, y *big.Int
+}
+
+func (secp256k1TRSuite) Name() string      { return SuiteSecp256k1TR }
+func (secp256k1TRSuite) Order() *big.Int   { return secp256k1.N }
+func (secp256k1TRSuite) Identity() Element { return secpElement{} }
+
+func (secp256k1TRSuite) BaseMult(k *big.Int) Element {
+	k = new(big.Int).Mod(k, secp256k1.N)
+	if k.Sign() == 0 {
+		return secpElement{}
+	}
+	x, y := secp256k1.ScalarBaseMult(k.Bytes())
+	return secpElement{x: x, y: y}
+}
+
+// DecodeElement 只接受 33 字节压缩编码
+func (secp256k1TRSuite) DecodeElement(data []byte) (Element, error) {
+	if len(data) != btcec.PubKeyBytesLenCompressed {
+		return nil, ErrInvalidElement
+	}
+	pub, err := btcec.ParsePubKey(data)
+	if err != nil {
+		return nil, ErrInvalidElement
+	}
+	return secpElement{x: pub.X(), y: pub.Y()}, nil
+}
+
+// EncodeScalar 32 字节大端
+func (secp256k1TRSuite) EncodeScalar(k *big.Int) []byte {
+	out := make([]byte, 32)
+	new(big.Int).Mod(k, secp256k1.N).FillBytes(out)
+	return out
+}
+
+func (secp256k1TRSuite) h1(m []byte) *big.Int { return hashToField(m, secp256k1TRContext+"rho") }
+func (secp256k1TRSuite) h3(m []byte) *big.Int { return hashToField(m, secp256k1TRContext+"nonce") }
+func (secp256k1TRSuite) h4(m []byte) []byte {
+	return sha256Sum([]byte(secp256k1TRContext), []byte("msg"), m)
+}
+func (secp256k1TRSuite) h5(m []byte) []byte {
+	return sha256Sum([]byte(secp256k1TRContext), []byte("com"), m)
+}
+func (secp256k1TRSuite) hdkg(m []byte) *big.Int { return hashToField(m, secp256k1TRContext+"dkg") }
+
+// challenge BIP340: int(hash_BIP0340/challenge(x(R) || x(P) || m)) mod n
+func (secp256k1TRSuite) challenge(R, PK Element, msg []byte) *big.Int {
+	digest := chainhash.TaggedHash(chainhash.TagBIP0340Challenge, xOnly(R), xOnly(PK), msg)
+	return new(big.Int).Mod(new(big.Int).SetBytes(digest[:]), secp256k1.N)
+}
+
+func (secp256k1TRSuite) evenY() bool { return true }
+
+func (secp256k1TRSuite) hasEvenY(e Element) bool {
+	p := e.(secpElement)
+	return p.y != nil && p.y.Bit(0) == 0
+}
+
+func (s secp256k1TRSuite) encodeSignature(R Element, z *big.Int) []byte {
+	return append(xOnly(R), s.EncodeScalar(z)...)
+}
+
+// verify 按 BIP340 校验：PK 只取 x 坐标，z·G - c·P 必须是 x 坐标等于 r 的偶数 y 点
+func (s secp256k1TRSuite) verify(PK Element, msg, sig []byte) bool {
+	if len(sig) != 64 || PK.IsIdentity() {
+		return false
+	}
+	if !s.hasEvenY(PK) {
+		PK = PK.Negate()
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	z := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(secp256k1.P) >= 0 || z.Cmp(secp256k1.N) >= 0 {
+		return false
+	}
+	R, err := s.DecodeElement(append([]byte{0x02}, sig[:32]...))
+	if err != nil {
+		return false
+	}
+	c := s.challenge(R, PK, msg)
+	check := s.BaseMult(z).Add(PK.ScalarMult(c).Negate())
+	return !check.IsIdentity() && s.hasEvenY(check) && check.(secpElement).x.Cmp(r) == 0
+}
+
+func (e secpElement) Add(other Element) Element {
+	o := other.(secpElement)
+	if e.x == nil {
+		return o
+	}
+	if o.x == nil {
+		return e
+	}
+	x, y := secp256k1.Add(e.x, e.y, o.x, o.y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return secpElement{}
+	}
+	return secpElement{x: x, y: y}
+}
+
+func (e secpElement) ScalarMult(k *big.Int) Element {
+	k = new(big.Int).Mod(k, secp256k1.N)
+	if e.x == nil || k.Sign() == 0 {
+		return secpElement{}
+	}
+	x, y := secp256k1.ScalarMult(e.x, e.y, k.Bytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return secpElement{}
+	}
+	return secpElement{x: x, y: y}
+}
+
+func (e secpElement) Negate() Element {
+	if e.x == nil {
+		return e
+	}
+	return secpElement{x: e.x, y: new(big.Int).Sub(secp256k1.P, e.y)}
+}
+
+func (e secpElement) Equal(other Element) bool {
+	o := other.(secpElement)
+	if e.x == nil || o.x == nil {
+		return e.x == nil && o.x == nil
+	}
+	return e.x.Cmp(o.x) == 0 && e.y.Cmp(o.y) == 0
+}
+
+func (e secpElement) IsIdentity() bool {
+	return e.x == nil
+}
+
+// Bytes 33 字节压缩编码，无穷远点编码为 33 个 0
+func (e secpElement) Bytes() []byte {
+	out := make([]byte, btcec.PubKeyBytesLenCompressed)
+	if e.x == nil {
+		return out
+	}
+	out[0] = 0x02 + byte(e.y.Bit(0))
+	e.x.FillBytes(out[1:])
+	return out
+}
+
+func xOnly(e Element) []byte {
+	return e.Bytes()[1:]
+}
+
+// hashToField RFC 9380 的 hash_to_field，expand_message_xmd(SHA-256) 输出 48 字节后模 n
+func hashToField(msg []byte, dst string) *big.Int {
+	const length = 48
+	dstPrime := append([]byte(dst), byte(len(dst)))
+	msgPrime := make([]byte, 0, sha256.BlockSize+len(msg)+3+len(dstPrime))
+	msgPrime = append(msgPrime, make([]byte, sha256.BlockSize)...)
+	msgPrime = append(msgPrime, msg...)
+	msgPrime = append(msgPrime, 0, length, 0)
+	msgPrime = append(msgPrime, dstPrime...)
+	b0 := sha256.Sum256(msgPrime)
+
+	// b_1 = H(b_0 || 1 || DST')，b_i = H((b_0 xor b_(i-1)) || i || DST')，prev 初始为 0 使两者形式一致
+	uniform := make([]byte, 0, 2*sha256.Size)
+	prev := make([]byte, sha256.Size)
+	for i := 1; len(uniform) < length; i++ {
+		input := make([]byte, 0, sha256.Size+1+len(dstPrime))
+		for j := range prev {
+			input = append(input, b0[j]^prev[j])
+		}
+		input = append(input, byte(i))
+		input = append(input, dstPrime...)
+		bi := sha256.Sum256(input)
+		prev = bi[:]
+		uniform = append(uniform, bi[:]...)
+	}
+	return new(big.Int).Mod(new(big.Int).SetBytes(uniform[:length]), secp256k1.N)
+}
+
+func sha512Sum(parts ...[]byte) []byte {
+	h := sha512.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+func sha256Sum(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
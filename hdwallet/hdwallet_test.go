@@ -0,0 +1,70 @@
+package hdwallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// 测试向量来自 BIP-32 test vector 1 和 SLIP-10 ed25519 test vector 1
+var testSeed, _ = hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+func TestDeriveSecp256k1(t *testing.T) {
+	key, err := DeriveSecp256k1(testSeed, "m/0'/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"
+	if got := hex.EncodeToString(key.Serialize()); got != want {
+		t.Fatalf("private key = %s, want %s", got, want)
+	}
+}
+
+func TestDeriveEd25519(t *testing.T) {
+	key, err := DeriveEd25519(testSeed, "m/0'/1'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"
+	if got := hex.EncodeToString(key.Seed()); got != want {
+		t.Fatalf("private key = %s, want %s", got, want)
+	}
+	if _, err := DeriveEd25519(testSeed, "m/0'/1"); err == nil {
+		t.Fatal("expected error for non-hardened ed25519 path")
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	indexes, err := ParsePath(Bip44(CoinTypeEthereum)(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint32{44 + HardenedOffset, 60 + HardenedOffset, HardenedOffset, 0, 7}
+	for i := range want {
+		if indexes[i] != want[i] {
+			t.Fatalf("indexes = %v, want %v", indexes, want)
+		}
+	}
+	for _, bad := range []string{"", "44'/0'", "m/x", "m/2147483648"} {
+		if _, err := ParsePath(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestEncryptSeed(t *testing.T) {
+	encrypted, err := EncryptSeed(testSeed, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, err := DecryptSeed(encrypted, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(seed, testSeed) {
+		t.Fatal("decrypted seed mismatch")
+	}
+	if _, err := DecryptSeed(encrypted, "wrong"); err != ErrInvalidPassphrase {
+		t.Fatalf("err = %v, want ErrInvalidPassphrase", err)
+	}
+}
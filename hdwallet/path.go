@@ -0,0 +1,58 @@
+package hdwallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HardenedOffset BIP-32 中 hardened 子索引的起始值
+const HardenedOffset uint32 = 0x80000000
+
+// 各链在 BIP-44 中注册的 coin type
+const (
+	CoinTypeBitcoin  uint32 = 0
+	CoinTypeEthereum uint32 = 60
+	CoinTypeSolana   uint32 = 501
+)
+
+// PathFunc 根据地址索引生成派生路径
+type PathFunc func(index uint32) string
+
+// Bip44 返回 secp256k1 链使用的 BIP-44 路径 m/44'/coin'/0'/0/index
+func Bip44(coinType uint32) PathFunc {
+	return func(index uint32) string {
+		return fmt.Sprintf("m/44'/%d'/0'/0/%d", coinType, index)
+	}
+}
+
+// Slip10 返回 Ed25519 链使用的路径 m/44'/coin'/index'/0'，SLIP-10 只支持 hardened 派生
+func Slip10(coinType uint32) PathFunc {
+	return func(index uint32) string {
+		return fmt.Sprintf("m/44'/%d'/%d'/0'", coinType, index)
+	}
+}
+
+// ParsePath 把 "m/44'/60'/0'/0/1" 形式的路径解析成子索引列表
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+	if len(segments) == 0 || segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		hardened := strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h")
+		if hardened {
+			segment = segment[:len(segment)-1]
+		}
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil || uint32(index) >= HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q: bad segment %q", path, segment)
+		}
+		if hardened {
+			index += uint64(HardenedOffset)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
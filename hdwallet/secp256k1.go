@@ -0,0 +1,27 @@
+package hdwallet
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// DeriveSecp256k1 按 BIP-32 从种子派生 secp256k1 私钥
+func DeriveSecp256k1(seed []byte, path string) (*btcec.PrivateKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	// 网络参数只影响扩展密钥的序列化前缀，不影响派生出的私钥
+	key, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		key, err = key.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key.ECPrivKey()
+}
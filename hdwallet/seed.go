@@ -0,0 +1,91 @@
+package hdwallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+
+	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	seedVersion  byte = 1
+	seedSaltSize      = 16
+	// scrypt 参数，与以太坊 keystore 的 standard 档位一致
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+)
+
+var ErrInvalidPassphrase = errors.New("invalid seed passphrase")
+
+// NewMnemonic 生成 24 个单词的 BIP-39 助记词
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// SeedFromMnemonic 校验助记词并按 BIP-39 计算 64 字节种子
+func SeedFromMnemonic(mnemonic, password string) ([]byte, error) {
+	return bip39.NewSeedWithErrorChecking(mnemonic, password)
+}
+
+// EncryptSeed 用口令加密种子，格式为 version || salt || nonce || AES-256-GCM 密文
+func EncryptSeed(seed []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("seed passphrase is empty")
+	}
+	salt := make([]byte, seedSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := seedCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := append([]byte{seedVersion}, salt...)
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, seed, header), nil
+}
+
+// DecryptSeed 解密 EncryptSeed 的输出
+func DecryptSeed(data []byte, passphrase string) ([]byte, error) {
+	if len(data) < 1+seedSaltSize || data[0] != seedVersion {
+		return nil, errors.New("unsupported encrypted seed format")
+	}
+	header := data[:1+seedSaltSize]
+	aead, err := seedCipher(passphrase, header[1:])
+	if err != nil {
+		return nil, err
+	}
+	rest := data[len(header):]
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("encrypted seed is truncated")
+	}
+	seed, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+	return seed, nil
+}
+
+func seedCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
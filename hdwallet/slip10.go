@@ -0,0 +1,39 @@
+package hdwallet
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+)
+
+var ed25519SeedKey = []byte("ed25519 seed")
+
+// DeriveEd25519 按 SLIP-10 从种子派生 Ed25519 私钥，路径中的每一级都必须是 hardened
+func DeriveEd25519(seed []byte, path string) (ed25519.PrivateKey, error) {
+	indexes, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha512.New, ed25519SeedKey)
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	for _, index := range indexes {
+		if index < HardenedOffset {
+			return nil, fmt.Errorf("invalid derivation path %q: ed25519 only supports hardened derivation", path)
+		}
+		data := make([]byte, 0, 37)
+		data = append(data, 0x00)
+		data = append(data, key...)
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac = hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum = mac.Sum(nil)
+		key, chainCode = sum[:32], sum[32:]
+	}
+	return ed25519.NewKeyFromSeed(key), nil
+}
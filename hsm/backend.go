@@ -0,0 +1,39 @@
+package hsm
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	BackendGcp    = "gcp"
+	BackendPkcs11 = "pkcs11"
+	BackendMemory = "memory"
+)
+
+// secp256k1 签名的编码，由后端声明，不按长度猜测
+const (
+	// SignatureDER ASN.1 DER 编码的 ECDSA-Sig-Value（GCP KMS、Vault）
+	SignatureDER = "der"
+	// SignatureRaw 定长 32 字节 r || 32 字节 s（PKCS#11 CKM_ECDSA）
+	SignatureRaw = "raw"
+)
+
+var (
+	ErrKeyNotFound          = errors.New("hsm key not found")
+	ErrUnsupportedAlgorithm = errors.New("unsupported hsm key algorithm")
+)
+
+// Backend 私钥保存在外部 KMS/HSM 中的签名后端，私钥不会离开后端，只能通过 keyName 引用。
+//
+// 公钥格式与本地签名器一致：secp256k1 为 65 字节非压缩公钥，ed25519 为 32 字节公钥。
+// SignDigest 对 secp256k1 传入 32 字节摘要，返回 SignatureFormat 声明的编码（GCP KMS 为 DER，PKCS#11 为 64 字节 r || s）；
+// Ed25519 自带哈希，传入的是待签名的原始消息，返回 64 字节签名
+type Backend interface {
+	Name() string
+	SignatureFormat() string
+	CreateKey(ctx context.Context, curve string) (keyName string, err error)
+	PublicKey(ctx context.Context, keyName string) ([]byte, error)
+	SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error)
+	ListKeys(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,64 @@
+package hsm
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var secp256k1N = btcec.S256().N
+
+// recoverableSignature 把 HSM 返回的 (r, s) 转成与 crypto.Sign 相同的 r || s || v：s 规范为 low-S，
+// HSM 不返回恢复 id，依次尝试 v = 0、1，取能恢复出 pubKey（65 字节非压缩）的那个
+func recoverableSignature(hash []byte, r, s *big.Int, pubKey []byte) ([]byte, error) {
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("ecdsa signature out of range")
+	}
+	if s.Cmp(new(big.Int).Rsh(secp256k1N, 1)) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		recovered, err := crypto.Ecrecover(hash, sig)
+		if err == nil && bytes.Equal(recovered, pubKey) {
+			return sig, nil
+		}
+	}
+	return nil, errors.New("signature does not recover to the key's public key")
+}
+
+// parseRawSignature 按后端声明的编码解析 secp256k1 签名：SignatureRaw 为 64 字节 r || s，SignatureDER 为 DER
+func parseRawSignature(format string, sig []byte) (*big.Int, *big.Int, error) {
+	switch format {
+	case SignatureRaw:
+		if len(sig) != 64 {
+			return nil, nil, fmt.Errorf("raw signature must be 64 bytes, got %d", len(sig))
+		}
+		return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), nil
+	case SignatureDER:
+		return parseDERSignature(sig)
+	}
+	return nil, nil, fmt.Errorf("unknown signature format %q", format)
+}
+
+func parseDERSignature(sig []byte) (*big.Int, *big.Int, error) {
+	var der struct {
+		R, S *big.Int
+	}
+	rest, err := asn1.Unmarshal(sig, &der)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rest) != 0 {
+		return nil, nil, errors.New("trailing data after der signature")
+	}
+	return der.R, der.S, nil
+}
@@ -0,0 +1,193 @@
+package hsm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/ethereum/go-ethereum/log"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// keyReadyPoll HSM 保护级别的密钥版本异步生成，创建后轮询直到可用
+const keyReadyPoll = 500 * time.Millisecond
+
+// GcpBackend 基于 Cloud KMS 的签名后端，新密钥创建在 keyRing（projects/*/locations/*/keyRings/*）下，
+// keyName 是密钥版本的完整资源名
+type GcpBackend struct {
+	client  *HsmClient
+	keyRing string
+
+	// algorithms 密钥版本的签名算法，创建后不会改变，第一次签名时从 KMS 读取
+	mu         sync.Mutex
+	algorithms map[string]kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+}
+
+var _ Backend = (*GcpBackend)(nil)
+
+func NewGcpBackend(client *HsmClient, keyRing string) *GcpBackend {
+	return &GcpBackend{
+		client:     client,
+		keyRing:    keyRing,
+		algorithms: make(map[string]kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm),
+	}
+}
+
+func (b *GcpBackend) Name() string {
+	return BackendGcp
+}
+
+// SignatureFormat Cloud KMS 的 EC_SIGN_SECP256K1_SHA256 返回 DER
+func (b *GcpBackend) SignatureFormat() string {
+	return SignatureDER
+}
+
+func (b *GcpBackend) CreateKey(ctx context.Context, curve string) (string, error) {
+	if b.keyRing == "" {
+		return "", errors.New("hsm key ring is not configured")
+	}
+	var algorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	switch curve {
+	case ssm.CurveSecp256k1:
+		algorithm = kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256
+	case ssm.CurveEd25519:
+		algorithm = kmspb.CryptoKeyVersion_EC_SIGN_ED25519
+	default:
+		return "", fmt.Errorf("unsupported hsm curve %q", curve)
+	}
+	var suffix [8]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", err
+	}
+	key, err := b.client.Gclient.CreateCryptoKey(ctx, &kmspb.CreateCryptoKeyRequest{
+		Parent:      b.keyRing,
+		CryptoKeyId: "wallet-" + curve + "-" + hex.EncodeToString(suffix[:]),
+		CryptoKey: &kmspb.CryptoKey{
+			Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
+			VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
+				Algorithm:       algorithm,
+				ProtectionLevel: kmspb.ProtectionLevel_HSM,
+			},
+		},
+	})
+	if err != nil {
+		log.Error("create kms key fail", "err", err)
+		return "", err
+	}
+	keyName := key.Name + "/cryptoKeyVersions/1"
+	for {
+		version, err := b.client.Gclient.GetCryptoKeyVersion(ctx, &kmspb.GetCryptoKeyVersionRequest{Name: keyName})
+		if err != nil {
+			return "", err
+		}
+		switch version.State {
+		case kmspb.CryptoKeyVersion_ENABLED:
+			return keyName, nil
+		case kmspb.CryptoKeyVersion_PENDING_GENERATION:
+		default:
+			return "", fmt.Errorf("kms key %s is in state %s", keyName, version.State)
+		}
+		select {
+		case <-time.After(keyReadyPoll):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+func (b *GcpBackend) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	return b.client.publicKey(ctx, keyName)
+}
+
+func (b *GcpBackend) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	algorithm, err := b.algorithm(ctx, keyName)
+	if err != nil {
+		return nil, err
+	}
+	req := &kmspb.AsymmetricSignRequest{Name: keyName}
+	if algorithm == kmspb.CryptoKeyVersion_EC_SIGN_ED25519 {
+		req.Data = digest
+	} else {
+		if len(digest) != 32 {
+			return nil, errors.New("digest must be 32 bytes")
+		}
+		req.Digest = &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}}
+	}
+	resp, err := b.client.Gclient.AsymmetricSign(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			b.mu.Lock()
+			delete(b.algorithms, keyName)
+			b.mu.Unlock()
+			return nil, ErrKeyNotFound
+		}
+		log.Error("kms asymmetric sign fail", "key", keyName, "err", err)
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// algorithm 返回密钥版本的签名算法，每个版本只查询一次 KMS
+func (b *GcpBackend) algorithm(ctx context.Context, keyName string) (kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm, error) {
+	b.mu.Lock()
+	algorithm, ok := b.algorithms[keyName]
+	b.mu.Unlock()
+	if ok {
+		return algorithm, nil
+	}
+	version, err := b.client.Gclient.GetCryptoKeyVersion(ctx, &kmspb.GetCryptoKeyVersionRequest{Name: keyName})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, ErrKeyNotFound
+		}
+		return 0, err
+	}
+	b.mu.Lock()
+	b.algorithms[keyName] = version.Algorithm
+	b.mu.Unlock()
+	return version.Algorithm, nil
+}
+
+// ListKeys 列出 keyRing 下所有可用的签名密钥版本
+func (b *GcpBackend) ListKeys(ctx context.Context) ([]string, error) {
+	if b.keyRing == "" {
+		return nil, errors.New("hsm key ring is not configured")
+	}
+	var names []string
+	keys := b.client.Gclient.ListCryptoKeys(ctx, &kmspb.ListCryptoKeysRequest{Parent: b.keyRing})
+	for {
+		key, err := keys.Next()
+		if errors.Is(err, iterator.Done) {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key.Purpose != kmspb.CryptoKey_ASYMMETRIC_SIGN {
+			continue
+		}
+		versions := b.client.Gclient.ListCryptoKeyVersions(ctx, &kmspb.ListCryptoKeyVersionsRequest{
+			Parent: key.Name,
+			Filter: "state=ENABLED",
+		})
+		for {
+			version, err := versions.Next()
+			if errors.Is(err, iterator.Done) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, version.Name)
+		}
+	}
+}
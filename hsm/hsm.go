@@ -2,27 +2,44 @@ package hsm
 
 import (
 	"context"
+	"encoding/asn1"
 	"encoding/hex"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
 	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
-	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
 )
 
 type HsmClient struct {
 	Ctx     context.Context
 	KeyName string
 	Gclient *kms.KeyManagementClient
+
+	// signPubKey KeyName 对应的公钥，第一次签名时从 KMS 读取
+	signPubKey []byte
+	pubKeyMu   sync.Mutex
 }
 
 func NewHSMClient(ctx context.Context, keyPath string, keyName string) (*HsmClient, error) {
 	apikey := option.WithCredentialsFile(keyPath)
+	// 每次 KMS RPC 产生一个 client span，并把 trace 上下文传给 Cloud KMS
+	traced := option.WithGRPCDialOption(grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 
-	client, err := kms.NewKeyManagementClient(ctx, apikey)
+	client, err := kms.NewKeyManagementClient(ctx, apikey, traced)
 	if err != nil {
 		log.Error("new key manager client fail", "err", err)
 		return nil, err
@@ -31,8 +48,13 @@ func NewHSMClient(ctx context.Context, keyPath string, keyName string) (*HsmClie
 	return &HsmClient{Ctx: ctx, KeyName: keyName, Gclient: client}, nil
 }
 
+// SignTransaction 用 KeyName 对 32 字节哈希签名。KMS 返回 DER 编码的 (r, s)，这里转换成以太坊使用的
+// 65 字节 r || s || v（low-S，恢复 id 通过与 KMS 公钥比对得到）
 func (hsm *HsmClient) SignTransaction(hash string) (string, error) {
-	hashByte, _ := hex.DecodeString(hash)
+	hashByte, err := hex.DecodeString(hash)
+	if err != nil || len(hashByte) != 32 {
+		return common.Hash{}.String(), errors.New("hash must be 32 bytes hex")
+	}
 	req := kmspb.AsymmetricSignRequest{
 		Name: hsm.KeyName,
 		Digest: &kmspb.Digest{
@@ -45,7 +67,64 @@ func (hsm *HsmClient) SignTransaction(hash string) (string, error) {
 	if err != nil {
 		return common.Hash{}.String(), err
 	}
-	return hex.EncodeToString(resp.Signature), nil
+	// Cloud KMS 的 ECDSA 签名为 DER
+	r, s, err := parseRawSignature(SignatureDER, resp.Signature)
+	if err != nil {
+		log.Error("parse kms signature fail", "err", err)
+		return common.Hash{}.String(), err
+	}
+	pubKey, err := hsm.signingPublicKey()
+	if err != nil {
+		log.Error("get kms public key fail", "err", err)
+		return common.Hash{}.String(), err
+	}
+	signature, err := recoverableSignature(hashByte, r, s, pubKey)
+	if err != nil {
+		return common.Hash{}.String(), err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+func (hsm *HsmClient) signingPublicKey() ([]byte, error) {
+	hsm.pubKeyMu.Lock()
+	defer hsm.pubKeyMu.Unlock()
+	if hsm.signPubKey != nil {
+		return hsm.signPubKey, nil
+	}
+	pubKey, err := hsm.publicKey(hsm.Ctx, hsm.KeyName)
+	if err != nil {
+		return nil, err
+	}
+	hsm.signPubKey = pubKey
+	return pubKey, nil
+}
+
+// publicKey 读取 KMS 公钥。x509 不支持 secp256k1，这里直接解析 ASN.1
+func (hsm *HsmClient) publicKey(ctx context.Context, keyName string) ([]byte, error) {
+	resp, err := hsm.Gclient.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: keyName})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return parsePublicKeyPem(resp.Pem)
+}
+
+// parsePublicKeyPem 从 PEM 编码的 SubjectPublicKeyInfo 中取出原始公钥
+func parsePublicKeyPem(data string) ([]byte, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid public key pem")
+	}
+	var spki struct {
+		Algorithm asn1.RawValue
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(block.Bytes, &spki); err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return spki.PublicKey.RightAlign(), nil
 }
 
 func (hsm *HsmClient) CreateKeyRing(projectID, locationID, keyRingID string) (string, error) {
@@ -61,25 +140,24 @@ func (hsm *HsmClient) CreateKeyRing(projectID, locationID, keyRingID string) (st
 	return keyRingID, nil
 }
 
+// CreateKeyPair 在 key ring 下创建 HSM 保护的签名密钥，method 为 secp256k1（ecdsa）或 ed25519（eddsa），其它算法直接拒绝
 func (hsm *HsmClient) CreateKeyPair(projectID, locationID, keyRingID, keyID, method string) (string, error) {
+	var algorithm kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm
+	switch method {
+	case "ecdsa", ssm.CurveSecp256k1:
+		algorithm = kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256
+	case "eddsa", ssm.CurveEd25519:
+		algorithm = kmspb.CryptoKeyVersion_EC_SIGN_ED25519
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, method)
+	}
 	parent := fmt.Sprintf("projects/%s/locations/%s/keyRings/%s", projectID, locationID, keyRingID)
-	var key *kmspb.CryptoKey
-	if method == "ecdsa" {
-		key = &kmspb.CryptoKey{
-			Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
-			VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
-				Algorithm:       kmspb.CryptoKeyVersion_EC_SIGN_SECP256K1_SHA256,
-				ProtectionLevel: kmspb.ProtectionLevel_HSM,
-			},
-		}
-	} else {
-		key = &kmspb.CryptoKey{
-			Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
-			VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
-				Algorithm:       kmspb.CryptoKeyVersion_RSA_SIGN_RAW_PKCS1_4096,
-				ProtectionLevel: kmspb.ProtectionLevel_HSM,
-			},
-		}
+	key := &kmspb.CryptoKey{
+		Purpose: kmspb.CryptoKey_ASYMMETRIC_SIGN,
+		VersionTemplate: &kmspb.CryptoKeyVersionTemplate{
+			Algorithm:       algorithm,
+			ProtectionLevel: kmspb.ProtectionLevel_HSM,
+		},
 	}
 	createdKey, err := hsm.Gclient.CreateCryptoKey(hsm.Ctx, &kmspb.CreateCryptoKeyRequest{
 		Parent:      parent,
@@ -87,8 +165,56 @@ func (hsm *HsmClient) CreateKeyPair(projectID, locationID, keyRingID, keyID, met
 		CryptoKey:   key,
 	})
 	if err != nil {
-		log.Error("Failed to create ECDSA key: %v", err)
+		log.Error("create kms key fail", "method", method, "err", err)
 		return "", err
 	}
 	return createdKey.Name, nil
 }
+
+// ListKeyVersions 列出密钥（projects/*/locations/*/keyRings/*/cryptoKeys/*）的所有版本
+func (hsm *HsmClient) ListKeyVersions(keyName string) ([]*kmspb.CryptoKeyVersion, error) {
+	it := hsm.Gclient.ListCryptoKeyVersions(hsm.Ctx, &kmspb.ListCryptoKeyVersionsRequest{Parent: keyName})
+	var versions []*kmspb.CryptoKeyVersion
+	for {
+		version, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return versions, nil
+		}
+		if err != nil {
+			log.Error("list kms key versions fail", "key", keyName, "err", err)
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
+
+// PublicKey 返回密钥版本的公钥：secp256k1 为 65 字节非压缩公钥，ed25519 为 32 字节公钥
+func (hsm *HsmClient) PublicKey(keyVersionName string) ([]byte, error) {
+	return hsm.publicKey(hsm.Ctx, keyVersionName)
+}
+
+// Encrypt 使用 KMS 对称密钥加密数据，用于包装 key store 的数据加密密钥
+func (hsm *HsmClient) Encrypt(keyName string, plaintext []byte) ([]byte, error) {
+	resp, err := hsm.Gclient.Encrypt(hsm.Ctx, &kmspb.EncryptRequest{
+		Name:      keyName,
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		log.Error("kms encrypt fail", "err", err)
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+// Decrypt 使用 KMS 对称密钥解密 Encrypt 的输出
+func (hsm *HsmClient) Decrypt(keyName string, ciphertext []byte) ([]byte, error) {
+	resp, err := hsm.Gclient.Decrypt(hsm.Ctx, &kmspb.DecryptRequest{
+		Name:       keyName,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		log.Error("kms decrypt fail", "err", err)
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
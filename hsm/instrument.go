@@ -0,0 +1,59 @@
+package hsm
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/0xshin-chan/wallet-sign/metrics"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+// instrumented 记录每次后端调用的 span、耗时和错误
+type instrumented struct {
+	Backend
+}
+
+// Instrument 包装后端，每次调用产生一个 hsm.<操作> span，耗时和错误计入 KMS 指标
+func Instrument(backend Backend) Backend {
+	return &instrumented{Backend: backend}
+}
+
+// observe 开始一次后端调用，返回的函数在调用结束时传入错误
+func (b *instrumented) observe(ctx context.Context, operation string) (context.Context, func(err error)) {
+	ctx, span := tracing.Start(ctx, "hsm."+operation, attribute.String("hsm.backend", b.Name()))
+	start := time.Now()
+	return ctx, func(err error) {
+		metrics.ObserveKmsCall(b.Name(), operation, time.Since(start), err)
+		tracing.End(span, err)
+	}
+}
+
+func (b *instrumented) CreateKey(ctx context.Context, curve string) (string, error) {
+	ctx, done := b.observe(ctx, "create_key")
+	keyName, err := b.Backend.CreateKey(ctx, curve)
+	done(err)
+	return keyName, err
+}
+
+func (b *instrumented) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	ctx, done := b.observe(ctx, "public_key")
+	pubKey, err := b.Backend.PublicKey(ctx, keyName)
+	done(err)
+	return pubKey, err
+}
+
+func (b *instrumented) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	ctx, done := b.observe(ctx, "sign")
+	signature, err := b.Backend.SignDigest(ctx, keyName, digest)
+	done(err)
+	return signature, err
+}
+
+func (b *instrumented) ListKeys(ctx context.Context) ([]string, error) {
+	ctx, done := b.observe(ctx, "list_keys")
+	keys, err := b.Backend.ListKeys(ctx)
+	done(err)
+	return keys, err
+}
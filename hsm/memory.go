@@ -0,0 +1,120 @@
+package hsm
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// MemoryBackend 私钥保存在进程内存中的假后端，行为与 GcpBackend 一致（secp256k1 签名为 DER 编码），
+// 只用于测试和本地开发，进程退出后密钥丢失
+type MemoryBackend struct {
+	mu   sync.RWMutex
+	keys map[string]interface{}
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{keys: make(map[string]interface{})}
+}
+
+func (b *MemoryBackend) Name() string {
+	return BackendMemory
+}
+
+// SignatureFormat 与 Cloud KMS 一样返回 DER
+func (b *MemoryBackend) SignatureFormat() string {
+	return SignatureDER
+}
+
+func (b *MemoryBackend) CreateKey(ctx context.Context, curve string) (string, error) {
+	var key interface{}
+	switch curve {
+	case ssm.CurveSecp256k1:
+		priKey, err := btcec.NewPrivateKey()
+		if err != nil {
+			return "", err
+		}
+		key = priKey
+	case ssm.CurveEd25519:
+		_, priKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", err
+		}
+		key = priKey
+	default:
+		return "", fmt.Errorf("unsupported hsm curve %q", curve)
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	keyName := "memory/" + curve + "/" + hex.EncodeToString(id[:])
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.keys[keyName] = key
+	return keyName, nil
+}
+
+func (b *MemoryBackend) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	key, err := b.key(keyName)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *btcec.PrivateKey:
+		return k.PubKey().SerializeUncompressed(), nil
+	case ed25519.PrivateKey:
+		return k.Public().(ed25519.PublicKey), nil
+	}
+	return nil, errors.New("unknown key type")
+}
+
+func (b *MemoryBackend) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	key, err := b.key(keyName)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *btcec.PrivateKey:
+		if len(digest) != 32 {
+			return nil, errors.New("digest must be 32 bytes")
+		}
+		return ecdsa.Sign(k, digest).Serialize(), nil
+	case ed25519.PrivateKey:
+		return ed25519.Sign(k, digest), nil
+	}
+	return nil, errors.New("unknown key type")
+}
+
+func (b *MemoryBackend) ListKeys(ctx context.Context) ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	names := make([]string, 0, len(b.keys))
+	for name := range b.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (b *MemoryBackend) key(keyName string) (interface{}, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	key, ok := b.keys[keyName]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
@@ -0,0 +1,266 @@
+//go:build cgo
+
+package hsm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/miekg/pkcs11"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// PKCS#11 v3.0 中 Ed25519 相关的常量，miekg/pkcs11 尚未定义
+const (
+	ckkEcEdwards            = 0x00000040
+	ckmEcEdwardsKeyPairGen  = 0x00001055
+	ckmEddsa                = 0x00001057
+	pkcs11KeyLabelPrefix    = "wallet-"
+	pkcs11MaxObjectsPerFind = 64
+)
+
+var (
+	// secp256k1 的 OID 1.3.132.0.10 和 Ed25519 的 OID 1.3.101.112，作为 CKA_EC_PARAMS
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidEd25519   = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// Pkcs11Backend 通过 PKCS#11 使用 HSM（Thales、Utimaco、SoftHSMv2 等）。密钥在 token 内生成且不可导出，
+// keyName 是密钥对的 CKA_LABEL。PKCS#11 会话不能并发使用，所有操作串行执行
+type Pkcs11Backend struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+
+	mu sync.Mutex
+}
+
+var _ Backend = (*Pkcs11Backend)(nil)
+
+// NewPkcs11Backend 加载 PKCS#11 库，打开 tokenLabel 对应 token 的读写会话并以普通用户登录
+func NewPkcs11Backend(library string, tokenLabel string, pin string) (*Pkcs11Backend, error) {
+	ctx := pkcs11.New(library)
+	if ctx == nil {
+		return nil, fmt.Errorf("load pkcs11 library %s fail", library)
+	}
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("initialize pkcs11 library: %w", err)
+	}
+	slot, err := findSlot(ctx, tokenLabel)
+	if err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, err
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, fmt.Errorf("open pkcs11 session: %w", err)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		ctx.CloseSession(session)
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, fmt.Errorf("pkcs11 login: %w", err)
+	}
+	return &Pkcs11Backend{ctx: ctx, session: session}, nil
+}
+
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("list pkcs11 slots: %w", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if strings.TrimSpace(info.Label) == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11 token %q not found", tokenLabel)
+}
+
+func (b *Pkcs11Backend) Name() string {
+	return BackendPkcs11
+}
+
+// SignatureFormat CKM_ECDSA 按 PKCS#11 规范返回定长的 r || s
+func (b *Pkcs11Backend) SignatureFormat() string {
+	return SignatureRaw
+}
+
+// Close 退出登录并释放库
+func (b *Pkcs11Backend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ctx.Logout(b.session)
+	b.ctx.CloseSession(b.session)
+	err := b.ctx.Finalize()
+	b.ctx.Destroy()
+	return err
+}
+
+func (b *Pkcs11Backend) CreateKey(ctx context.Context, curve string) (string, error) {
+	var (
+		mechanism uint
+		keyType   uint
+		oid       asn1.ObjectIdentifier
+	)
+	switch curve {
+	case ssm.CurveSecp256k1:
+		mechanism, keyType, oid = pkcs11.CKM_EC_KEY_PAIR_GEN, pkcs11.CKK_EC, oidSecp256k1
+	case ssm.CurveEd25519:
+		mechanism, keyType, oid = ckmEcEdwardsKeyPairGen, ckkEcEdwards, oidEd25519
+	default:
+		return "", fmt.Errorf("unsupported hsm curve %q", curve)
+	}
+	params, err := asn1.Marshal(oid)
+	if err != nil {
+		return "", err
+	}
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	label := pkcs11KeyLabelPrefix + curve + "-" + hex.EncodeToString(id[:8])
+	public := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PUBLIC_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, params),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id[:]),
+	}
+	private := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, keyType),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, id[:]),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, _, err := b.ctx.GenerateKeyPair(b.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, public, private); err != nil {
+		log.Error("pkcs11 generate key pair fail", "curve", curve, "err", err)
+		return "", err
+	}
+	return label, nil
+}
+
+// PublicKey 读取 CKA_EC_POINT，规范要求它是 DER OCTET STRING，部分实现直接返回原始点，两种都接受
+func (b *Pkcs11Backend) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	handle, err := b.findKey(pkcs11.CKO_PUBLIC_KEY, keyName)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := b.ctx.GetAttributeValue(b.session, handle, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		return nil, fmt.Errorf("read pkcs11 public key: %w", err)
+	}
+	point := attrs[0].Value
+	var unwrapped []byte
+	if rest, err := asn1.Unmarshal(point, &unwrapped); err == nil && len(rest) == 0 {
+		point = unwrapped
+	}
+	return point, nil
+}
+
+// SignDigest secp256k1 使用 CKM_ECDSA，返回 64 字节 r || s；Ed25519 使用 CKM_EDDSA
+func (b *Pkcs11Backend) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	mechanism := uint(pkcs11.CKM_ECDSA)
+	if strings.HasPrefix(keyName, pkcs11KeyLabelPrefix+ssm.CurveEd25519+"-") {
+		mechanism = ckmEddsa
+	} else if len(digest) != 32 {
+		return nil, errors.New("digest must be 32 bytes")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	handle, err := b.findKey(pkcs11.CKO_PRIVATE_KEY, keyName)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.ctx.SignInit(b.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, handle); err != nil {
+		return nil, fmt.Errorf("pkcs11 sign init: %w", err)
+	}
+	signature, err := b.ctx.Sign(b.session, digest)
+	if err != nil {
+		log.Error("pkcs11 sign fail", "key", keyName, "err", err)
+		return nil, err
+	}
+	return signature, nil
+}
+
+// ListKeys 列出 token 中由本服务创建的私钥
+func (b *Pkcs11Backend) ListKeys(ctx context.Context) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	handles, err := b.find([]*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY)})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, handle := range handles {
+		attrs, err := b.ctx.GetAttributeValue(b.session, handle, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_LABEL, nil)})
+		if err != nil {
+			return nil, err
+		}
+		if label := string(attrs[0].Value); strings.HasPrefix(label, pkcs11KeyLabelPrefix) {
+			names = append(names, label)
+		}
+	}
+	return names, nil
+}
+
+func (b *Pkcs11Backend) findKey(class uint, label string) (pkcs11.ObjectHandle, error) {
+	handles, err := b.find([]*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(handles) == 0 {
+		return 0, ErrKeyNotFound
+	}
+	if len(handles) > 1 {
+		return 0, fmt.Errorf("multiple pkcs11 objects labelled %s", label)
+	}
+	return handles[0], nil
+}
+
+func (b *Pkcs11Backend) find(template []*pkcs11.Attribute) ([]pkcs11.ObjectHandle, error) {
+	if err := b.ctx.FindObjectsInit(b.session, template); err != nil {
+		return nil, fmt.Errorf("pkcs11 find objects: %w", err)
+	}
+	defer b.ctx.FindObjectsFinal(b.session)
+	var handles []pkcs11.ObjectHandle
+	for {
+		batch, _, err := b.ctx.FindObjects(b.session, pkcs11MaxObjectsPerFind)
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11 find objects: %w", err)
+		}
+		if len(batch) == 0 {
+			return handles, nil
+		}
+		handles = append(handles, batch...)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !cgo
+
+package hsm
+
+import (
+	"context"
+	"errors"
+)
+
+var errPkcs11Unavailable = errors.New("pkcs11 backend requires cgo")
+
+// Pkcs11Backend 未开启 cgo 时不可用
+type Pkcs11Backend struct{}
+
+var _ Backend = (*Pkcs11Backend)(nil)
+
+func NewPkcs11Backend(library string, tokenLabel string, pin string) (*Pkcs11Backend, error) {
+	return nil, errPkcs11Unavailable
+}
+
+func (b *Pkcs11Backend) Name() string            { return BackendPkcs11 }
+func (b *Pkcs11Backend) SignatureFormat() string { return SignatureRaw }
+func (b *Pkcs11Backend) Close() error            { return errPkcs11Unavailable }
+
+func (b *Pkcs11Backend) CreateKey(ctx context.Context, curve string) (string, error) {
+	return "", errPkcs11Unavailable
+}
+
+func (b *Pkcs11Backend) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	return nil, errPkcs11Unavailable
+}
+
+func (b *Pkcs11Backend) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	return nil, errPkcs11Unavailable
+}
+
+func (b *Pkcs11Backend) ListKeys(ctx context.Context) ([]string, error) {
+	return nil, errPkcs11Unavailable
+}
@@ -0,0 +1,167 @@
+package hsm
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+const (
+	// KeyIDPrefix HSM 密钥 id 的前缀，完整格式为 hsm:<后端名>:<keyName>
+	KeyIDPrefix = "hsm:"
+
+	DefaultTimeout = 30 * time.Second
+)
+
+// Signer 把签名转发给 Backend 的 ssm.Signer。CreateKeyPair 在后端中生成密钥，返回的“私钥”是密钥 id；
+// 不带 KeyIDPrefix 的普通私钥交给 fallback，开启 HSM 前的密钥仍然可以签名
+type Signer struct {
+	backend  Backend
+	curve    string
+	timeout  time.Duration
+	fallback ssm.Signer
+
+	mu      sync.Mutex
+	pubKeys map[string][]byte
+}
+
+var (
+	_ ssm.Signer            = (*Signer)(nil)
+	_ ssm.ContextSigner     = (*Signer)(nil)
+	_ ssm.SchnorrOnlySigner = (*Signer)(nil)
+)
+
+func NewSigner(backend Backend, curve string, fallback ssm.Signer) *Signer {
+	return &Signer{
+		backend:  backend,
+		curve:    curve,
+		timeout:  DefaultTimeout,
+		fallback: fallback,
+		pubKeys:  make(map[string][]byte),
+	}
+}
+
+// SchnorrOnly 新密钥在后端中生成并产生 ECDSA/Ed25519 签名，fallback 只处理开启 HSM 前的密钥，不影响新密钥
+func (s *Signer) SchnorrOnly() bool {
+	return false
+}
+
+func (s *Signer) CreateKeyPair() (string, string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	keyName, err := s.backend.CreateKey(ctx, s.curve)
+	if err != nil {
+		log.Error("create hsm key fail", "backend", s.backend.Name(), "err", err)
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	pubKey, err := s.publicKey(ctx, keyName)
+	if err != nil {
+		log.Error("get hsm public key fail", "key", keyName, "err", err)
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	keyID := KeyIDPrefix + s.backend.Name() + ":" + keyName
+	if s.curve != ssm.CurveSecp256k1 {
+		return keyID, hex.EncodeToString(pubKey), hex.EncodeToString(pubKey), nil
+	}
+	parsed, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	return keyID, hex.EncodeToString(parsed.SerializeUncompressed()), hex.EncodeToString(parsed.SerializeCompressed()), nil
+}
+
+// SignMessage secp256k1 的 txMsg 为 32 字节哈希，ed25519 为待签名消息
+func (s *Signer) SignMessage(priKey string, txMsg string) (string, error) {
+	return s.SignMessageContext(context.Background(), priKey, txMsg)
+}
+
+// SignMessageContext 后端调用使用 ctx，超时不超过 DefaultTimeout
+func (s *Signer) SignMessageContext(ctx context.Context, priKey string, txMsg string) (string, error) {
+	if !strings.HasPrefix(priKey, KeyIDPrefix) {
+		return ssm.SignMessageContext(ctx, s.fallback, priKey, txMsg)
+	}
+	keyName, err := s.keyName(priKey)
+	if err != nil {
+		return ssm.EmptyHexString, err
+	}
+	var msg []byte
+	if s.curve == ssm.CurveSecp256k1 {
+		hash := common.HexToHash(txMsg)
+		msg = hash[:]
+	} else if msg, err = hex.DecodeString(txMsg); err != nil {
+		log.Error("decode tx message fail", "err", err)
+		return ssm.EmptyHexString, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	signature, err := s.backend.SignDigest(ctx, keyName, msg)
+	if err != nil {
+		log.Error("hsm sign fail", "backend", s.backend.Name(), "key", keyName, "err", err)
+		return ssm.EmptyHexString, err
+	}
+	// r || s 或 DER 转成与本地签名器相同的 r || s || v，可以直接用于以太坊交易
+	if s.curve == ssm.CurveSecp256k1 {
+		r, sv, err := parseRawSignature(s.backend.SignatureFormat(), signature)
+		if err != nil {
+			log.Error("parse hsm signature fail", "key", keyName, "err", err)
+			return ssm.EmptyHexString, err
+		}
+		pubKey, err := s.publicKey(ctx, keyName)
+		if err != nil {
+			log.Error("get hsm public key fail", "key", keyName, "err", err)
+			return ssm.EmptyHexString, err
+		}
+		if signature, err = recoverableSignature(msg, r, sv, pubKey); err != nil {
+			log.Error("convert hsm signature fail", "key", keyName, "err", err)
+			return ssm.EmptyHexString, err
+		}
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+func (s *Signer) VerifySignature(pubKey string, msgHash string, signature string) (bool, error) {
+	return s.fallback.VerifySignature(pubKey, msgHash, signature)
+}
+
+// publicKey 公钥不会变化，第一次从后端读取后缓存，避免每次签名都多一次 KMS 调用
+func (s *Signer) publicKey(ctx context.Context, keyName string) ([]byte, error) {
+	s.mu.Lock()
+	pubKey, ok := s.pubKeys[keyName]
+	s.mu.Unlock()
+	if ok {
+		return pubKey, nil
+	}
+	pubKey, err := s.backend.PublicKey(ctx, keyName)
+	if err != nil {
+		return nil, err
+	}
+	if s.curve == ssm.CurveSecp256k1 {
+		parsed, err := btcec.ParsePubKey(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKey = parsed.SerializeUncompressed()
+	}
+	s.mu.Lock()
+	s.pubKeys[keyName] = pubKey
+	s.mu.Unlock()
+	return pubKey, nil
+}
+
+// keyName 去掉密钥 id 的前缀，并确认密钥属于当前后端
+func (s *Signer) keyName(keyID string) (string, error) {
+	backend, keyName, ok := strings.Cut(strings.TrimPrefix(keyID, KeyIDPrefix), ":")
+	if !ok || backend != s.backend.Name() {
+		return "", fmt.Errorf("key %s does not belong to hsm backend %s", keyID, s.backend.Name())
+	}
+	return keyName, nil
+}
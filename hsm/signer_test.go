@@ -0,0 +1,123 @@
+package hsm
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+func TestSignerRoutesToBackend(t *testing.T) {
+	backend := NewMemoryBackend()
+	cases := []struct {
+		curve    string
+		fallback ssm.Signer
+		msg      string
+	}{
+		{ssm.CurveSecp256k1, &ssm.ECDSASigner{}, hex.EncodeToString(crypto.Keccak256([]byte("tx")))},
+		{ssm.CurveEd25519, &ssm.EdDSASigner{}, hex.EncodeToString([]byte("solana message"))},
+	}
+	for _, c := range cases {
+		signer := NewSigner(backend, c.curve, c.fallback)
+		keyID, pubKey, _, err := signer.CreateKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(keyID, KeyIDPrefix+BackendMemory+":") {
+			t.Fatalf("unexpected key id %s", keyID)
+		}
+		sig, err := signer.SignMessage(keyID, c.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := signer.VerifySignature(pubKey, c.msg, sig); err != nil || !ok {
+			t.Fatalf("%s: verify = %v, err %v", c.curve, ok, err)
+		}
+
+		// 不带前缀的本地私钥仍走 fallback
+		localKey, localPub, _, _ := c.fallback.CreateKeyPair()
+		sig, err = signer.SignMessage(localKey, c.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := signer.VerifySignature(localPub, c.msg, sig); !ok {
+			t.Fatalf("%s: fallback signature does not verify", c.curve)
+		}
+	}
+
+	keys, err := backend.ListKeys(context.Background())
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("list keys = %v, err %v", keys, err)
+	}
+	other := NewSigner(NewMemoryBackend(), ssm.CurveSecp256k1, &ssm.ECDSASigner{})
+	if _, err := other.SignMessage(KeyIDPrefix+"gcp:projects/p", cases[0].msg); err == nil {
+		t.Fatal("expected a key of another backend to be rejected")
+	}
+}
+
+func TestRecoverableSignature(t *testing.T) {
+	priKey, _ := crypto.GenerateKey()
+	pubKey := crypto.FromECDSAPub(&priKey.PublicKey)
+	hash := crypto.Keccak256([]byte("hsm"))
+	want, _ := crypto.Sign(hash, priKey)
+
+	r := new(big.Int).SetBytes(want[:32])
+	s := new(big.Int).SetBytes(want[32:64])
+	// HSM 可能返回 high-S，转换后应与 crypto.Sign 的结果一致
+	for _, candidate := range []*big.Int{s, new(big.Int).Sub(secp256k1N, s)} {
+		got, err := recoverableSignature(hash, r, candidate, pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != hex.EncodeToString(want) {
+			t.Fatalf("got %x, want %x", got, want)
+		}
+	}
+	other, _ := crypto.GenerateKey()
+	if _, err := recoverableSignature(hash, r, s, crypto.FromECDSAPub(&other.PublicKey)); err == nil {
+		t.Fatal("expected a signature of another key to be rejected")
+	}
+}
+
+func TestParseRawSignature(t *testing.T) {
+	priKey, _ := btcec.NewPrivateKey()
+	hash := crypto.Keccak256([]byte("hsm"))
+	der := ecdsa.Sign(priKey, hash).Serialize()
+	r, s, err := parseRawSignature(SignatureDER, der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	s.FillBytes(raw[32:])
+	if gotR, gotS, err := parseRawSignature(SignatureRaw, raw); err != nil || gotR.Cmp(r) != 0 || gotS.Cmp(s) != 0 {
+		t.Fatalf("raw = %v %v, err %v", gotR, gotS, err)
+	}
+	// 按后端声明的编码解析，不按长度猜测
+	if _, _, err := parseRawSignature(SignatureRaw, der); err == nil {
+		t.Fatal("expected a der signature to be rejected as raw")
+	}
+	if _, _, err := parseRawSignature(SignatureDER, raw); err == nil {
+		t.Fatal("expected a raw signature to be rejected as der")
+	}
+	if _, _, err := parseRawSignature("", raw); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+}
+
+func TestCreateKeyPairRejectsUnsupportedAlgorithm(t *testing.T) {
+	client := &HsmClient{Ctx: context.Background()}
+	for _, method := range []string{"rsa", "", "ecdsa-p256"} {
+		if _, err := client.CreateKeyPair("p", "global", "ring", "key", method); !errors.Is(err, ErrUnsupportedAlgorithm) {
+			t.Fatalf("%q: err = %v", method, err)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package hsm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+const (
+	BackendVault = "vault"
+
+	// DefaultVaultSecp256k1KeyType Transit 本身不支持 secp256k1，需要安装提供该类型的插件
+	DefaultVaultSecp256k1KeyType = "ecdsa-secp256k1"
+	vaultKeyPrefix               = "wallet-"
+)
+
+// VaultAuth Vault 认证方式：Token 非空时直接使用；否则用 AppRole 的 RoleID/SecretID 登录
+type VaultAuth struct {
+	Token    string
+	RoleID   string
+	SecretID string
+}
+
+// VaultBackend 基于 HashiCorp Vault Transit 引擎的签名后端。
+//
+// keyName 格式为 <transit 密钥名>@<版本>。Transit 轮换密钥会改变公钥也就改变地址，
+// 所以创建时固定当时的最新版本，之后签名始终带 key_version，轮换不影响已有地址
+type VaultBackend struct {
+	address          string
+	mount            string
+	auth             VaultAuth
+	secp256k1KeyType string
+	client           *http.Client
+
+	mu        sync.Mutex
+	token     string
+	renewable bool
+	lease     time.Duration
+	expiresAt time.Time
+}
+
+var _ Backend = (*VaultBackend)(nil)
+
+// NewVaultBackend mount 为 Transit 引擎的挂载路径（默认 transit）。创建时完成登录并读取 token 的租期
+func NewVaultBackend(address string, mount string, auth VaultAuth, secp256k1KeyType string) (*VaultBackend, error) {
+	if address == "" {
+		return nil, errors.New("vault address is empty")
+	}
+	if auth.Token == "" && (auth.RoleID == "" || auth.SecretID == "") {
+		return nil, errors.New("vault token or approle credentials required")
+	}
+	if mount == "" {
+		mount = "transit"
+	}
+	if secp256k1KeyType == "" {
+		secp256k1KeyType = DefaultVaultSecp256k1KeyType
+	}
+	b := &VaultBackend{
+		address:          strings.TrimRight(address, "/"),
+		mount:            strings.Trim(mount, "/"),
+		auth:             auth,
+		secp256k1KeyType: secp256k1KeyType,
+		client:           &http.Client{Timeout: DefaultTimeout},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.login(ctx); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *VaultBackend) Name() string {
+	return BackendVault
+}
+
+// SignatureFormat 签名请求指定 marshaling_algorithm=asn1，ECDSA 签名为 DER
+func (b *VaultBackend) SignatureFormat() string {
+	return SignatureDER
+}
+
+func (b *VaultBackend) CreateKey(ctx context.Context, curve string) (string, error) {
+	var keyType string
+	switch curve {
+	case ssm.CurveSecp256k1:
+		keyType = b.secp256k1KeyType
+	case ssm.CurveEd25519:
+		keyType = "ed25519"
+	default:
+		return "", fmt.Errorf("unsupported hsm curve %q", curve)
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	name := vaultKeyPrefix + curve + "-" + hex.EncodeToString(id[:])
+	body := map[string]interface{}{"type": keyType, "exportable": false}
+	if err := b.do(ctx, http.MethodPost, b.mount+"/keys/"+name, body, nil); err != nil {
+		log.Error("vault create key fail", "type", keyType, "err", err)
+		return "", err
+	}
+	key, err := b.readKey(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return name + "@" + strconv.Itoa(key.LatestVersion), nil
+}
+
+func (b *VaultBackend) PublicKey(ctx context.Context, keyName string) ([]byte, error) {
+	name, version, err := splitVaultKeyName(keyName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := b.readKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := key.Keys[strconv.Itoa(version)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	if key.Type == "ed25519" {
+		return base64.StdEncoding.DecodeString(v.PublicKey)
+	}
+	return parsePublicKeyPem(v.PublicKey)
+}
+
+// SignDigest ECDSA 密钥对摘要签名（prehashed），返回 DER；Ed25519 对原始消息签名
+func (b *VaultBackend) SignDigest(ctx context.Context, keyName string, digest []byte) ([]byte, error) {
+	name, version, err := splitVaultKeyName(keyName)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{
+		"input":       base64.StdEncoding.EncodeToString(digest),
+		"key_version": version,
+	}
+	if !strings.HasPrefix(name, vaultKeyPrefix+ssm.CurveEd25519+"-") {
+		if len(digest) != 32 {
+			return nil, errors.New("digest must be 32 bytes")
+		}
+		body["prehashed"] = true
+		body["hash_algorithm"] = "sha2-256"
+		body["marshaling_algorithm"] = "asn1"
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := b.do(ctx, http.MethodPost, b.mount+"/sign/"+name, body, &resp); err != nil {
+		log.Error("vault sign fail", "key", keyName, "err", err)
+		return nil, err
+	}
+	// 签名格式为 vault:v<版本>:<base64>
+	i := strings.LastIndex(resp.Signature, ":")
+	if i < 0 {
+		return nil, errors.New("invalid vault signature")
+	}
+	return base64.StdEncoding.DecodeString(resp.Signature[i+1:])
+}
+
+// ListKeys 列出由本服务创建的 Transit 密钥的所有版本
+func (b *VaultBackend) ListKeys(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Keys []string `json:"keys"`
+	}
+	if err := b.do(ctx, "LIST", b.mount+"/keys", nil, &resp); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, name := range resp.Keys {
+		if !strings.HasPrefix(name, vaultKeyPrefix) {
+			continue
+		}
+		key, err := b.readKey(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		versions := make([]int, 0, len(key.Keys))
+		for v := range key.Keys {
+			if n, err := strconv.Atoi(v); err == nil {
+				versions = append(versions, n)
+			}
+		}
+		sort.Ints(versions)
+		for _, v := range versions {
+			names = append(names, name+"@"+strconv.Itoa(v))
+		}
+	}
+	return names, nil
+}
+
+type vaultKey struct {
+	Type          string `json:"type"`
+	LatestVersion int    `json:"latest_version"`
+	Keys          map[string]struct {
+		PublicKey string `json:"public_key"`
+	} `json:"keys"`
+}
+
+func (b *VaultBackend) readKey(ctx context.Context, name string) (*vaultKey, error) {
+	var key vaultKey
+	if err := b.do(ctx, http.MethodGet, b.mount+"/keys/"+name, nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func splitVaultKeyName(keyName string) (string, int, error) {
+	name, v, ok := strings.Cut(keyName, "@")
+	version, err := strconv.Atoi(v)
+	if !ok || err != nil || version <= 0 {
+		return "", 0, fmt.Errorf("vault key %s is not pinned to a version", keyName)
+	}
+	return name, version, nil
+}
+
+// do 发送请求并把响应的 data 字段解析到 out。请求前按需续期 token，token 失效（403）时 AppRole 模式重新登录并重试一次
+func (b *VaultBackend) do(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	token, err := b.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	status, data, err := b.send(ctx, method, path, token, body)
+	if err == nil && status == http.StatusForbidden && b.auth.Token == "" {
+		b.mu.Lock()
+		if b.token == token {
+			err = b.login(ctx)
+		}
+		token = b.token
+		b.mu.Unlock()
+		if err == nil {
+			status, data, err = b.send(ctx, method, path, token, body)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return decodeVaultResponse(status, data, out)
+}
+
+func (b *VaultBackend) send(ctx context.Context, method string, path string, token string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.address+"/v1/"+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, data, nil
+}
+
+func decodeVaultResponse(status int, data []byte, out interface{}) error {
+	if status == http.StatusNotFound {
+		return ErrKeyNotFound
+	}
+	if status < 200 || status >= 300 {
+		var resp struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(data, &resp)
+		return fmt.Errorf("vault returned %d: %s", status, strings.Join(resp.Errors, "; "))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("decode vault response: %w", err)
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+type vaultAuthInfo struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// currentToken 租期剩余不足三分之一时续期，续期失败的 AppRole token 重新登录
+func (b *VaultBackend) currentToken(ctx context.Context) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.lease == 0 || time.Until(b.expiresAt) > b.lease/3 {
+		return b.token, nil
+	}
+	if b.renewable {
+		err := b.authRequest(ctx, "auth/token/renew-self", b.token, map[string]interface{}{})
+		if err == nil {
+			return b.token, nil
+		}
+		log.Warn("vault token renew fail", "err", err)
+	}
+	if b.auth.Token != "" {
+		if time.Now().Before(b.expiresAt) {
+			return b.token, nil
+		}
+		return "", errors.New("vault token expired")
+	}
+	if err := b.login(ctx); err != nil {
+		return "", err
+	}
+	return b.token, nil
+}
+
+// login Token 模式查询 token 的租期，AppRole 模式登录获取新 token。调用方持有 b.mu
+func (b *VaultBackend) login(ctx context.Context) error {
+	if b.auth.Token != "" {
+		status, data, err := b.send(ctx, http.MethodGet, "auth/token/lookup-self", b.auth.Token, nil)
+		if err != nil {
+			return err
+		}
+		var info struct {
+			TTL       int64 `json:"ttl"`
+			Renewable bool  `json:"renewable"`
+		}
+		if err := decodeVaultResponse(status, data, &info); err != nil {
+			return fmt.Errorf("vault token lookup: %w", err)
+		}
+		b.setToken(vaultAuthInfo{ClientToken: b.auth.Token, LeaseDuration: info.TTL, Renewable: info.Renewable})
+		return nil
+	}
+	body := map[string]interface{}{"role_id": b.auth.RoleID, "secret_id": b.auth.SecretID}
+	if err := b.authRequest(ctx, "auth/approle/login", "", body); err != nil {
+		return fmt.Errorf("vault approle login: %w", err)
+	}
+	return nil
+}
+
+// authRequest 发送登录或续期请求，用响应中的 auth 字段更新 token
+func (b *VaultBackend) authRequest(ctx context.Context, path string, token string, body interface{}) error {
+	status, data, err := b.send(ctx, http.MethodPost, path, token, body)
+	if err != nil {
+		return err
+	}
+	if status < 200 || status >= 300 {
+		return decodeVaultResponse(status, data, nil)
+	}
+	var resp struct {
+		Auth *vaultAuthInfo `json:"auth"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return errors.New("vault response has no auth")
+	}
+	b.setToken(*resp.Auth)
+	return nil
+}
+
+// setToken lease 为 0 表示 token 不过期（例如 root token）
+func (b *VaultBackend) setToken(info vaultAuthInfo) {
+	b.token = info.ClientToken
+	b.renewable = info.Renewable
+	b.lease = time.Duration(info.LeaseDuration) * time.Second
+	b.expiresAt = time.Now().Add(b.lease)
+}
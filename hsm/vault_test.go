@@ -0,0 +1,224 @@
+package hsm
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// fakeVault 实现测试用到的 Transit 和 AppRole 接口
+type fakeVault struct {
+	mu       sync.Mutex
+	tokens   map[string]bool
+	keys     map[string]*fakeVaultKey
+	logins   int
+	renewals int
+}
+
+type fakeVaultKey struct {
+	keyType  string
+	versions []interface{}
+}
+
+func newFakeVault() *fakeVault {
+	return &fakeVault{tokens: make(map[string]bool), keys: make(map[string]*fakeVaultKey)}
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+
+	if path == "auth/approle/login" {
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			writeVault(w, http.StatusBadRequest, nil)
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("s.%d", f.logins)
+		f.tokens[token] = true
+		writeVault(w, http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": token, "lease_duration": 1, "renewable": true},
+		})
+		return
+	}
+	token := r.Header.Get("X-Vault-Token")
+	if !f.tokens[token] {
+		writeVault(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+	switch {
+	case path == "auth/token/renew-self":
+		f.renewals++
+		writeVault(w, http.StatusOK, map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": token, "lease_duration": 1, "renewable": true},
+		})
+	case path == "transit/keys" && r.Method == "LIST":
+		var names []string
+		for name := range f.keys {
+			names = append(names, name)
+		}
+		writeVault(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": names}})
+	case strings.HasPrefix(path, "transit/keys/") && r.Method == http.MethodPost:
+		name := strings.TrimPrefix(path, "transit/keys/")
+		key := &fakeVaultKey{keyType: body["type"].(string)}
+		f.keys[name] = key
+		key.rotate()
+		writeVault(w, http.StatusNoContent, nil)
+	case strings.HasPrefix(path, "transit/keys/"):
+		key, ok := f.keys[strings.TrimPrefix(path, "transit/keys/")]
+		if !ok {
+			writeVault(w, http.StatusNotFound, nil)
+			return
+		}
+		versions := make(map[string]interface{})
+		for i, k := range key.versions {
+			versions[fmt.Sprint(i+1)] = map[string]interface{}{"public_key": publicKeyString(k)}
+		}
+		writeVault(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"type": key.keyType, "latest_version": len(key.versions), "keys": versions,
+		}})
+	case strings.HasPrefix(path, "transit/sign/"):
+		key, ok := f.keys[strings.TrimPrefix(path, "transit/sign/")]
+		if !ok {
+			writeVault(w, http.StatusNotFound, nil)
+			return
+		}
+		version := int(body["key_version"].(float64))
+		input, _ := base64.StdEncoding.DecodeString(body["input"].(string))
+		var sig []byte
+		switch k := key.versions[version-1].(type) {
+		case *btcec.PrivateKey:
+			sig = ecdsa.Sign(k, input).Serialize()
+		case ed25519.PrivateKey:
+			sig = ed25519.Sign(k, input)
+		}
+		writeVault(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"signature": fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(sig)),
+		}})
+	default:
+		writeVault(w, http.StatusNotFound, nil)
+	}
+}
+
+func (k *fakeVaultKey) rotate() {
+	if k.keyType == "ed25519" {
+		_, priKey, _ := ed25519.GenerateKey(rand.Reader)
+		k.versions = append(k.versions, priKey)
+		return
+	}
+	priKey, _ := btcec.NewPrivateKey()
+	k.versions = append(k.versions, priKey)
+}
+
+func publicKeyString(key interface{}) string {
+	if k, ok := key.(ed25519.PrivateKey); ok {
+		return base64.StdEncoding.EncodeToString(k.Public().(ed25519.PublicKey))
+	}
+	spki := struct {
+		Algorithm []asn1.ObjectIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: []asn1.ObjectIdentifier{{1, 2, 840, 10045, 2, 1}, {1, 3, 132, 0, 10}},
+	}
+	point := key.(*btcec.PrivateKey).PubKey().SerializeUncompressed()
+	spki.PublicKey = asn1.BitString{Bytes: point, BitLength: len(point) * 8}
+	der, _ := asn1.Marshal(spki)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func writeVault(w http.ResponseWriter, status int, body interface{}) {
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func TestVaultBackend(t *testing.T) {
+	vault := newFakeVault()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	if _, err := NewVaultBackend(server.URL, "", VaultAuth{RoleID: "role", SecretID: "wrong"}, ""); err == nil {
+		t.Fatal("expected approle login with a wrong secret to fail")
+	}
+	backend, err := NewVaultBackend(server.URL, "", VaultAuth{RoleID: "role", SecretID: "secret"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		curve    string
+		fallback ssm.Signer
+		msg      string
+	}{
+		{ssm.CurveSecp256k1, &ssm.ECDSASigner{}, hex.EncodeToString(crypto.Keccak256([]byte("tx")))},
+		{ssm.CurveEd25519, &ssm.EdDSASigner{}, hex.EncodeToString([]byte("solana message"))},
+	}
+	for _, c := range cases {
+		signer := NewSigner(backend, c.curve, c.fallback)
+		keyID, pubKey, _, err := signer.CreateKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(keyID, "@1") {
+			t.Fatalf("key id %s is not pinned to version 1", keyID)
+		}
+		// 轮换后已有密钥仍使用创建时的版本签名
+		vault.mu.Lock()
+		for _, key := range vault.keys {
+			key.rotate()
+		}
+		vault.mu.Unlock()
+		// 租期即将到期，签名前应先续期
+		backend.mu.Lock()
+		backend.expiresAt = time.Now()
+		backend.mu.Unlock()
+
+		sig, err := signer.SignMessage(keyID, c.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := signer.VerifySignature(pubKey, c.msg, sig); err != nil || !ok {
+			t.Fatalf("%s: verify = %v, err %v", c.curve, ok, err)
+		}
+	}
+
+	// 旧 token 被吊销后重新登录
+	vault.mu.Lock()
+	vault.tokens = make(map[string]bool)
+	vault.mu.Unlock()
+	keys, err := backend.ListKeys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// secp256k1 密钥轮换两次，ed25519 密钥轮换一次
+	if len(keys) != 5 {
+		t.Fatalf("list keys = %v", keys)
+	}
+	if vault.renewals == 0 || vault.logins < 2 {
+		t.Fatalf("renewals %d, logins %d", vault.renewals, vault.logins)
+	}
+	if _, err := backend.SignDigest(context.Background(), "wallet-ed25519-00", []byte("msg")); err == nil {
+		t.Fatal("expected an unpinned key name to be rejected")
+	}
+}
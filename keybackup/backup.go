@@ -0,0 +1,300 @@
+// Package keybackup 负责外部私钥格式的解码，以及加密备份文件的生成和解析。
+//
+// 备份文件是一个 JSON 对象：
+//
+//	{
+//	  "version": 1,
+//	  "created_at": 1700000000,
+//	  "keys": [
+//	    {
+//	      "chain": "Ethereum",
+//	      "curve": "secp256k1",
+//	      "network": "mainnet",
+//	      "public_key": "04...",
+//	      "label": "hot-1",
+//	      "derivation_path": "m/44'/60'/0'/0/0",
+//	      "keystore": { ... }
+//	    }
+//	  ]
+//	}
+//
+// secp256k1 私钥的 keystore 字段是标准的 Ethereum keystore v3 JSON，可以直接导入 geth 等钱包。
+// ed25519 私钥没有通用的加密格式，keystore 字段使用以下格式：
+//
+//	{
+//	  "version": 1,
+//	  "curve": "ed25519",
+//	  "public_key": "<32 字节公钥 hex>",
+//	  "crypto": {
+//	    "cipher": "aes-256-gcm",
+//	    "ciphertext": "<hex>",
+//	    "nonce": "<12 字节 hex>",
+//	    "kdf": "scrypt",
+//	    "kdfparams": {"n": 262144, "r": 8, "p": 1, "dklen": 32, "salt": "<32 字节 hex>"}
+//	  }
+//	}
+//
+// 解密时用 scrypt(password, salt, n, r, p, dklen) 得到 AES-256 密钥，以公钥字节作为附加数据进行
+// AES-GCM 解密，明文是 32 字节的 ed25519 种子（RFC 8032 中的私钥）。
+package keybackup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+const (
+	backupVersion  = 1
+	ed25519Version = 1
+	ed25519Cipher  = "aes-256-gcm"
+	ed25519Kdf     = "scrypt"
+	scryptR        = 8
+	scryptDkLen    = 32
+	scryptSaltSize = 32
+)
+
+// 导出使用 keystore 的标准强度，测试中会调低
+var (
+	scryptN = keystore.StandardScryptN
+	scryptP = keystore.StandardScryptP
+)
+
+var ErrWrongPassword = errors.New("wrong backup password")
+
+// Entry 备份中的一个私钥及其元数据，PrivateKey 为原始字节（ed25519 为 32 字节种子）
+type Entry struct {
+	Chain          string
+	Curve          string
+	Network        string
+	PublicKey      string
+	Label          string
+	DerivationPath string
+	PrivateKey     []byte
+}
+
+type backupFile struct {
+	Version   int           `json:"version"`
+	CreatedAt int64         `json:"created_at"`
+	Keys      []backupEntry `json:"keys"`
+}
+
+type backupEntry struct {
+	Chain          string          `json:"chain"`
+	Curve          string          `json:"curve"`
+	Network        string          `json:"network,omitempty"`
+	PublicKey      string          `json:"public_key"`
+	Label          string          `json:"label,omitempty"`
+	DerivationPath string          `json:"derivation_path,omitempty"`
+	Keystore       json.RawMessage `json:"keystore"`
+}
+
+type ed25519Keystore struct {
+	Version   int           `json:"version"`
+	Curve     string        `json:"curve"`
+	PublicKey string        `json:"public_key"`
+	Crypto    ed25519Crypto `json:"crypto"`
+}
+
+type ed25519Crypto struct {
+	Cipher     string       `json:"cipher"`
+	CipherText string       `json:"ciphertext"`
+	Nonce      string       `json:"nonce"`
+	Kdf        string       `json:"kdf"`
+	KdfParams  scryptParams `json:"kdfparams"`
+}
+
+type scryptParams struct {
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	DkLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// Seal 用口令加密所有私钥并生成备份文件
+func Seal(entries []Entry, password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("backup password is empty")
+	}
+	file := backupFile{Version: backupVersion, CreatedAt: time.Now().Unix()}
+	for _, entry := range entries {
+		encrypted, err := EncryptKey(entry.Curve, entry.PrivateKey, password)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt key %s: %w", entry.PublicKey, err)
+		}
+		file.Keys = append(file.Keys, backupEntry{
+			Chain:          entry.Chain,
+			Curve:          entry.Curve,
+			Network:        entry.Network,
+			PublicKey:      entry.PublicKey,
+			Label:          entry.Label,
+			DerivationPath: entry.DerivationPath,
+			Keystore:       encrypted,
+		})
+	}
+	return json.MarshalIndent(file, "", "  ")
+}
+
+// Open 解析并解密备份文件
+func Open(data []byte, password string) ([]Entry, error) {
+	var file backupFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse backup: %w", err)
+	}
+	if file.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", file.Version)
+	}
+	entries := make([]Entry, 0, len(file.Keys))
+	for _, item := range file.Keys {
+		priKey, err := DecryptKey(item.Curve, item.Keystore, password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt key %s: %w", item.PublicKey, err)
+		}
+		entries = append(entries, Entry{
+			Chain:          item.Chain,
+			Curve:          item.Curve,
+			Network:        item.Network,
+			PublicKey:      item.PublicKey,
+			Label:          item.Label,
+			DerivationPath: item.DerivationPath,
+			PrivateKey:     priKey,
+		})
+	}
+	return entries, nil
+}
+
+// EncryptKey 加密单个私钥：secp256k1 输出 keystore v3，ed25519 输出包内文档描述的格式
+func EncryptKey(curve string, priKey []byte, password string) (json.RawMessage, error) {
+	switch curve {
+	case ssm.CurveSecp256k1:
+		privateKey, err := crypto.ToECDSA(priKey)
+		if err != nil {
+			return nil, err
+		}
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return nil, err
+		}
+		key := &keystore.Key{
+			Id:         id,
+			Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+			PrivateKey: privateKey,
+		}
+		return keystore.EncryptKey(key, password, scryptN, scryptP)
+	case ssm.CurveEd25519:
+		if len(priKey) != ed25519.SeedSize && len(priKey) != ed25519.PrivateKeySize {
+			return nil, errors.New("invalid ed25519 private key")
+		}
+		privateKey := ed25519.NewKeyFromSeed(priKey[:ed25519.SeedSize])
+		publicKey := privateKey.Public().(ed25519.PublicKey)
+		salt := make([]byte, scryptSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		params := scryptParams{N: scryptN, R: scryptR, P: scryptP, DkLen: scryptDkLen, Salt: hex.EncodeToString(salt)}
+		aead, err := newScryptAead(password, params)
+		if err != nil {
+			return nil, err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+		return json.Marshal(ed25519Keystore{
+			Version:   ed25519Version,
+			Curve:     ssm.CurveEd25519,
+			PublicKey: hex.EncodeToString(publicKey),
+			Crypto: ed25519Crypto{
+				Cipher:     ed25519Cipher,
+				CipherText: hex.EncodeToString(aead.Seal(nil, nonce, privateKey.Seed(), publicKey)),
+				Nonce:      hex.EncodeToString(nonce),
+				Kdf:        ed25519Kdf,
+				KdfParams:  params,
+			},
+		})
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+// DecryptKey 解密 EncryptKey 的输出，返回原始私钥字节
+func DecryptKey(curve string, data []byte, password string) ([]byte, error) {
+	switch curve {
+	case ssm.CurveSecp256k1:
+		key, err := keystore.DecryptKey(data, password)
+		if err != nil {
+			if errors.Is(err, keystore.ErrDecrypt) {
+				return nil, ErrWrongPassword
+			}
+			return nil, err
+		}
+		return crypto.FromECDSA(key.PrivateKey), nil
+	case ssm.CurveEd25519:
+		var ks ed25519Keystore
+		if err := json.Unmarshal(data, &ks); err != nil {
+			return nil, err
+		}
+		if ks.Version != ed25519Version || ks.Crypto.Cipher != ed25519Cipher || ks.Crypto.Kdf != ed25519Kdf {
+			return nil, errors.New("unsupported ed25519 keystore")
+		}
+		publicKey, err := hex.DecodeString(ks.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		nonce, err := hex.DecodeString(ks.Crypto.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		ciphertext, err := hex.DecodeString(ks.Crypto.CipherText)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := newScryptAead(password, ks.Crypto.KdfParams)
+		if err != nil {
+			return nil, err
+		}
+		if len(nonce) != aead.NonceSize() {
+			return nil, errors.New("invalid nonce")
+		}
+		seed, err := aead.Open(nil, nonce, ciphertext, publicKey)
+		if err != nil {
+			return nil, ErrWrongPassword
+		}
+		return seed, nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", curve)
+	}
+}
+
+func newScryptAead(password string, params scryptParams) (cipher.AEAD, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if params.DkLen != scryptDkLen {
+		return nil, errors.New("unsupported scrypt key length")
+	}
+	key, err := scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.DkLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
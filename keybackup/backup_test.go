@@ -0,0 +1,77 @@
+package keybackup
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+func init() {
+	scryptN = keystore.LightScryptN
+	scryptP = keystore.LightScryptP
+}
+
+func TestSealOpen(t *testing.T) {
+	secpKey, _ := hex.DecodeString("646448df201c4cdc805a3271de8e5d951ac4cb83ccb88b8cbc31540d1cdd7fd0")
+	edKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{3}, ed25519.SeedSize))
+	entries := []Entry{
+		{Chain: "Ethereum", Curve: ssm.CurveSecp256k1, PublicKey: "04aa", Label: "hot", PrivateKey: secpKey},
+		{Chain: "Solana", Curve: ssm.CurveEd25519, PublicKey: "bb", DerivationPath: "m/44'/501'/0'/0'", PrivateKey: edKey},
+	}
+	data, err := Seal(entries, "backup password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(hex.EncodeToString(secpKey))) || bytes.Contains(data, []byte(hex.EncodeToString(edKey.Seed()))) {
+		t.Fatal("backup contains plaintext private key")
+	}
+	opened, err := Open(data, "backup password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 2 || !bytes.Equal(opened[0].PrivateKey, secpKey) || !bytes.Equal(opened[1].PrivateKey, edKey.Seed()) {
+		t.Fatalf("opened entries do not match: %+v", opened)
+	}
+	if opened[0].Label != "hot" || opened[1].DerivationPath != "m/44'/501'/0'/0'" {
+		t.Fatalf("metadata lost: %+v", opened)
+	}
+	if _, err := Open(data, "wrong"); err == nil {
+		t.Fatal("expected open with wrong password to fail")
+	}
+}
+
+func TestDecodePrivateKey(t *testing.T) {
+	want, _ := hex.DecodeString("0c28fca386c7a227600b2fe50b7cae11ec86d3bf1fbe471be89827e19d72aa1d")
+	priKey, err := DecodePrivateKey(FormatWif, "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", "")
+	if err != nil || !bytes.Equal(priKey, want) {
+		t.Fatalf("wif = %x, err %v", priKey, err)
+	}
+	priKey, err = DecodePrivateKey(FormatHex, "0x"+hex.EncodeToString(want), "")
+	if err != nil || !bytes.Equal(priKey, want) {
+		t.Fatalf("hex = %x, err %v", priKey, err)
+	}
+
+	edKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{5}, ed25519.SeedSize))
+	priKey, err = DecodePrivateKey(FormatBase58, base58.Encode(edKey), "")
+	if err != nil || !bytes.Equal(priKey, edKey) {
+		t.Fatalf("base58 = %x, err %v", priKey, err)
+	}
+	if _, _, _, err := new(ssm.ECDSASigner).ImportKeyPair(priKey); err == nil {
+		t.Fatal("expected ed25519 key to be rejected by the secp256k1 signer")
+	}
+
+	ks, err := EncryptKey(ssm.CurveSecp256k1, want, "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	priKey, err = DecodePrivateKey(FormatKeystore, string(ks), "pw")
+	if err != nil || !bytes.Equal(priKey, want) {
+		t.Fatalf("keystore = %x, err %v", priKey, err)
+	}
+}
@@ -0,0 +1,59 @@
+package keybackup
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	FormatHex      = "hex"
+	FormatWif      = "wif"
+	FormatBase58   = "base58"
+	FormatKeystore = "keystore"
+	// FormatBackup 本服务导出的加密备份文件
+	FormatBackup = "backup"
+)
+
+// DecodePrivateKey 把一个外部格式的私钥解码成原始字节，曲线由调用方用 ssm.KeyImporter 校验
+func DecodePrivateKey(format string, input string, password string) ([]byte, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("private key is empty")
+	}
+	switch format {
+	case FormatHex:
+		priKey, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex private key: %w", err)
+		}
+		return priKey, nil
+	case FormatWif:
+		wif, err := btcutil.DecodeWIF(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wif private key: %w", err)
+		}
+		return wif.PrivKey.Serialize(), nil
+	case FormatBase58:
+		// Solana 钱包导出的 64 字节私钥
+		priKey := base58.Decode(input)
+		if len(priKey) == 0 {
+			return nil, errors.New("invalid base58 private key")
+		}
+		return priKey, nil
+	case FormatKeystore:
+		key, err := keystore.DecryptKey([]byte(input), password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt keystore: %w", err)
+		}
+		return crypto.FromECDSA(key.PrivateKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported key format %q", format)
+	}
+}
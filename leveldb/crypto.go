@@ -0,0 +1,84 @@
+package leveldb
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+const (
+	dekSize = 32
+	// 加密值的格式版本，升级算法时递增
+	ciphertextVersion byte = 1
+)
+
+// ciphertextMagic 加密值的固定前缀，后跟一个字节的版本号
+var ciphertextMagic = []byte("WSK")
+
+var ErrDecrypt = errors.New("decrypt key store value fail")
+
+// valueCipher 用数据加密密钥（DEK）加解密 key store 中的敏感值，
+// 格式为 magic || version || nonce || AES-256-GCM 密文，LevelDB 的 key 作为附加数据参与认证，
+// 防止密文在不同条目之间被替换
+type valueCipher struct {
+	aead cipher.AEAD
+}
+
+func newValueCipher(dek []byte) (*valueCipher, error) {
+	if len(dek) != dekSize {
+		return nil, errors.New("invalid data encryption key size")
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &valueCipher{aead: aead}, nil
+}
+
+func (c *valueCipher) seal(key, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(ciphertextMagic)+1+len(nonce)+len(plaintext)+c.aead.Overhead())
+	out = append(out, ciphertextMagic...)
+	out = append(out, ciphertextVersion)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, plaintext, key), nil
+}
+
+func (c *valueCipher) open(key, value []byte) ([]byte, error) {
+	if !isCiphertext(value) {
+		return nil, ErrDecrypt
+	}
+	body := value[len(ciphertextMagic)+1:]
+	if len(body) < c.aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	plaintext, err := c.aead.Open(nil, body[:c.aead.NonceSize()], body[c.aead.NonceSize():], key)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// isCiphertext 判断值是否带有加密头，用于区分迁移前的明文数据
+func isCiphertext(value []byte) bool {
+	return len(value) > len(ciphertextMagic) &&
+		bytes.HasPrefix(value, ciphertextMagic) &&
+		value[len(ciphertextMagic)] == ciphertextVersion
+}
+
+func newDataEncryptionKey() ([]byte, error) {
+	dek := make([]byte, dekSize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	return dek, nil
+}
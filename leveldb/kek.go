@@ -0,0 +1,283 @@
+package leveldb
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+	"github.com/0xshin-chan/wallet-sign/hsm"
+)
+
+// 数据加密密钥（DEK）被密钥加密密钥（KEK）包装后存放在这个 key 下
+const dekRecordKey = "meta/dek"
+
+const (
+	EncryptionNone       = "none"
+	EncryptionPassphrase = "passphrase"
+	EncryptionFile       = "file"
+	EncryptionKms        = "kms"
+
+	KdfArgon2id = "argon2id"
+	KdfScrypt   = "scrypt"
+)
+
+// KeyWrapper 用 KEK 包装和解包 DEK
+type KeyWrapper interface {
+	Name() string
+	Wrap(dek []byte) ([]byte, error)
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+type dekRecord struct {
+	Version    int    `json:"version"`
+	Kek        string `json:"kek"`
+	WrappedDek string `json:"wrapped_dek"`
+}
+
+// NewKeyWrapper 根据配置创建 KEK，未开启加密时返回 nil
+func NewKeyWrapper(conf *config.Config) (KeyWrapper, error) {
+	ksConf := conf.KeyStore
+	switch ksConf.Encryption {
+	case "", EncryptionNone:
+		return nil, nil
+	case EncryptionPassphrase:
+		passphrase := os.Getenv(ksConf.PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("key store passphrase is empty, set %s", ksConf.PassphraseEnv)
+		}
+		return NewPassphraseKeyWrapper(passphrase, ksConf.Kdf)
+	case EncryptionFile:
+		return NewFileKeyWrapper(ksConf.KekFile)
+	case EncryptionKms:
+		hsmCli, err := hsm.NewHSMClient(context.Background(), conf.KeyPath, ksConf.KmsKeyName)
+		if err != nil {
+			return nil, err
+		}
+		return &kmsKeyWrapper{client: hsmCli, keyName: ksConf.KmsKeyName}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key store encryption %q", ksConf.Encryption)
+	}
+}
+
+// OpenKeyStore 按配置打开 key store，开启加密时解包（首次使用时生成）DEK
+func OpenKeyStore(conf *config.Config) (*Keys, error) {
+	wrapper, err := NewKeyWrapper(conf)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := NewKeyStore(conf.LevelDbPath)
+	if err != nil {
+		return nil, err
+	}
+	if wrapper == nil {
+		return keys, nil
+	}
+	if err := keys.unlock(wrapper); err != nil {
+		_ = keys.Close()
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (k *Keys) unlock(wrapper KeyWrapper) error {
+	var dek []byte
+	data, err := k.db.Get([]byte(dekRecordKey))
+	if err == nil {
+		var record dekRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("parse data encryption key record: %w", err)
+		}
+		if record.Kek != wrapper.Name() {
+			return fmt.Errorf("key store is encrypted with %s kek, configured %s", record.Kek, wrapper.Name())
+		}
+		wrapped, err := hex.DecodeString(record.WrappedDek)
+		if err != nil {
+			return fmt.Errorf("decode wrapped data encryption key: %w", err)
+		}
+		dek, err = wrapper.Unwrap(wrapped)
+		if err != nil {
+			return fmt.Errorf("unwrap data encryption key: %w", err)
+		}
+	} else if isNotFound(err) {
+		dek, err = newDataEncryptionKey()
+		if err != nil {
+			return err
+		}
+		wrapped, err := wrapper.Wrap(dek)
+		if err != nil {
+			return fmt.Errorf("wrap data encryption key: %w", err)
+		}
+		record, _ := json.Marshal(dekRecord{Version: 1, Kek: wrapper.Name(), WrappedDek: hex.EncodeToString(wrapped)})
+		if err := k.db.Put([]byte(dekRecordKey), record); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+	c, err := newValueCipher(dek)
+	if err != nil {
+		return err
+	}
+	k.cipher = c
+	return nil
+}
+
+// passphraseKeyWrapper 从口令派生 KEK，包装结果为 kdf || salt || nonce || 密文，盐值随包装结果保存
+type passphraseKeyWrapper struct {
+	passphrase []byte
+	kdf        string
+}
+
+const kekSaltSize = 16
+
+func NewPassphraseKeyWrapper(passphrase string, kdf string) (KeyWrapper, error) {
+	if kdf == "" {
+		kdf = KdfArgon2id
+	}
+	if kdf != KdfArgon2id && kdf != KdfScrypt {
+		return nil, fmt.Errorf("unsupported kdf %q", kdf)
+	}
+	return &passphraseKeyWrapper{passphrase: []byte(passphrase), kdf: kdf}, nil
+}
+
+func (w *passphraseKeyWrapper) Name() string {
+	return EncryptionPassphrase
+}
+
+func (w *passphraseKeyWrapper) Wrap(dek []byte) ([]byte, error) {
+	salt := make([]byte, kekSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	kek, err := deriveKek(w.kdf, w.passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := aesWrap(kek, dek)
+	if err != nil {
+		return nil, err
+	}
+	header := append([]byte(w.kdf+":"), salt...)
+	return append(header, sealed...), nil
+}
+
+func (w *passphraseKeyWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	sep := strings.IndexByte(string(wrapped), ':')
+	if sep < 0 || len(wrapped) < sep+1+kekSaltSize {
+		return nil, errors.New("invalid wrapped key")
+	}
+	kdf := string(wrapped[:sep])
+	salt := wrapped[sep+1 : sep+1+kekSaltSize]
+	kek, err := deriveKek(kdf, w.passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	return aesUnwrap(kek, wrapped[sep+1+kekSaltSize:])
+}
+
+func deriveKek(kdf string, passphrase, salt []byte) ([]byte, error) {
+	switch kdf {
+	case KdfArgon2id:
+		return argon2.IDKey(passphrase, salt, 3, 64*1024, 4, 32), nil
+	case KdfScrypt:
+		return scrypt.Key(passphrase, salt, 1<<18, 8, 1, 32)
+	default:
+		return nil, fmt.Errorf("unsupported kdf %q", kdf)
+	}
+}
+
+// fileKeyWrapper 从文件读取 32 字节 KEK，文件内容可以是原始字节或 hex 字符串
+type fileKeyWrapper struct {
+	kek []byte
+}
+
+func NewFileKeyWrapper(path string) (KeyWrapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read kek file: %w", err)
+	}
+	kek := data
+	if decoded, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil {
+		kek = decoded
+	}
+	if len(kek) != 32 {
+		return nil, errors.New("kek file must contain 32 bytes")
+	}
+	return &fileKeyWrapper{kek: kek}, nil
+}
+
+func (w *fileKeyWrapper) Name() string {
+	return EncryptionFile
+}
+
+func (w *fileKeyWrapper) Wrap(dek []byte) ([]byte, error) {
+	return aesWrap(w.kek, dek)
+}
+
+func (w *fileKeyWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	return aesUnwrap(w.kek, wrapped)
+}
+
+// kmsKeyWrapper 使用 KMS 对称密钥包装 DEK，KEK 本身不离开 KMS
+type kmsKeyWrapper struct {
+	client  *hsm.HsmClient
+	keyName string
+}
+
+func (w *kmsKeyWrapper) Name() string {
+	return EncryptionKms
+}
+
+func (w *kmsKeyWrapper) Wrap(dek []byte) ([]byte, error) {
+	return w.client.Encrypt(w.keyName, dek)
+}
+
+func (w *kmsKeyWrapper) Unwrap(wrapped []byte) ([]byte, error) {
+	return w.client.Decrypt(w.keyName, wrapped)
+}
+
+func aesWrap(kek, dek []byte) ([]byte, error) {
+	aead, err := newAead(kek)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dek, nil), nil
+}
+
+func aesUnwrap(kek, wrapped []byte) ([]byte, error) {
+	aead, err := newAead(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key")
+	}
+	dek, err := aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("wrong key encryption key")
+	}
+	return dek, nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
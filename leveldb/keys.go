@@ -1,11 +1,50 @@
 package leveldb
 
 import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/0xshin-chan/wallet-sign/consumer"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+const (
+	keyPrefix   = "key/"
+	seedPrefix  = "seed/"
+	indexPrefix = "index/"
+	metaPrefix  = "meta/"
+	// 旧版本单独保存派生路径的前缀，迁移后合并进密钥记录
+	legacyPathPrefix = "path/"
+)
+
+var (
+	ErrKeyNotFound      = errors.New("key not found")
+	ErrConsumerNotFound = errors.New("consumer not found")
 )
 
 type Keys struct {
 	db *LevelStore
+	// 开启静态加密后私钥和种子以密文存储，为 nil 时按明文存储
+	cipher *valueCipher
+	// 保护 HD 钱包地址索引的读-改-写
+	indexLock sync.Mutex
+	// 保护密钥记录的读-改-写和删除
+	recordLock sync.Mutex
+	// 同一纳秒内写入的滚动窗口事件用序号区分
+	velocitySeq atomic.Uint64
 }
 
 func NewKeyStore(path string) (*Keys, error) {
@@ -17,25 +56,356 @@ func NewKeyStore(path string) (*Keys, error) {
 	return &Keys{db: db}, nil
 }
 
-func (k *Keys) StoreKeys(keyList []Key) bool {
+func (k *Keys) Close() error {
+	return k.db.Close()
+}
+
+func (k *Keys) StoreKeys(ctx context.Context, keyList []Key) bool {
+	_, span := startSpan(ctx, "StoreKeys", attribute.Int("keys", len(keyList)))
+	defer span.End()
+	k.recordLock.Lock()
+	defer k.recordLock.Unlock()
+	batch := new(leveldb.Batch)
 	for _, item := range keyList {
-		key := []byte(item.PublicKey)
-		value := toBytes(item.PrivateKey)
-		err := k.db.Put(key, value)
-		if err != nil {
-			log.Error("store key value fail", "err", err, "key", key, "value", value)
+		if err := k.putKeyRecord(batch, &item); err != nil {
+			log.Error("store key value fail", "err", err, "chain", item.Chain, "key", item.PublicKey)
 			return false
 		}
 	}
+	if err := k.db.Write(batch, nil); err != nil {
+		log.Error("store key batch fail", "err", err)
+		return false
+	}
 	return true
 }
 
-func (k *Keys) GetPrivKey(publicKey string) (string, bool) {
-	key := []byte(publicKey)
+// GetKey 读取某条链下的密钥记录，其它链的同名公钥不可见
+func (k *Keys) GetKey(ctx context.Context, chain string, publicKey string) (*Key, error) {
+	_, span := startSpan(ctx, "GetKey", attribute.String("chain", chain))
+	record, err := k.getKey(chain, publicKey)
+	if errors.Is(err, ErrKeyNotFound) {
+		span.End()
+		return nil, err
+	}
+	tracing.End(span, err)
+	return record, err
+}
+
+func (k *Keys) getKey(chain string, publicKey string) (*Key, error) {
+	key := keyRecordKey(chain, publicKey)
+	data, err := k.db.Get(key)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return k.decodeKeyRecord(key, data)
+}
+
+func (k *Keys) putKeyRecord(batch *leveldb.Batch, item *Key) error {
+	if item.Chain == "" || item.PublicKey == "" {
+		return errors.New("key record must have chain and public key")
+	}
+	key := keyRecordKey(item.Chain, item.PublicKey)
+	value, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if k.cipher != nil {
+		value, err = k.cipher.seal(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	batch.Put(key, value)
+	return nil
+}
+
+func keyRecordKey(chain string, publicKey string) []byte {
+	return []byte(keyPrefix + chain + "/" + publicKey)
+}
+
+// MigrateLegacyKeys 把旧版本以裸公钥为 key 存储的私钥复制为 chain 下的密钥记录。
+// 旧数据没有记录所属链，只迁移公钥格式与 curve 匹配的条目；publicKeys 非空时只迁移其中列出的公钥。
+// secp256k1 公钥被多条链共用，无法从格式判断属于哪条链，必须给出 publicKeys。
+// 原记录保留不删，确认各链都迁移完成后再用 PurgeLegacyKeys 清理
+func (k *Keys) MigrateLegacyKeys(chain string, curve string, publicKeys map[string]bool) (int, error) {
+	if curve == ssm.CurveSecp256k1 && len(publicKeys) == 0 {
+		return 0, errors.New("secp256k1 keys are shared by several chains, list the public keys to migrate")
+	}
+	iter := k.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	migrated := 0
+	for iter.Next() {
+		publicKey := string(iter.Key())
+		if strings.Contains(publicKey, "/") || !legacyKeyMatchesCurve(publicKey, curve) {
+			continue
+		}
+		if len(publicKeys) > 0 && !publicKeys[publicKey] {
+			continue
+		}
+		priKey := iter.Value()
+		if k.cipher != nil && isCiphertext(priKey) {
+			var err error
+			if priKey, err = k.cipher.open(iter.Key(), priKey); err != nil {
+				return 0, err
+			}
+		}
+		record := Key{
+			Chain:      chain,
+			Curve:      curve,
+			Consumer:   consumer.Default,
+			PublicKey:  publicKey,
+			PrivateKey: toString(priKey),
+		}
+		if path, err := k.db.Get([]byte(legacyPathPrefix + publicKey)); err == nil {
+			record.DerivationPath = string(path)
+		}
+		if err := k.putKeyRecord(batch, &record); err != nil {
+			return 0, err
+		}
+		migrated++
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := k.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return migrated, nil
+}
+
+// PurgeLegacyKeys 删除已经迁移到某条链下的旧版本记录和派生路径，没有迁移的旧记录保留
+func (k *Keys) PurgeLegacyKeys() (int, error) {
+	migrated := make(map[string]bool)
+	records := k.db.NewIterator(util.BytesPrefix([]byte(keyPrefix)), nil)
+	for records.Next() {
+		name := strings.TrimPrefix(string(records.Key()), keyPrefix)
+		if i := strings.Index(name, "/"); i >= 0 {
+			migrated[name[i+1:]] = true
+		}
+	}
+	records.Release()
+	if err := records.Error(); err != nil {
+		return 0, err
+	}
+
+	iter := k.db.NewIterator(nil, nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	purged := 0
+	for iter.Next() {
+		publicKey := string(iter.Key())
+		if strings.Contains(publicKey, "/") || !migrated[publicKey] {
+			continue
+		}
+		batch.Delete([]byte(publicKey))
+		batch.Delete([]byte(legacyPathPrefix + publicKey))
+		purged++
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := k.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// legacyKeyMatchesCurve 旧数据按公钥格式判断曲线：secp256k1 为 65 字节非压缩公钥，ed25519 为 32 字节
+func legacyKeyMatchesCurve(publicKey string, curve string) bool {
+	pubKey, err := hex.DecodeString(publicKey)
+	if err != nil {
+		return false
+	}
+	switch curve {
+	case ssm.CurveSecp256k1:
+		return len(pubKey) == 65 && pubKey[0] == 0x04
+	case ssm.CurveEd25519:
+		return len(pubKey) == 32
+	default:
+		return false
+	}
+}
+
+// StoreSeed 保存加密后的 HD 钱包主种子，同名种子已存在时拒绝覆盖
+func (k *Keys) StoreSeed(name string, encryptedSeed []byte) error {
+	key := []byte(seedPrefix + name)
+	if _, err := k.db.Get(key); err == nil {
+		return errors.New("seed already exists")
+	} else if !isNotFound(err) {
+		return err
+	}
+	return k.putSecret(key, encryptedSeed)
+}
+
+func (k *Keys) GetSeed(name string) ([]byte, bool) {
+	data, err := k.getSecret([]byte(seedPrefix + name))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// NextIndex 分配种子下一个未使用的地址索引
+func (k *Keys) NextIndex(name string) (uint32, error) {
+	k.indexLock.Lock()
+	defer k.indexLock.Unlock()
+
+	key := []byte(indexPrefix + name)
+	var index uint32
 	data, err := k.db.Get(key)
+	switch {
+	case err == nil:
+		index = binary.BigEndian.Uint32(data)
+	case !isNotFound(err):
+		return 0, err
+	}
+	if err := k.db.Put(key, binary.BigEndian.AppendUint32(nil, index+1)); err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// Encrypted 返回 key store 是否开启了静态加密
+func (k *Keys) Encrypted() bool {
+	return k.cipher != nil
+}
+
+// MigrateEncryption 把迁移前以明文保存的私钥和种子就地加密，返回加密的条目数
+func (k *Keys) MigrateEncryption() (int, error) {
+	if k.cipher == nil {
+		return 0, errors.New("key store encryption is not enabled")
+	}
+	iter := k.db.NewIterator(nil, nil)
+	defer iter.Release()
+
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		key := iter.Key()
+		if !isSecretKey(key) || isCiphertext(iter.Value()) {
+			continue
+		}
+		sealed, err := k.cipher.seal(key, iter.Value())
+		if err != nil {
+			return 0, err
+		}
+		batch.Put(append([]byte(nil), key...), sealed)
+	}
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := k.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return batch.Len(), nil
+}
+
+func (k *Keys) putSecret(key, value []byte) error {
+	if k.cipher == nil {
+		return k.db.Put(key, value)
+	}
+	sealed, err := k.cipher.seal(key, value)
+	if err != nil {
+		return err
+	}
+	return k.db.Put(key, sealed)
+}
+
+func (k *Keys) getSecret(key []byte) ([]byte, error) {
+	data, err := k.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if k.cipher == nil {
+		return data, nil
+	}
+	return k.cipher.open(key, data)
+}
+
+// isSecretKey 密钥记录、旧版本的裸私钥和种子需要加密，路径、索引、元数据、限额计数和 nonce 记录不需要
+func isSecretKey(key []byte) bool {
+	s := string(key)
+	return !strings.HasPrefix(s, legacyPathPrefix) &&
+		!strings.HasPrefix(s, indexPrefix) &&
+		!strings.HasPrefix(s, metaPrefix) &&
+		!strings.HasPrefix(s, velocityPrefix) &&
+		!strings.HasPrefix(s, noncePrefix)
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(err, leveldb.ErrNotFound)
+}
+
+const tssPrefix = "tss/"
+
+// StoreTssShare 保存门限签名参与方的私钥分片，开启静态加密时按密文存储
+func (k *Keys) StoreTssShare(party int, keyID string, share []byte) error {
+	return k.putSecret(tssShareKey(party, keyID), share)
+}
+
+func (k *Keys) GetTssShare(party int, keyID string) ([]byte, error) {
+	data, err := k.getSecret(tssShareKey(party, keyID))
 	if err != nil {
-		return "0x00", false
+		if isNotFound(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func tssShareKey(party int, keyID string) []byte {
+	return []byte(tssPrefix + strconv.Itoa(party) + "/" + keyID)
+}
+
+const consumerPrefix = "consumer/"
+
+// PutConsumer 保存或覆盖调用方记录
+func (k *Keys) PutConsumer(c *consumer.Consumer) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return k.putSecret([]byte(consumerPrefix+c.Name), data)
+}
+
+func (k *Keys) GetConsumer(name string) (*consumer.Consumer, error) {
+	data, err := k.getSecret([]byte(consumerPrefix + name))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrConsumerNotFound
+		}
+		return nil, err
+	}
+	var c consumer.Consumer
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListConsumers 按名字顺序列出数据库中的调用方
+func (k *Keys) ListConsumers() ([]consumer.Consumer, error) {
+	iter := k.db.NewIterator(util.BytesPrefix([]byte(consumerPrefix)), nil)
+	defer iter.Release()
+	var consumers []consumer.Consumer
+	for iter.Next() {
+		data := iter.Value()
+		if k.cipher != nil {
+			var err error
+			if data, err = k.cipher.open(iter.Key(), data); err != nil {
+				return nil, err
+			}
+		}
+		var c consumer.Consumer
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+		consumers = append(consumers, c)
 	}
-	privKey := toString(data)
-	return privKey, true
+	return consumers, iter.Error()
 }
@@ -0,0 +1,233 @@
+package leveldb
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func openTestKeys(t *testing.T, path string, wrapper KeyWrapper) *Keys {
+	keys, err := NewKeyStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wrapper != nil {
+		if err := keys.unlock(wrapper); err != nil {
+			_ = keys.Close()
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestMigrateEncryption(t *testing.T) {
+	dir := t.TempDir()
+	kekFile := filepath.Join(dir, "kek")
+	if err := os.WriteFile(kekFile, bytes.Repeat([]byte{7}, 32), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wrapper, err := NewFileKeyWrapper(kekFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbPath := filepath.Join(dir, "data")
+	priKey := "646448df201c4cdc805a3271de8e5d951ac4cb83ccb88b8cbc31540d1cdd7fd0"
+	pubKey := "04a3cdc9ec7962a2531a65196830e5c322239fcfbc36fad2db1a577cc70ae6b90817dad1f288b35f9d8754190c1521bf9b7119217497687f0e62f8adac4c69e7d6"
+
+	// 以旧格式明文写入，模拟加密和命名空间上线前的数据库
+	keys := openTestKeys(t, dbPath, nil)
+	if err := keys.db.Put([]byte(pubKey), toBytes(priKey)); err != nil {
+		t.Fatal(err)
+	}
+	_ = keys.Close()
+
+	keys = openTestKeys(t, dbPath, wrapper)
+	count, err := keys.MigrateEncryption()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("encrypted %d entries, want 1", count)
+	}
+	raw, err := keys.db.Get([]byte(pubKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCiphertext(raw) || bytes.Contains(raw, toBytes(priKey)) {
+		t.Fatal("value is not stored as ciphertext")
+	}
+
+	// secp256k1 公钥无法区分所属链，不给出公钥列表时拒绝迁移
+	if _, err := keys.MigrateLegacyKeys("Ethereum", "secp256k1", nil); err == nil {
+		t.Fatal("expected migration without a public key list to fail")
+	}
+	count, err = keys.MigrateLegacyKeys("Ethereum", "secp256k1", map[string]bool{pubKey: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("migrated %d legacy keys, want 1", count)
+	}
+	// 原记录保留，可以继续迁移到其它链
+	if _, err := keys.db.Get([]byte(pubKey)); err != nil {
+		t.Fatalf("legacy record was removed by migration: %v", err)
+	}
+	record, err := keys.GetKey(context.Background(), "Ethereum", pubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.PrivateKey != priKey || record.Chain != "Ethereum" || record.Curve != "secp256k1" {
+		t.Fatalf("unexpected record %+v", record)
+	}
+	if _, err := keys.GetKey(context.Background(), "Bitcoin", pubKey); err != ErrKeyNotFound {
+		t.Fatalf("err = %v, want ErrKeyNotFound for another chain", err)
+	}
+	raw, err = keys.db.Get(keyRecordKey("Ethereum", pubKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isCiphertext(raw) {
+		t.Fatal("key record is not stored as ciphertext")
+	}
+	count, err = keys.PurgeLegacyKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.db.Get([]byte(pubKey)); count != 1 || !isNotFound(err) {
+		t.Fatalf("purged %d legacy keys, err %v", count, err)
+	}
+	if _, err := keys.GetKey(context.Background(), "Ethereum", pubKey); err != nil {
+		t.Fatal(err)
+	}
+	_ = keys.Close()
+
+	// 换一个 KEK 后无法解包 DEK
+	if err := os.WriteFile(kekFile, bytes.Repeat([]byte{8}, 32), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wrongWrapper, _ := NewFileKeyWrapper(kekFile)
+	keys, err = NewKeyStore(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keys.Close()
+	if err := keys.unlock(wrongWrapper); err == nil {
+		t.Fatal("expected unlock with wrong kek to fail")
+	}
+}
+
+func TestPassphraseKeyWrapper(t *testing.T) {
+	for _, kdf := range []string{KdfArgon2id, KdfScrypt} {
+		wrapper, err := NewPassphraseKeyWrapper("correct horse", kdf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dek := bytes.Repeat([]byte{1}, dekSize)
+		wrapped, err := wrapper.Wrap(dek)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unwrapped, err := wrapper.Unwrap(wrapped)
+		if err != nil || !bytes.Equal(unwrapped, dek) {
+			t.Fatalf("%s: unwrap mismatch, err = %v", kdf, err)
+		}
+		other, _ := NewPassphraseKeyWrapper("wrong", kdf)
+		if _, err := other.Unwrap(wrapped); err == nil {
+			t.Fatalf("%s: expected unwrap with wrong passphrase to fail", kdf)
+		}
+	}
+}
+
+func TestKeyLifecycle(t *testing.T) {
+	keys := openTestKeys(t, filepath.Join(t.TempDir(), "data"), nil)
+	defer keys.Close()
+
+	var records []Key
+	for _, pub := range []string{"01", "02", "03", "04", "05"} {
+		records = append(records, Key{Chain: "Solana", Consumer: "default", PublicKey: pub, PrivateKey: "aa"})
+	}
+	records = append(records, Key{Chain: "Ethereum", Consumer: "default", PublicKey: "06", PrivateKey: "aa"})
+	if !keys.StoreKeys(context.Background(), records) {
+		t.Fatal("store keys fail")
+	}
+
+	page, next, err := keys.ListKeys("Solana", KeyFilter{}, 2, "")
+	if err != nil || len(page) != 2 || next != "02" {
+		t.Fatalf("first page = %d keys, next %q, err %v", len(page), next, err)
+	}
+	var all []string
+	for token := ""; ; {
+		page, token, err = keys.ListKeys("Solana", KeyFilter{}, 2, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range page {
+			all = append(all, record.PublicKey)
+		}
+		if token == "" {
+			break
+		}
+	}
+	if len(all) != 5 {
+		t.Fatalf("listed %v, want 5 solana keys", all)
+	}
+
+	// 标签和状态分别修改，互不覆盖
+	if err := keys.SetKeyStatus("Solana", "03", KeyStatusDisabled); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.SetKeyLabel("Solana", "03", "cold"); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.SetKeyLabel("Solana", "missing", "cold"); err != ErrKeyNotFound {
+		t.Fatalf("err = %v, want ErrKeyNotFound", err)
+	}
+	page, _, _ = keys.ListKeys("Solana", KeyFilter{Status: KeyStatusDisabled}, 10, "")
+	if len(page) != 1 || page[0].PublicKey != "03" || page[0].Label != "cold" {
+		t.Fatalf("disabled keys = %+v", page)
+	}
+
+	if err := keys.DeleteKey("Solana", "03", "guess"); err != ErrInvalidConfirmToken {
+		t.Fatalf("err = %v, want ErrInvalidConfirmToken", err)
+	}
+	token, err := keys.IssueDeleteToken("Solana", "03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.DeleteKey("Solana", "03", token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.GetKey(context.Background(), "Solana", "03"); err != ErrKeyNotFound {
+		t.Fatalf("err = %v, want ErrKeyNotFound after delete", err)
+	}
+
+	// 删除门限密钥时同时删除各方的私钥分片，其它密钥的分片保留
+	tssKey := Key{Chain: "Ethereum", Consumer: "default", PublicKey: "07", PrivateKey: "tss:02ab"}
+	if !keys.StoreKeys(context.Background(), []Key{tssKey}) {
+		t.Fatal("store tss key fail")
+	}
+	for party := 1; party <= 3; party++ {
+		if err := keys.StoreTssShare(party, "tss:02ab", []byte("share")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := keys.StoreTssShare(1, "tss:02abcd", []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if token, err = keys.IssueDeleteToken("Ethereum", "07"); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.DeleteKey("Ethereum", "07", token); err != nil {
+		t.Fatal(err)
+	}
+	for party := 1; party <= 3; party++ {
+		if _, err := keys.GetTssShare(party, "tss:02ab"); err != ErrKeyNotFound {
+			t.Fatalf("party %d share: err = %v, want ErrKeyNotFound", party, err)
+		}
+	}
+	if _, err := keys.GetTssShare(1, "tss:02abcd"); err != nil {
+		t.Fatalf("unrelated share was deleted: %v", err)
+	}
+}
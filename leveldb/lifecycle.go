@@ -0,0 +1,211 @@
+package leveldb
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	KeyStatusActive   = "active"
+	KeyStatusDisabled = "disabled"
+
+	deleteTokenPrefix = "meta/delete/"
+	// 删除确认 token 的有效期
+	deleteTokenTTL = 5 * time.Minute
+)
+
+var ErrInvalidConfirmToken = errors.New("invalid or expired confirm token")
+
+// KeyFilter 列出密钥时的过滤条件，空字段表示不过滤
+type KeyFilter struct {
+	Consumer string
+	Label    string
+	Status   string
+}
+
+func (f KeyFilter) match(record *Key) bool {
+	return (f.Consumer == "" || record.Consumer == f.Consumer) &&
+		(f.Label == "" || record.Label == f.Label) &&
+		(f.Status == "" || record.KeyStatus() == f.Status)
+}
+
+// KeyStatus 旧记录没有状态字段，视为 active
+func (k *Key) KeyStatus() string {
+	if k.Status == "" {
+		return KeyStatusActive
+	}
+	return k.Status
+}
+
+// ListKeys 按公钥顺序分页列出某条链下的密钥，pageToken 为上一页最后一个公钥
+func (k *Keys) ListKeys(chain string, filter KeyFilter, pageSize int, pageToken string) ([]*Key, string, error) {
+	prefix := []byte(keyPrefix + chain + "/")
+	iter := k.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	var ok bool
+	if pageToken != "" {
+		// Seek 定位到 >= token 的位置，跳过 token 本身
+		ok = iter.Seek(keyRecordKey(chain, pageToken))
+		if ok && string(iter.Key()) == string(keyRecordKey(chain, pageToken)) {
+			ok = iter.Next()
+		}
+	} else {
+		ok = iter.First()
+	}
+
+	var records []*Key
+	for ; ok; ok = iter.Next() {
+		record, err := k.decodeKeyRecord(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, "", err
+		}
+		if !filter.match(record) {
+			continue
+		}
+		if len(records) == pageSize {
+			return records, records[len(records)-1].PublicKey, nil
+		}
+		records = append(records, record)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, "", err
+	}
+	return records, "", nil
+}
+
+// SetKeyLabel 只修改密钥记录的标签
+func (k *Keys) SetKeyLabel(chain string, publicKey string, label string) error {
+	return k.updateKey(chain, publicKey, func(record *Key) {
+		record.Label = label
+	})
+}
+
+// SetKeyStatus 只修改密钥记录的状态
+func (k *Keys) SetKeyStatus(chain string, publicKey string, status string) error {
+	return k.updateKey(chain, publicKey, func(record *Key) {
+		record.Status = status
+	})
+}
+
+// updateKey 加锁后重新读取记录，只修改 update 涉及的字段，并发的修改不会互相覆盖
+func (k *Keys) updateKey(chain string, publicKey string, update func(record *Key)) error {
+	k.recordLock.Lock()
+	defer k.recordLock.Unlock()
+	record, err := k.getKey(chain, publicKey)
+	if err != nil {
+		return err
+	}
+	update(record)
+	batch := new(leveldb.Batch)
+	if err := k.putKeyRecord(batch, record); err != nil {
+		return err
+	}
+	return k.db.Write(batch, nil)
+}
+
+type deleteToken struct {
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// IssueDeleteToken 为删除密钥生成一次性确认 token
+func (k *Keys) IssueDeleteToken(chain string, publicKey string) (string, error) {
+	if _, err := k.getKey(chain, publicKey); err != nil {
+		return "", err
+	}
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(tokenBytes)
+	value, _ := json.Marshal(deleteToken{Token: token, ExpiresAt: time.Now().Add(deleteTokenTTL).Unix()})
+	if err := k.db.Put(deleteTokenKey(chain, publicKey), value); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteKey 校验确认 token 后永久删除密钥记录，门限密钥的各方私钥分片在同一个 batch 中删除
+func (k *Keys) DeleteKey(chain string, publicKey string, token string) error {
+	k.recordLock.Lock()
+	defer k.recordLock.Unlock()
+	data, err := k.db.Get(deleteTokenKey(chain, publicKey))
+	if err != nil {
+		if isNotFound(err) {
+			return ErrInvalidConfirmToken
+		}
+		return err
+	}
+	var issued deleteToken
+	if err := json.Unmarshal(data, &issued); err != nil {
+		return err
+	}
+	if time.Now().Unix() > issued.ExpiresAt ||
+		subtle.ConstantTimeCompare([]byte(issued.Token), []byte(token)) != 1 {
+		return ErrInvalidConfirmToken
+	}
+	record, err := k.getKey(chain, publicKey)
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete(keyRecordKey(chain, publicKey))
+	batch.Delete(deleteTokenKey(chain, publicKey))
+	if err := k.deleteTssShares(batch, record.PrivateKey); err != nil {
+		return err
+	}
+	return k.db.Write(batch, nil)
+}
+
+// deleteTssShares 删除所有参与方保存的 keyID 分片，分片的 key 为 tss/<party>/<keyID>
+func (k *Keys) deleteTssShares(batch *leveldb.Batch, keyID string) error {
+	if keyID == "" {
+		return nil
+	}
+	iter := k.db.NewIterator(util.BytesPrefix([]byte(tssPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		if strings.HasSuffix(string(iter.Key()), "/"+keyID) {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+	}
+	return iter.Error()
+}
+
+// CountKeys 返回 key store 中密钥记录的数量
+func (k *Keys) CountKeys() (int, error) {
+	iter := k.db.NewIterator(util.BytesPrefix([]byte(keyPrefix)), nil)
+	defer iter.Release()
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	return count, iter.Error()
+}
+
+func (k *Keys) decodeKeyRecord(key, value []byte) (*Key, error) {
+	if k.cipher != nil {
+		var err error
+		if value, err = k.cipher.open(key, value); err != nil {
+			return nil, err
+		}
+	}
+	var record Key
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func deleteTokenKey(chain string, publicKey string) []byte {
+	return []byte(deleteTokenPrefix + chain + "/" + publicKey)
+}
@@ -0,0 +1,58 @@
+package leveldb
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const pendingPrefix = "pending/"
+
+var ErrPendingNotFound = errors.New("pending transaction not found")
+
+// PutPendingTransaction 保存或覆盖待审批交易
+func (k *Keys) PutPendingTransaction(tx *PendingTransaction) error {
+	data, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+	return k.putSecret([]byte(pendingPrefix+tx.Id), data)
+}
+
+func (k *Keys) GetPendingTransaction(id string) (*PendingTransaction, error) {
+	data, err := k.getSecret([]byte(pendingPrefix + id))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrPendingNotFound
+		}
+		return nil, err
+	}
+	var tx PendingTransaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// ListPendingTransactions 按 id 顺序列出所有待审批交易
+func (k *Keys) ListPendingTransactions() ([]*PendingTransaction, error) {
+	iter := k.db.NewIterator(util.BytesPrefix([]byte(pendingPrefix)), nil)
+	defer iter.Release()
+	var txs []*PendingTransaction
+	for iter.Next() {
+		data := iter.Value()
+		if k.cipher != nil {
+			var err error
+			if data, err = k.cipher.open(iter.Key(), data); err != nil {
+				return nil, err
+			}
+		}
+		var tx PendingTransaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			return nil, err
+		}
+		txs = append(txs, &tx)
+	}
+	return txs, iter.Error()
+}
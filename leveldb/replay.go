@@ -0,0 +1,101 @@
+package leveldb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+const (
+	// 幂等记录，key 为 idempotency/<consumer>\x00<method>\x00<request id>，value 按密文存储
+	idempotencyPrefix = "idempotency/"
+	// 已签名的 nonce，key 为 nonce/<chain>\x00<public key>\x00<nonce>，value 为交易请求体的 hash
+	noncePrefix = "nonce/"
+)
+
+// IdempotencyRecord request_id 第一次成功签名的结果
+type IdempotencyRecord struct {
+	// 去掉 consumer_token 后请求的 sha256，hex
+	Fingerprint string `json:"fingerprint"`
+	// 序列化后的 proto 响应
+	Response  []byte `json:"response"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func idempotencyKey(consumerName string, method string, requestID string) []byte {
+	return []byte(idempotencyPrefix + consumerName + "\x00" + method + "\x00" + requestID)
+}
+
+// GetIdempotencyRecord 没有记录时返回 nil
+func (k *Keys) GetIdempotencyRecord(ctx context.Context, consumerName string, method string, requestID string) (*IdempotencyRecord, error) {
+	_, span := startSpan(ctx, "GetIdempotencyRecord")
+	data, err := k.getSecret(idempotencyKey(consumerName, method, requestID))
+	if err != nil {
+		if isNotFound(err) {
+			span.End()
+			return nil, nil
+		}
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.End()
+	var record IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (k *Keys) PutIdempotencyRecord(ctx context.Context, consumerName string, method string, requestID string, record *IdempotencyRecord) error {
+	_, span := startSpan(ctx, "PutIdempotencyRecord")
+	data, err := json.Marshal(record)
+	if err == nil {
+		err = k.putSecret(idempotencyKey(consumerName, method, requestID), data)
+	}
+	tracing.End(span, err)
+	return err
+}
+
+// NonceRecord 一个 (链, 公钥, nonce) 已签名的交易
+type NonceRecord struct {
+	Chain     string
+	PublicKey string
+	Nonce     string
+	BodyHash  string
+}
+
+func nonceKey(chainName string, publicKey string, nonce string) []byte {
+	return []byte(noncePrefix + chainName + "\x00" + publicKey + "\x00" + nonce)
+}
+
+// GetNonceBodyHash 返回该 nonce 已签名交易的请求体 hash，没有签名过时返回空字符串
+func (k *Keys) GetNonceBodyHash(ctx context.Context, chainName string, publicKey string, nonce string) (string, error) {
+	_, span := startSpan(ctx, "GetNonceBodyHash", attribute.String("chain", chainName))
+	data, err := k.db.Get(nonceKey(chainName, publicKey, nonce))
+	if err != nil {
+		if isNotFound(err) {
+			span.End()
+			return "", nil
+		}
+		tracing.End(span, err)
+		return "", err
+	}
+	span.End()
+	return string(data), nil
+}
+
+// PutNonceRecords 在一个 batch 中记录签名成功的 nonce，替换交易覆盖原来的记录
+func (k *Keys) PutNonceRecords(ctx context.Context, records []NonceRecord) error {
+	_, span := startSpan(ctx, "PutNonceRecords", attribute.Int("records", len(records)))
+	batch := new(leveldb.Batch)
+	for _, record := range records {
+		batch.Put(nonceKey(record.Chain, record.PublicKey, record.Nonce), []byte(record.BodyHash))
+	}
+	err := k.db.Write(batch, nil)
+	tracing.End(span, err)
+	return err
+}
@@ -0,0 +1,15 @@
+package leveldb
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0xshin-chan/wallet-sign/tracing"
+)
+
+// startSpan key store 操作的 span，名称为 leveldb.<操作>，不记录公钥和值
+func startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "leveldb."+op, attrs...)
+}
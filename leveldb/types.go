@@ -1,6 +1,55 @@
 package leveldb
 
+// Key 一条密钥记录，以 key/<chain>/<public key> 为 key 整体加密存储
 type Key struct {
-	PrivateKey string
-	PublicKey  string
+	Chain          string `json:"chain"`
+	Curve          string `json:"curve"`
+	Network        string `json:"network"`
+	Consumer       string `json:"consumer"`
+	PublicKey      string `json:"public_key"`
+	PrivateKey     string `json:"private_key"`
+	CreatedAt      int64  `json:"created_at"`
+	Label          string `json:"label"`
+	DerivationPath string `json:"derivation_path"`
+	Status         string `json:"status"`
+	// 登记外部 HSM 密钥时记录的链上地址
+	Address string `json:"address,omitempty"`
+}
+
+// 待审批交易的状态
+const (
+	PendingStatusPending  = "pending"
+	PendingStatusApproved = "approved"
+	PendingStatusSigned   = "signed"
+	PendingStatusRejected = "rejected"
+	PendingStatusExpired  = "expired"
+	PendingStatusFailed   = "failed"
+)
+
+// PendingTransaction 等待多方审批的交易，以 pending/<id> 为 key 加密存储
+type PendingTransaction struct {
+	Id            string `json:"id"`
+	Consumer      string `json:"consumer"`
+	ChainName     string `json:"chain_name"`
+	Network       string `json:"network"`
+	PublicKey     string `json:"public_key"`
+	TxBase64Body  string `json:"tx_base64_body"`
+	WalletKeyHash string `json:"wallet_key_hash"`
+	RiskKeyHash   string `json:"risk_key_hash"`
+	// 替换同一 nonce 已签名的交易
+	Replacement bool `json:"replacement,omitempty"`
+	// 审批人签名的对象，hex
+	IntentHash string `json:"intent_hash"`
+	Status     string `json:"status"`
+	// 审批人 id -> 签名
+	Approvals  map[string]string `json:"approvals"`
+	RejectedBy string            `json:"rejected_by,omitempty"`
+	// 被拒绝或签名失败的原因
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+	// 达到法定人数并签名后的结果
+	SignedTx      string `json:"signed_tx,omitempty"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	TxMessageHash string `json:"tx_message_hash,omitempty"`
 }
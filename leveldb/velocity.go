@@ -0,0 +1,91 @@
+package leveldb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// 滚动窗口计数器的事件，key 为 velocity/<counter>\x00<unix 纳秒><序号>，value 为金额
+const velocityPrefix = "velocity/"
+
+// VelocityEvent 计数器中的一次签名，Amount 为 nil 表示只计次数
+type VelocityEvent struct {
+	Counter string
+	At      time.Time
+	Amount  *big.Int
+}
+
+func velocityCounterPrefix(counter string) []byte {
+	return []byte(velocityPrefix + counter + "\x00")
+}
+
+func velocityTimeKey(counter string, at time.Time) []byte {
+	return binary.BigEndian.AppendUint64(velocityCounterPrefix(counter), uint64(at.UnixNano()))
+}
+
+// VelocityUsage 返回计数器在 since 之后（含）的事件数和金额合计
+func (k *Keys) VelocityUsage(counter string, since time.Time) (int, *big.Int, error) {
+	prefix := util.BytesPrefix(velocityCounterPrefix(counter))
+	iter := k.db.NewIterator(&util.Range{Start: velocityTimeKey(counter, since), Limit: prefix.Limit}, nil)
+	defer iter.Release()
+
+	count, total := 0, new(big.Int)
+	for iter.Next() {
+		count++
+		if len(iter.Value()) == 0 {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(string(iter.Value()), 10)
+		if !ok {
+			return 0, nil, fmt.Errorf("invalid velocity event amount %q", iter.Value())
+		}
+		total.Add(total, amount)
+	}
+	if err := iter.Error(); err != nil {
+		return 0, nil, err
+	}
+	return count, total, nil
+}
+
+// AddVelocityEvents 在一个 batch 中写入事件，同时删除各计数器 before 之前的过期事件。
+// 返回写入的 key，签名失败时用 DeleteVelocityEvents 撤销
+func (k *Keys) AddVelocityEvents(events []VelocityEvent, before map[string]time.Time) ([][]byte, error) {
+	batch := new(leveldb.Batch)
+	for counter, at := range before {
+		iter := k.db.NewIterator(&util.Range{Start: velocityCounterPrefix(counter), Limit: velocityTimeKey(counter, at)}, nil)
+		for iter.Next() {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+		iter.Release()
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+	}
+	keys := make([][]byte, 0, len(events))
+	for _, event := range events {
+		key := binary.BigEndian.AppendUint64(velocityTimeKey(event.Counter, event.At), k.velocitySeq.Add(1))
+		var value []byte
+		if event.Amount != nil {
+			value = []byte(event.Amount.String())
+		}
+		batch.Put(key, value)
+		keys = append(keys, key)
+	}
+	if err := k.db.Write(batch, nil); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (k *Keys) DeleteVelocityEvents(keys [][]byte) error {
+	batch := new(leveldb.Batch)
+	for _, key := range keys {
+		batch.Delete(key)
+	}
+	return k.db.Write(batch, nil)
+}
@@ -0,0 +1,165 @@
+// Package metrics 签名服务的 Prometheus 指标。
+//
+// 指标注册在独立的 Registry 中，由 NewServer 在 /metrics 输出：按 RPC 方法、链和返回码统计的请求数和耗时，
+// 按原因统计的拒绝次数，批量签名的交易数，KMS/HSM 调用的耗时和错误，审计日志写入失败的次数，以及 key store 中的密钥数量。
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "wallet_sign"
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	Registry = prometheus.NewRegistry()
+
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "RPC requests by method, chain and return code.",
+	}, []string{"method", "chain", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "RPC request latency by method, chain and return code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "chain", "code"})
+
+	rejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rejections_total",
+		Help:      "Signing requests rejected by policy, velocity limits, approvals or replay protection, by reason.",
+	}, []string{"method", "chain", "reason"})
+
+	batchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "batch_size",
+		Help:      "Number of transactions in a batch signing request.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 50, 100, 200, 500},
+	}, []string{"chain"})
+
+	kmsCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kms_calls_total",
+		Help:      "KMS/HSM backend calls by backend, operation and result.",
+	}, []string{"backend", "operation", "result"})
+
+	kmsCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "kms_call_duration_seconds",
+		Help:      "KMS/HSM backend call latency by backend and operation.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"backend", "operation"})
+
+	auditFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_write_failures_total",
+		Help:      "Audit log entries that could not be written, by RPC method.",
+	}, []string{"method"})
+
+	keyStore = &keyStoreCollector{
+		size: prometheus.NewDesc(prometheus.BuildFQName(namespace, "key_store", "keys"),
+			"Number of key records in the key store.", nil, nil),
+		errors: prometheus.NewDesc(prometheus.BuildFQName(namespace, "key_store", "scrape_error"),
+			"1 if the key store could not be counted during the last scrape.", nil, nil),
+	}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requests, requestDuration, rejections, batchSize, kmsCalls, kmsCallDuration, auditFailures, keyStore,
+	)
+}
+
+// ObserveRequest 记录一次 RPC 请求
+func ObserveRequest(method, chain, code string, elapsed time.Duration) {
+	requests.WithLabelValues(method, chain, code).Inc()
+	requestDuration.WithLabelValues(method, chain, code).Observe(elapsed.Seconds())
+}
+
+// ObserveRejection 记录一次被拒绝的签名请求，reason 为响应中的 reject_reason
+func ObserveRejection(method, chain, reason string) {
+	rejections.WithLabelValues(method, chain, reason).Inc()
+}
+
+// ObserveBatchSize 记录批量签名请求中的交易数
+func ObserveBatchSize(chain string, size int) {
+	batchSize.WithLabelValues(chain).Observe(float64(size))
+}
+
+// ObserveKmsCall 记录一次 KMS/HSM 调用，err 不为 nil 时计为错误
+func ObserveKmsCall(backend, operation string, elapsed time.Duration, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	kmsCalls.WithLabelValues(backend, operation, result).Inc()
+	kmsCallDuration.WithLabelValues(backend, operation).Observe(elapsed.Seconds())
+}
+
+// ObserveAuditFailure 记录一次审计日志写入失败
+func ObserveAuditFailure(method string) {
+	auditFailures.WithLabelValues(method).Inc()
+}
+
+// SetKeyStore 设置抓取时统计密钥数量的函数，为 nil 时不输出 key store 指标
+func SetKeyStore(count func() (int, error)) {
+	keyStore.mu.Lock()
+	defer keyStore.mu.Unlock()
+	keyStore.count = count
+}
+
+// keyStoreCollector 在抓取时统计 key store 中的密钥数量
+type keyStoreCollector struct {
+	size   *prometheus.Desc
+	errors *prometheus.Desc
+
+	mu    sync.Mutex
+	count func() (int, error)
+}
+
+func (c *keyStoreCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.size
+	ch <- c.errors
+}
+
+func (c *keyStoreCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	count := c.count
+	c.mu.Unlock()
+	if count == nil {
+		return
+	}
+	n, err := count()
+	if err != nil {
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.GaugeValue, 1)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(n))
+	ch <- prometheus.MustNewConstMetric(c.errors, prometheus.GaugeValue, 0)
+}
+
+// NewServer 返回在 addr 上输出 /metrics 的 HTTP 服务，由调用方启动和关闭
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveKmsCall(t *testing.T) {
+	before := testutil.ToFloat64(kmsCalls.WithLabelValues("memory", "sign", ResultError))
+	ObserveKmsCall("memory", "sign", time.Millisecond, nil)
+	ObserveKmsCall("memory", "sign", time.Millisecond, errors.New("unavailable"))
+	if got := testutil.ToFloat64(kmsCalls.WithLabelValues("memory", "sign", ResultError)); got != before+1 {
+		t.Fatalf("kms errors = %v, want %v", got, before+1)
+	}
+}
+
+func TestServerExportsKeyStoreSize(t *testing.T) {
+	SetKeyStore(func() (int, error) { return 7, nil })
+	defer SetKeyStore(nil)
+	ObserveRequest("signTransactionMessage", "Ethereum", "SUCCESS", 20*time.Millisecond)
+
+	server := httptest.NewServer(NewServer("").Handler)
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"wallet_sign_key_store_keys 7",
+		"wallet_sign_key_store_scrape_error 0",
+		`wallet_sign_requests_total{chain="Ethereum",code="SUCCESS",method="signTransactionMessage"}`,
+		`wallet_sign_request_duration_seconds_bucket{chain="Ethereum",code="SUCCESS",method="signTransactionMessage"`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("metrics output has no %q:\n%s", want, body)
+		}
+	}
+
+	SetKeyStore(func() (int, error) { return 0, errors.New("closed") })
+	if got := testutil.CollectAndCount(keyStore, "wallet_sign_key_store_keys"); got != 0 {
+		t.Fatalf("key store size exported after count failure")
+	}
+}
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// 两次检查策略文件的最小间隔
+const reloadInterval = time.Second
+
+// Engine 从文件加载策略，文件修改后在下一次检查时重新加载，无需重启。
+// 新文件解析失败时继续使用旧策略
+type Engine struct {
+	path string
+
+	mu        sync.Mutex
+	policy    *Policy
+	modTime   time.Time
+	lastCheck time.Time
+}
+
+func NewEngine(path string) (*Engine, error) {
+	e := &Engine{path: path}
+	if err := e.load(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Engine) load() error {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return fmt.Errorf("policy: %w", err)
+	}
+	policy, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	e.policy, e.modTime = policy, info.ModTime()
+	return nil
+}
+
+// current 返回当前策略，文件有变化时先重新加载
+func (e *Engine) current() *Policy {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	if now.Sub(e.lastCheck) < reloadInterval {
+		return e.policy
+	}
+	e.lastCheck = now
+	info, err := os.Stat(e.path)
+	if err != nil {
+		log.Warn("stat policy file fail", "err", err)
+		return e.policy
+	}
+	if info.ModTime().Equal(e.modTime) {
+		return e.policy
+	}
+	if err := e.load(); err != nil {
+		log.Error("reload policy fail, keep the previous one", "err", err)
+		return e.policy
+	}
+	log.Info("policy reloaded", "path", e.path)
+	return e.policy
+}
+
+func (e *Engine) CheckRawMessage(chain string) error {
+	return e.current().CheckRawMessage(chain)
+}
+
+func (e *Engine) Evaluate(chain string, intents []Intent) error {
+	return e.current().Evaluate(chain, intents)
+}
+
+func (e *Engine) RequiresApproval(chain string, intents []Intent) bool {
+	return e.current().RequiresApproval(chain, intents)
+}
@@ -0,0 +1,40 @@
+// Package policy 在签名前按声明式规则检查交易。
+//
+// 各链 adaptor 把交易请求解码成统一的 Intent，Engine 按链检查单笔限额、目的地址黑白名单、
+// 合约方法白名单和手续费上限，拒绝时返回带原因码的 *Violation。
+package policy
+
+import (
+	"math/big"
+	"strings"
+)
+
+// AssetNative 链的原生币（ETH、SOL、BTC）
+const AssetNative = "native"
+
+// Intent 一笔交易中的一个转账或合约调用，一笔交易可以解码出多个 Intent（例如 Solana 的多个 action）
+type Intent struct {
+	Chain string
+	From  string
+	To    string
+	// AssetNative 或代币合约（mint）地址
+	Asset  string
+	Amount *big.Int
+	// 整笔交易的最大手续费，无法确定时为 nil
+	Fee *big.Int
+	// 被调用的合约，普通转账为空
+	Contract string
+	// 合约方法 selector（hex，不带 0x）或 Solana action 类型
+	Method   string
+	CallData []byte
+	// 账户 nonce，同一发送方的同一 nonce 只能签名一笔交易；Ethereum 为 <chain id>:<nonce>，没有账户 nonce 的链为空
+	Nonce string
+}
+
+// NormalizeAddress 0x 开头的地址不区分大小写，统一转成小写；base58 地址区分大小写，保持不变
+func NormalizeAddress(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
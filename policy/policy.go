@@ -0,0 +1,261 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// 拒绝原因码，通过 reject_reason 返回给调用方
+const (
+	ReasonRawMessage            = "raw_message_not_allowed"
+	ReasonDecodeFailed          = "intent_decode_failed"
+	ReasonDestinationDenied     = "destination_denied"
+	ReasonDestinationNotAllowed = "destination_not_allowed"
+	ReasonAssetNotAllowed       = "asset_not_allowed"
+	ReasonAmountLimit           = "amount_limit_exceeded"
+	ReasonMethodNotAllowed      = "method_not_allowed"
+	ReasonFeeCap                = "fee_cap_exceeded"
+)
+
+// Violation 交易违反的规则
+type Violation struct {
+	Reason string
+	Detail string
+}
+
+func (v *Violation) Error() string {
+	return v.Reason + ": " + v.Detail
+}
+
+func violation(reason string, format string, args ...interface{}) *Violation {
+	return &Violation{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// File 策略文件，YAML 或 JSON。金额均为链的最小单位（wei、lamport、satoshi）的十进制字符串
+type File struct {
+	Chains map[string]ChainPolicy `yaml:"chains" json:"chains"`
+}
+
+// ChainPolicy 一条链的规则，列表为空表示不限制。
+// 策略文件中列出的链默认不允许对无法解码的原始消息签名
+type ChainPolicy struct {
+	AllowRawMessages bool `yaml:"allow_raw_messages" json:"allow_raw_messages"`
+	// 整笔交易的手续费上限
+	MaxFee  string   `yaml:"max_fee" json:"max_fee"`
+	AllowTo []string `yaml:"allow_to" json:"allow_to"`
+	DenyTo  []string `yaml:"deny_to" json:"deny_to"`
+	// 允许的资产及单笔限额，key 为 native 或代币合约地址；不为空时未列出的资产被拒绝
+	Assets map[string]AssetPolicy `yaml:"assets" json:"assets"`
+	// 允许的方法（Ethereum selector 或 Solana action 类型）
+	Methods []string `yaml:"methods" json:"methods"`
+	// 允许调用的合约及其方法，不为空时未列出的合约被拒绝
+	ContractMethods map[string][]string `yaml:"contract_methods" json:"contract_methods"`
+}
+
+type AssetPolicy struct {
+	// 单笔交易中该资产的总额上限，为空表示不限制
+	MaxAmount string `yaml:"max_amount" json:"max_amount"`
+	// 单笔交易中该资产的总额超过该值时需要多方审批，为空表示不需要
+	ApprovalAmount string `yaml:"approval_amount" json:"approval_amount"`
+}
+
+// chainRules 解析后的 ChainPolicy
+type chainRules struct {
+	allowRaw        bool
+	maxFee          *big.Int
+	allowTo         map[string]bool
+	denyTo          map[string]bool
+	assets          map[string]*big.Int
+	approvalAmounts map[string]*big.Int
+	methods         map[string]bool
+	contractMethods map[string]map[string]bool
+}
+
+// Policy 解析后的策略文件，只读
+type Policy struct {
+	chains map[string]*chainRules
+}
+
+// Parse 解析 YAML 或 JSON 策略，未知字段视为错误，避免拼错的规则被静默忽略
+func Parse(data []byte) (*Policy, error) {
+	var file File
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	p := &Policy{chains: make(map[string]*chainRules, len(file.Chains))}
+	for chain, conf := range file.Chains {
+		rules, err := compile(conf)
+		if err != nil {
+			return nil, fmt.Errorf("policy for %s: %w", chain, err)
+		}
+		p.chains[chain] = rules
+	}
+	return p, nil
+}
+
+func compile(conf ChainPolicy) (*chainRules, error) {
+	rules := &chainRules{
+		allowRaw: conf.AllowRawMessages,
+		allowTo:  addressSet(conf.AllowTo),
+		denyTo:   addressSet(conf.DenyTo),
+		methods:  methodSet(conf.Methods),
+	}
+	var err error
+	if rules.maxFee, err = parseAmount(conf.MaxFee); err != nil {
+		return nil, fmt.Errorf("max_fee: %w", err)
+	}
+	if len(conf.Assets) > 0 {
+		rules.assets = make(map[string]*big.Int, len(conf.Assets))
+		rules.approvalAmounts = make(map[string]*big.Int)
+		for asset, assetConf := range conf.Assets {
+			limit, err := parseAmount(assetConf.MaxAmount)
+			if err != nil {
+				return nil, fmt.Errorf("asset %s: %w", asset, err)
+			}
+			rules.assets[NormalizeAddress(asset)] = limit
+			threshold, err := parseAmount(assetConf.ApprovalAmount)
+			if err != nil {
+				return nil, fmt.Errorf("asset %s approval_amount: %w", asset, err)
+			}
+			if threshold != nil {
+				rules.approvalAmounts[NormalizeAddress(asset)] = threshold
+			}
+		}
+	}
+	if len(conf.ContractMethods) > 0 {
+		rules.contractMethods = make(map[string]map[string]bool, len(conf.ContractMethods))
+		for contract, methods := range conf.ContractMethods {
+			rules.contractMethods[NormalizeAddress(contract)] = methodSet(methods)
+		}
+	}
+	return rules, nil
+}
+
+func parseAmount(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+func addressSet(addresses []string) map[string]bool {
+	if len(addresses) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		set[NormalizeAddress(address)] = true
+	}
+	return set
+}
+
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[normalizeMethod(method)] = true
+	}
+	return set
+}
+
+func normalizeMethod(method string) string {
+	return strings.TrimPrefix(strings.ToLower(method), "0x")
+}
+
+// CheckRawMessage 检查能否对链上无法解码的原始消息签名，策略中没有该链时不限制
+func (p *Policy) CheckRawMessage(chain string) error {
+	rules, ok := p.chains[chain]
+	if !ok || rules.allowRaw {
+		return nil
+	}
+	return violation(ReasonRawMessage, "%s policy does not allow signing raw messages", chain)
+}
+
+// Evaluate 检查一笔交易解码出的所有 Intent，策略中没有该链时不限制
+func (p *Policy) Evaluate(chain string, intents []Intent) error {
+	rules, ok := p.chains[chain]
+	if !ok {
+		return nil
+	}
+	for _, intent := range intents {
+		if err := rules.check(intent); err != nil {
+			return err
+		}
+	}
+	for asset, total := range assetTotals(intents) {
+		if limit := rules.assets[asset]; limit != nil && total.Cmp(limit) > 0 {
+			return violation(ReasonAmountLimit, "%s amount %s exceeds %s", asset, total, limit)
+		}
+	}
+	return nil
+}
+
+// RequiresApproval 交易中任一资产的总额超过 approval_amount 时需要多方审批
+func (p *Policy) RequiresApproval(chain string, intents []Intent) bool {
+	rules, ok := p.chains[chain]
+	if !ok || len(rules.approvalAmounts) == 0 {
+		return false
+	}
+	for asset, total := range assetTotals(intents) {
+		if threshold := rules.approvalAmounts[asset]; threshold != nil && total.Cmp(threshold) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// assetTotals 按资产合计交易中的转出金额
+func assetTotals(intents []Intent) map[string]*big.Int {
+	totals := make(map[string]*big.Int)
+	for _, intent := range intents {
+		if intent.Amount == nil {
+			continue
+		}
+		asset := NormalizeAddress(intent.Asset)
+		if totals[asset] == nil {
+			totals[asset] = new(big.Int)
+		}
+		totals[asset].Add(totals[asset], intent.Amount)
+	}
+	return totals
+}
+
+func (r *chainRules) check(intent Intent) error {
+	if intent.To != "" {
+		to := NormalizeAddress(intent.To)
+		if r.denyTo[to] {
+			return violation(ReasonDestinationDenied, "%s is denied", intent.To)
+		}
+		if r.allowTo != nil && !r.allowTo[to] {
+			return violation(ReasonDestinationNotAllowed, "%s is not in the allowlist", intent.To)
+		}
+	}
+	if r.assets != nil && intent.Amount != nil {
+		if _, ok := r.assets[NormalizeAddress(intent.Asset)]; !ok {
+			return violation(ReasonAssetNotAllowed, "%s is not allowed", intent.Asset)
+		}
+	}
+	method := normalizeMethod(intent.Method)
+	if method != "" && len(r.methods) > 0 && !r.methods[method] {
+		return violation(ReasonMethodNotAllowed, "method %s is not allowed", intent.Method)
+	}
+	if intent.Contract != "" && r.contractMethods != nil {
+		methods, ok := r.contractMethods[NormalizeAddress(intent.Contract)]
+		if !ok {
+			return violation(ReasonMethodNotAllowed, "contract %s is not allowed", intent.Contract)
+		}
+		if len(methods) > 0 && !methods[method] {
+			return violation(ReasonMethodNotAllowed, "method %s of %s is not allowed", intent.Method, intent.Contract)
+		}
+	}
+	if r.maxFee != nil && intent.Fee != nil && intent.Fee.Cmp(r.maxFee) > 0 {
+		return violation(ReasonFeeCap, "fee %s exceeds %s", intent.Fee, r.maxFee)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+chains:
+  Ethereum:
+    max_fee: "1000000000000000"
+    deny_to: ["0x000000000000000000000000000000000000dEaD"]
+    assets:
+      native: {max_amount: "1000"}
+      "0xdAC17F958D2ee523a2206206994597C13D831ec7": {max_amount: "500"}
+    contract_methods:
+      "0xdac17f958d2ee523a2206206994597c13d831ec7": ["0xa9059cbb"]
+  Solana:
+    allow_raw_messages: true
+    allow_to: [9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM]
+    methods: [sol_transfer, memo]
+`
+
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	usdt := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	transfer := func(to string, asset string, amount int64) Intent {
+		intent := Intent{To: to, Asset: asset, Amount: big.NewInt(amount), Fee: big.NewInt(21000)}
+		if asset != AssetNative {
+			intent.Contract, intent.Method = asset, "a9059cbb"
+		}
+		return intent
+	}
+	to := "0x35096AD62E57e86032a3Bb35aDaCF2240d55421D"
+	cases := []struct {
+		name    string
+		chain   string
+		intents []Intent
+		reason  string
+	}{
+		{"native within limit", "Ethereum", []Intent{transfer(to, AssetNative, 1000)}, ""},
+		{"token within limit", "Ethereum", []Intent{transfer(to, usdt, 500)}, ""},
+		{"native over limit", "Ethereum", []Intent{transfer(to, AssetNative, 1001)}, ReasonAmountLimit},
+		// 单笔限额按整笔交易累计
+		{"split over limit", "Ethereum", []Intent{transfer(to, AssetNative, 600), transfer(to, AssetNative, 600)}, ReasonAmountLimit},
+		{"denied destination", "Ethereum", []Intent{transfer("0x000000000000000000000000000000000000dead", AssetNative, 1)}, ReasonDestinationDenied},
+		{"unknown asset", "Ethereum", []Intent{transfer(to, "0x6B175474E89094C44Da98b954EedeAC495271d0F", 1)}, ReasonAssetNotAllowed},
+		{"unknown method", "Ethereum", []Intent{{To: to, Asset: usdt, Amount: big.NewInt(1), Contract: usdt, Method: "095ea7b3"}}, ReasonMethodNotAllowed},
+		{"fee cap", "Ethereum", []Intent{{To: to, Asset: AssetNative, Amount: big.NewInt(1), Fee: big.NewInt(1000000000000001)}}, ReasonFeeCap},
+		{"allowlisted", "Solana", []Intent{{To: "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", Method: "sol_transfer"}, {Method: "memo"}}, ""},
+		// base58 地址区分大小写
+		{"not allowlisted", "Solana", []Intent{{To: "9wzdxwbbmkg8ztbnmquxvqrayrzzdsgydlvl9zytawwm", Method: "sol_transfer"}}, ReasonDestinationNotAllowed},
+		{"action not allowed", "Solana", []Intent{{Method: "stake_withdraw"}}, ReasonMethodNotAllowed},
+		{"chain without policy", "Bitcoin", []Intent{{To: "bc1q", Amount: big.NewInt(1 << 40)}}, ""},
+	}
+	for _, c := range cases {
+		err := p.Evaluate(c.chain, c.intents)
+		var v *Violation
+		switch {
+		case c.reason == "" && err != nil:
+			t.Fatalf("%s: unexpected rejection %v", c.name, err)
+		case c.reason != "" && (!errors.As(err, &v) || v.Reason != c.reason):
+			t.Fatalf("%s: err = %v, want %s", c.name, err, c.reason)
+		}
+	}
+
+	if err := p.CheckRawMessage("Ethereum"); err == nil {
+		t.Fatal("expected raw messages to be rejected on Ethereum")
+	}
+	if p.CheckRawMessage("Solana") != nil || p.CheckRawMessage("Bitcoin") != nil {
+		t.Fatal("raw messages should be allowed on Solana and Bitcoin")
+	}
+	if _, err := Parse([]byte(`{"chains": {"Ethereum": {"max_fees": "1"}}}`)); err == nil {
+		t.Fatal("expected an unknown field to be rejected")
+	}
+	if _, err := Parse([]byte(`{"chains": {"Ethereum": {"max_fee": "-1"}}}`)); err == nil {
+		t.Fatal("expected a negative amount to be rejected")
+	}
+}
+
+func TestEngineReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yml")
+	write := func(data string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, modTime, modTime)
+	}
+	write(`{"chains": {"Ethereum": {"allow_raw_messages": true}}}`, time.Now())
+	engine, err := NewEngine(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.CheckRawMessage("Ethereum"); err != nil {
+		t.Fatal(err)
+	}
+
+	write(`{"chains": {"Ethereum": {"allow_raw_messages": false}}}`, time.Now().Add(time.Minute))
+	time.Sleep(reloadInterval)
+	if err := engine.CheckRawMessage("Ethereum"); err == nil {
+		t.Fatal("policy was not reloaded")
+	}
+
+	// 解析失败时继续使用上一份策略
+	write(`chains: [`, time.Now().Add(2*time.Minute))
+	time.Sleep(reloadInterval)
+	if err := engine.CheckRawMessage("Ethereum"); err == nil {
+		t.Fatal("broken policy replaced the previous one")
+	}
+}
@@ -568,8 +568,10 @@ type CreateKeyPairsWithAddressesRequest struct {
 	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
 	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
 	Network       string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
-	AddressFormat string                 `protobuf:"bytes,4,opt,name=address_format,json=addressFormat,proto3" json:"address_format,omitempty"`
-	KeyNum        uint64                 `protobuf:"varint,5,opt,name=key_num,json=keyNum,proto3" json:"key_num,omitempty"`
+	// Bitcoin 支持 p2pkh、p2wpkh、p2sh 和 p2tr。p2tr 地址直接以公钥作为输出公钥，没有 BIP86 tweak，
+	// 与按 BIP86 派生地址的钱包得到的地址不同，花费时用未 tweak 的私钥做 key path 签名
+	AddressFormat string `protobuf:"bytes,4,opt,name=address_format,json=addressFormat,proto3" json:"address_format,omitempty"`
+	KeyNum        uint64 `protobuf:"varint,5,opt,name=key_num,json=keyNum,proto3" json:"key_num,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -706,6 +708,8 @@ type SignTransactionMessageRequest struct {
 	Network       string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
 	PublicKey     string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	MessageHash   string                 `protobuf:"bytes,5,opt,name=message_hash,json=messageHash,proto3" json:"message_hash,omitempty"`
+	// 幂等键，同一调用方重复提交相同的 request_id 和请求时返回第一次的签名结果
+	RequestId     string `protobuf:"bytes,6,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -775,11 +779,20 @@ func (x *SignTransactionMessageRequest) GetMessageHash() string {
 	return ""
 }
 
+func (x *SignTransactionMessageRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 type SignTransactionMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          ReturnCode             `protobuf:"varint,1,opt,name=Code,proto3,enum=theweb3.wallet.ReturnCode" json:"Code,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Signature     string                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Code      ReturnCode             `protobuf:"varint,1,opt,name=Code,proto3,enum=theweb3.wallet.ReturnCode" json:"Code,omitempty"`
+	Message   string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Signature string                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// 被交易策略拒绝时的原因码，例如 raw_message_not_allowed
+	RejectReason  string `protobuf:"bytes,4,opt,name=reject_reason,json=rejectReason,proto3" json:"reject_reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -835,15 +848,27 @@ func (x *SignTransactionMessageResponse) GetSignature() string {
 	return ""
 }
 
+func (x *SignTransactionMessageResponse) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
+
 type BuildAndSignTransactionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
 	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
 	Network       string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
 	PublicKey     string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
-	WalletKeyHash string                 `protobuf:"bytes,5,opt,name=wallet_key_hash,json=walletKeyHash,proto3" json:"wallet_key_hash,omitempty"`
-	RiskKeyHash   string                 `protobuf:"bytes,6,opt,name=risk_key_hash,json=riskKeyHash,proto3" json:"risk_key_hash,omitempty"`
-	TxBase64Body  string                 `protobuf:"bytes,7,opt,name=tx_base64_body,json=txBase64Body,proto3" json:"tx_base64_body,omitempty"`
+	// 钱包后端和风控系统对 tx_base64_body 解码后内容的审批，格式为 <key id>:<hex HMAC-SHA256 或签名>
+	WalletKeyHash string `protobuf:"bytes,5,opt,name=wallet_key_hash,json=walletKeyHash,proto3" json:"wallet_key_hash,omitempty"`
+	RiskKeyHash   string `protobuf:"bytes,6,opt,name=risk_key_hash,json=riskKeyHash,proto3" json:"risk_key_hash,omitempty"`
+	TxBase64Body  string `protobuf:"bytes,7,opt,name=tx_base64_body,json=txBase64Body,proto3" json:"tx_base64_body,omitempty"`
+	// 幂等键，同一调用方重复提交相同的 request_id 和请求时返回第一次的签名结果
+	RequestId string `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// 替换同一 nonce 已签名的交易（加速或取消），否则同一 nonce 只能签名一笔交易
+	Replacement   bool `protobuf:"varint,9,opt,name=replacement,proto3" json:"replacement,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -927,6 +952,20 @@ func (x *BuildAndSignTransactionRequest) GetTxBase64Body() string {
 	return ""
 }
 
+func (x *BuildAndSignTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *BuildAndSignTransactionRequest) GetReplacement() bool {
+	if x != nil {
+		return x.Replacement
+	}
+	return false
+}
+
 type BuildAndSignTransactionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
@@ -934,6 +973,10 @@ type BuildAndSignTransactionResponse struct {
 	TxMessageHash string                 `protobuf:"bytes,3,opt,name=tx_message_hash,json=txMessageHash,proto3" json:"tx_message_hash,omitempty"`
 	TxHash        string                 `protobuf:"bytes,4,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
 	SignedTx      string                 `protobuf:"bytes,5,opt,name=signed_tx,json=signedTx,proto3" json:"signed_tx,omitempty"`
+	// 交易引用的账户数量（Solana），用于调用方估算 compute unit
+	AccountCount uint64 `protobuf:"varint,6,opt,name=account_count,json=accountCount,proto3" json:"account_count,omitempty"`
+	// 被交易策略拒绝时的原因码，例如 amount_limit_exceeded
+	RejectReason  string `protobuf:"bytes,7,opt,name=reject_reason,json=rejectReason,proto3" json:"reject_reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1003,12 +1046,27 @@ func (x *BuildAndSignTransactionResponse) GetSignedTx() string {
 	return ""
 }
 
+func (x *BuildAndSignTransactionResponse) GetAccountCount() uint64 {
+	if x != nil {
+		return x.AccountCount
+	}
+	return 0
+}
+
+func (x *BuildAndSignTransactionResponse) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
+
 type TransactionMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PublicKey     string                 `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	WalletKeyHash string                 `protobuf:"bytes,2,opt,name=wallet_key_hash,json=walletKeyHash,proto3" json:"wallet_key_hash,omitempty"`
 	RiskKeyHash   string                 `protobuf:"bytes,3,opt,name=risk_key_hash,json=riskKeyHash,proto3" json:"risk_key_hash,omitempty"`
 	TxBase64Body  string                 `protobuf:"bytes,4,opt,name=tx_base64_body,json=txBase64Body,proto3" json:"tx_base64_body,omitempty"`
+	Replacement   bool                   `protobuf:"varint,5,opt,name=replacement,proto3" json:"replacement,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1071,6 +1129,13 @@ func (x *TransactionMessage) GetTxBase64Body() string {
 	return ""
 }
 
+func (x *TransactionMessage) GetReplacement() bool {
+	if x != nil {
+		return x.Replacement
+	}
+	return false
+}
+
 type TransactionWithSign struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TxMessageHash string                 `protobuf:"bytes,1,opt,name=tx_message_hash,json=txMessageHash,proto3" json:"tx_message_hash,omitempty"`
@@ -1137,6 +1202,7 @@ type BuildAndSignBatchTransactionRequest struct {
 	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
 	Network       string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
 	TxMsg         []*TransactionMessage  `protobuf:"bytes,4,rep,name=tx_msg,json=txMsg,proto3" json:"tx_msg,omitempty"`
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1199,11 +1265,20 @@ func (x *BuildAndSignBatchTransactionRequest) GetTxMsg() []*TransactionMessage {
 	return nil
 }
 
+func (x *BuildAndSignBatchTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 type BuildAndSignBatchTransactionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	TxWithSign    []*TransactionWithSign `protobuf:"bytes,3,rep,name=tx_with_sign,json=txWithSign,proto3" json:"tx_with_sign,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Code       ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message    string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TxWithSign []*TransactionWithSign `protobuf:"bytes,3,rep,name=tx_with_sign,json=txWithSign,proto3" json:"tx_with_sign,omitempty"`
+	// 被交易策略拒绝时的原因码
+	RejectReason  string `protobuf:"bytes,4,opt,name=reject_reason,json=rejectReason,proto3" json:"reject_reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1259,125 +1334,2666 @@ func (x *BuildAndSignBatchTransactionResponse) GetTxWithSign() []*TransactionWit
 	return nil
 }
 
-var File_protobuf_wallet_proto protoreflect.FileDescriptor
+func (x *BuildAndSignBatchTransactionResponse) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
 
-const file_protobuf_wallet_proto_rawDesc = "" +
-	"\n" +
-	"\x15protobuf/wallet.proto\x12\x0etheweb3.wallet\"\x97\x01\n" +
-	"\x16ChainSignMethodRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
-	"\n" +
-	"asset_type\x18\x04 \x01(\tR\tassetType\"\x84\x01\n" +
-	"\x17ChainSignMethodResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
-	"\vsign_method\x18\x03 \x01(\tR\n" +
-	"signMethod\"\x93\x01\n" +
-	"\x12ChainSchemaRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
-	"\n" +
-	"asset_type\x18\x04 \x01(\tR\tassetType\"w\n" +
-	"\x13ChainSchemaResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
-	"\x06schema\x18\x03 \x01(\tR\x06schema\"`\n" +
-	"\x0fExportPublicKey\x12\x1d\n" +
-	"\n" +
-	"public_key\x18\x01 \x01(\tR\tpublicKey\x12.\n" +
-	"\x13compress_public_key\x18\x02 \x01(\tR\x11compressPublicKey\"\xa1\x01\n" +
-	"&CreateKeyPairAndExportPublicKeyRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x17\n" +
-	"\akey_num\x18\x04 \x01(\x04R\x06keyNum\"\xbc\x01\n" +
-	"'CreateKeyPairAndExportPublicKeyResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12G\n" +
-	"\x0fpublic_key_list\x18\x03 \x03(\v2\x1f.theweb3.wallet.ExportPublicKeyR\rpublicKeyList\"\x85\x01\n" +
-	"\x1aExportPublicKeyWithAddress\x12\x1d\n" +
-	"\n" +
-	"public_key\x18\x01 \x01(\tR\tpublicKey\x12.\n" +
-	"\x13compress_public_key\x18\x02 \x01(\tR\x11compressPublicKey\x12\x18\n" +
-	"\aaddress\x18\x03 \x01(\tR\aaddress\"\xc4\x01\n" +
-	"\"CreateKeyPairsWithAddressesRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12%\n" +
-	"\x0eaddress_format\x18\x04 \x01(\tR\raddressFormat\x12\x17\n" +
-	"\akey_num\x18\x05 \x01(\x04R\x06keyNum\"\xcd\x01\n" +
-	"#CreateKeyPairsWithAddressesResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\\\n" +
-	"\x14public_key_addresses\x18\x03 \x03(\v2*.theweb3.wallet.ExportPublicKeyWithAddressR\x12publicKeyAddresses\"\xc1\x01\n" +
-	"\x1dSignTransactionMessageRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
-	"\n" +
-	"public_key\x18\x04 \x01(\tR\tpublicKey\x12!\n" +
-	"\fmessage_hash\x18\x05 \x01(\tR\vmessageHash\"\x88\x01\n" +
-	"\x1eSignTransactionMessageResponse\x12.\n" +
-	"\x04Code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04Code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
-	"\tsignature\x18\x03 \x01(\tR\tsignature\"\x91\x02\n" +
-	"\x1eBuildAndSignTransactionRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
-	"\n" +
-	"public_key\x18\x04 \x01(\tR\tpublicKey\x12&\n" +
-	"\x0fwallet_key_hash\x18\x05 \x01(\tR\rwalletKeyHash\x12\"\n" +
-	"\rrisk_key_hash\x18\x06 \x01(\tR\vriskKeyHash\x12$\n" +
-	"\x0etx_base64_body\x18\a \x01(\tR\ftxBase64Body\"\xc9\x01\n" +
-	"\x1fBuildAndSignTransactionResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
-	"\x0ftx_message_hash\x18\x03 \x01(\tR\rtxMessageHash\x12\x17\n" +
-	"\atx_hash\x18\x04 \x01(\tR\x06txHash\x12\x1b\n" +
-	"\tsigned_tx\x18\x05 \x01(\tR\bsignedTx\"\xa5\x01\n" +
-	"\x12TransactionMessage\x12\x1d\n" +
-	"\n" +
-	"public_key\x18\x01 \x01(\tR\tpublicKey\x12&\n" +
-	"\x0fwallet_key_hash\x18\x02 \x01(\tR\rwalletKeyHash\x12\"\n" +
-	"\rrisk_key_hash\x18\x03 \x01(\tR\vriskKeyHash\x12$\n" +
-	"\x0etx_base64_body\x18\x04 \x01(\tR\ftxBase64Body\"s\n" +
-	"\x13TransactionWithSign\x12&\n" +
-	"\x0ftx_message_hash\x18\x01 \x01(\tR\rtxMessageHash\x12\x17\n" +
-	"\atx_hash\x18\x02 \x01(\tR\x06txHash\x12\x1b\n" +
-	"\tsigned_tx\x18\x03 \x01(\tR\bsignedTx\"\xc0\x01\n" +
-	"#BuildAndSignBatchTransactionRequest\x12%\n" +
-	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
-	"\n" +
-	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
-	"\anetwork\x18\x03 \x01(\tR\anetwork\x129\n" +
-	"\x06tx_msg\x18\x04 \x03(\v2\".theweb3.wallet.TransactionMessageR\x05txMsg\"\xb7\x01\n" +
-	"$BuildAndSignBatchTransactionResponse\x12.\n" +
-	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12E\n" +
-	"\ftx_with_sign\x18\x03 \x03(\v2#.theweb3.wallet.TransactionWithSignR\n" +
-	"txWithSign*$\n" +
-	"\n" +
-	"ReturnCode\x12\t\n" +
-	"\x05ERROR\x10\x00\x12\v\n" +
-	"\aSUCCESS\x10\x012\x80\a\n" +
-	"\rWalletService\x12g\n" +
-	"\x12getChainSignMethod\x12&.theweb3.wallet.ChainSignMethodRequest\x1a'.theweb3.wallet.ChainSignMethodResponse\"\x00\x12[\n" +
-	"\x0egetChainSchema\x12\".theweb3.wallet.ChainSchemaRequest\x1a#.theweb3.wallet.ChainSchemaResponse\"\x00\x12\x96\x01\n" +
-	"!createKeyPairsExportPublicKeyList\x126.theweb3.wallet.CreateKeyPairAndExportPublicKeyRequest\x1a7.theweb3.wallet.CreateKeyPairAndExportPublicKeyResponse\"\x00\x12\x88\x01\n" +
-	"\x1bcreateKeyPairsWithAddresses\x122.theweb3.wallet.CreateKeyPairsWithAddressesRequest\x1a3.theweb3.wallet.CreateKeyPairsWithAddressesResponse\"\x00\x12y\n" +
-	"\x16signTransactionMessage\x12-.theweb3.wallet.SignTransactionMessageRequest\x1a..theweb3.wallet.SignTransactionMessageResponse\"\x00\x12|\n" +
-	"\x17buildAndSignTransaction\x12..theweb3.wallet.BuildAndSignTransactionRequest\x1a/.theweb3.wallet.BuildAndSignTransactionResponse\"\x00\x12\x8b\x01\n" +
-	"\x1cbuildAndSignBatchTransaction\x123.theweb3.wallet.BuildAndSignBatchTransactionRequest\x1a4.theweb3.wallet.BuildAndSignBatchTransactionResponse\"\x00B\x13Z\x11./protobuf/walletb\x06proto3"
+type KeyInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChainName      string                 `protobuf:"bytes,1,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	PublicKey      string                 `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Curve          string                 `protobuf:"bytes,3,opt,name=curve,proto3" json:"curve,omitempty"`
+	Network        string                 `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	Consumer       string                 `protobuf:"bytes,5,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	CreatedAt      int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Label          string                 `protobuf:"bytes,7,opt,name=label,proto3" json:"label,omitempty"`
+	DerivationPath string                 `protobuf:"bytes,8,opt,name=derivation_path,json=derivationPath,proto3" json:"derivation_path,omitempty"`
+	Status         string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *KeyInfo) Reset() {
+	*x = KeyInfo{}
+	mi := &file_protobuf_wallet_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyInfo) ProtoMessage() {}
+
+func (x *KeyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyInfo.ProtoReflect.Descriptor instead.
+func (*KeyInfo) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *KeyInfo) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetCurve() string {
+	if x != nil {
+		return x.Curve
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *KeyInfo) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetDerivationPath() string {
+	if x != nil {
+		return x.DerivationPath
+	}
+	return ""
+}
+
+func (x *KeyInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Label         string                 `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	PageSize      uint32                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListKeysRequest) Reset() {
+	*x = ListKeysRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeysRequest) ProtoMessage() {}
+
+func (x *ListKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListKeysRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListKeysRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListKeysRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListKeysRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Keys          []*KeyInfo             `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListKeysResponse) Reset() {
+	*x = ListKeysResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeysResponse) ProtoMessage() {}
+
+func (x *ListKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListKeysResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListKeysResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ListKeysResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListKeysResponse) GetKeys() []*KeyInfo {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ListKeysResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyRequest) Reset() {
+	*x = GetKeyRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyRequest) ProtoMessage() {}
+
+func (x *GetKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetKeyRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *GetKeyRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *GetKeyRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+type GetKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Key           *KeyInfo               `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyResponse) Reset() {
+	*x = GetKeyResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyResponse) ProtoMessage() {}
+
+func (x *GetKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyResponse.ProtoReflect.Descriptor instead.
+func (*GetKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetKeyResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *GetKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetKeyResponse) GetKey() *KeyInfo {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type SetKeyLabelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Label         string                 `protobuf:"bytes,4,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetKeyLabelRequest) Reset() {
+	*x = SetKeyLabelRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetKeyLabelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetKeyLabelRequest) ProtoMessage() {}
+
+func (x *SetKeyLabelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetKeyLabelRequest.ProtoReflect.Descriptor instead.
+func (*SetKeyLabelRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetKeyLabelRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *SetKeyLabelRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *SetKeyLabelRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SetKeyLabelRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type SetKeyLabelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetKeyLabelResponse) Reset() {
+	*x = SetKeyLabelResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetKeyLabelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetKeyLabelResponse) ProtoMessage() {}
+
+func (x *SetKeyLabelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetKeyLabelResponse.ProtoReflect.Descriptor instead.
+func (*SetKeyLabelResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetKeyLabelResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *SetKeyLabelResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DisableKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableKeyRequest) Reset() {
+	*x = DisableKeyRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableKeyRequest) ProtoMessage() {}
+
+func (x *DisableKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableKeyRequest.ProtoReflect.Descriptor instead.
+func (*DisableKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DisableKeyRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *DisableKeyRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *DisableKeyRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+type DisableKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableKeyResponse) Reset() {
+	*x = DisableKeyResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableKeyResponse) ProtoMessage() {}
+
+func (x *DisableKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableKeyResponse.ProtoReflect.Descriptor instead.
+func (*DisableKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DisableKeyResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *DisableKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DeleteKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	// 为空时返回一个确认 token，带上该 token 再次调用才会真正删除
+	ConfirmToken  string `protobuf:"bytes,4,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteKeyRequest) Reset() {
+	*x = DeleteKeyRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteKeyRequest) ProtoMessage() {}
+
+func (x *DeleteKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteKeyRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DeleteKeyRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *DeleteKeyRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *DeleteKeyRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *DeleteKeyRequest) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+type DeleteKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ConfirmToken  string                 `protobuf:"bytes,3,opt,name=confirm_token,json=confirmToken,proto3" json:"confirm_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteKeyResponse) Reset() {
+	*x = DeleteKeyResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteKeyResponse) ProtoMessage() {}
+
+func (x *DeleteKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeleteKeyResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteKeyResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *DeleteKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteKeyResponse) GetConfirmToken() string {
+	if x != nil {
+		return x.ConfirmToken
+	}
+	return ""
+}
+
+type ImportKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	AdminToken    string                 `protobuf:"bytes,3,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	Network       string                 `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	// hex, wif, base58, keystore 或 backup（exportKeys 导出的备份文件）
+	Format string   `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"`
+	Keys   []string `protobuf:"bytes,6,rep,name=keys,proto3" json:"keys,omitempty"`
+	// keystore 和 backup 格式的解密口令
+	Password      string `protobuf:"bytes,7,opt,name=password,proto3" json:"password,omitempty"`
+	Label         string `protobuf:"bytes,8,opt,name=label,proto3" json:"label,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportKeysRequest) Reset() {
+	*x = ImportKeysRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeysRequest) ProtoMessage() {}
+
+func (x *ImportKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeysRequest.ProtoReflect.Descriptor instead.
+func (*ImportKeysRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ImportKeysRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetAdminToken() string {
+	if x != nil {
+		return x.AdminToken
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ImportKeysRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *ImportKeysRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ImportKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Keys          []*KeyInfo             `protobuf:"bytes,3,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportKeysResponse) Reset() {
+	*x = ImportKeysResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportKeysResponse) ProtoMessage() {}
+
+func (x *ImportKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportKeysResponse.ProtoReflect.Descriptor instead.
+func (*ImportKeysResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ImportKeysResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ImportKeysResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ImportKeysResponse) GetKeys() []*KeyInfo {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type ExportKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	AdminToken    string                 `protobuf:"bytes,3,opt,name=admin_token,json=adminToken,proto3" json:"admin_token,omitempty"`
+	PublicKeys    []string               `protobuf:"bytes,4,rep,name=public_keys,json=publicKeys,proto3" json:"public_keys,omitempty"`
+	// 备份文件的加密口令
+	Password      string `protobuf:"bytes,5,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportKeysRequest) Reset() {
+	*x = ExportKeysRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeysRequest) ProtoMessage() {}
+
+func (x *ExportKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeysRequest.ProtoReflect.Descriptor instead.
+func (*ExportKeysRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ExportKeysRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ExportKeysRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ExportKeysRequest) GetAdminToken() string {
+	if x != nil {
+		return x.AdminToken
+	}
+	return ""
+}
+
+func (x *ExportKeysRequest) GetPublicKeys() []string {
+	if x != nil {
+		return x.PublicKeys
+	}
+	return nil
+}
+
+func (x *ExportKeysRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ExportKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Backup        []byte                 `protobuf:"bytes,3,opt,name=backup,proto3" json:"backup,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportKeysResponse) Reset() {
+	*x = ExportKeysResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportKeysResponse) ProtoMessage() {}
+
+func (x *ExportKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportKeysResponse.ProtoReflect.Descriptor instead.
+func (*ExportKeysResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ExportKeysResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ExportKeysResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ExportKeysResponse) GetBackup() []byte {
+	if x != nil {
+		return x.Backup
+	}
+	return nil
+}
+
+type LimitUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	// 为空时只返回按调用方计数的限额
+	PublicKey     string `protobuf:"bytes,3,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitUsageRequest) Reset() {
+	*x = LimitUsageRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitUsageRequest) ProtoMessage() {}
+
+func (x *LimitUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitUsageRequest.ProtoReflect.Descriptor instead.
+func (*LimitUsageRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *LimitUsageRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *LimitUsageRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *LimitUsageRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+type LimitUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scope         string                 `protobuf:"bytes,2,opt,name=scope,proto3" json:"scope,omitempty"`
+	Subject       string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	Asset         string                 `protobuf:"bytes,4,opt,name=asset,proto3" json:"asset,omitempty"`
+	WindowSeconds int64                  `protobuf:"varint,5,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	// 未限制次数时 max_count 和 remaining_count 为 0
+	UsedCount      uint64 `protobuf:"varint,6,opt,name=used_count,json=usedCount,proto3" json:"used_count,omitempty"`
+	MaxCount       uint64 `protobuf:"varint,7,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+	RemainingCount uint64 `protobuf:"varint,8,opt,name=remaining_count,json=remainingCount,proto3" json:"remaining_count,omitempty"`
+	// 资产最小单位的十进制字符串，未限制金额时 max_amount 和 remaining_amount 为空
+	UsedAmount      string `protobuf:"bytes,9,opt,name=used_amount,json=usedAmount,proto3" json:"used_amount,omitempty"`
+	MaxAmount       string `protobuf:"bytes,10,opt,name=max_amount,json=maxAmount,proto3" json:"max_amount,omitempty"`
+	RemainingAmount string `protobuf:"bytes,11,opt,name=remaining_amount,json=remainingAmount,proto3" json:"remaining_amount,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *LimitUsage) Reset() {
+	*x = LimitUsage{}
+	mi := &file_protobuf_wallet_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitUsage) ProtoMessage() {}
+
+func (x *LimitUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitUsage.ProtoReflect.Descriptor instead.
+func (*LimitUsage) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *LimitUsage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetUsedCount() uint64 {
+	if x != nil {
+		return x.UsedCount
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetMaxCount() uint64 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetRemainingCount() uint64 {
+	if x != nil {
+		return x.RemainingCount
+	}
+	return 0
+}
+
+func (x *LimitUsage) GetUsedAmount() string {
+	if x != nil {
+		return x.UsedAmount
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetMaxAmount() string {
+	if x != nil {
+		return x.MaxAmount
+	}
+	return ""
+}
+
+func (x *LimitUsage) GetRemainingAmount() string {
+	if x != nil {
+		return x.RemainingAmount
+	}
+	return ""
+}
+
+type LimitUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Usages        []*LimitUsage          `protobuf:"bytes,3,rep,name=usages,proto3" json:"usages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitUsageResponse) Reset() {
+	*x = LimitUsageResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitUsageResponse) ProtoMessage() {}
+
+func (x *LimitUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitUsageResponse.ProtoReflect.Descriptor instead.
+func (*LimitUsageResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LimitUsageResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *LimitUsageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LimitUsageResponse) GetUsages() []*LimitUsage {
+	if x != nil {
+		return x.Usages
+	}
+	return nil
+}
+
+type SubmitPendingTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Network       string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	PublicKey     string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	WalletKeyHash string                 `protobuf:"bytes,5,opt,name=wallet_key_hash,json=walletKeyHash,proto3" json:"wallet_key_hash,omitempty"`
+	RiskKeyHash   string                 `protobuf:"bytes,6,opt,name=risk_key_hash,json=riskKeyHash,proto3" json:"risk_key_hash,omitempty"`
+	TxBase64Body  string                 `protobuf:"bytes,7,opt,name=tx_base64_body,json=txBase64Body,proto3" json:"tx_base64_body,omitempty"`
+	Replacement   bool                   `protobuf:"varint,8,opt,name=replacement,proto3" json:"replacement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPendingTransactionRequest) Reset() {
+	*x = SubmitPendingTransactionRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPendingTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPendingTransactionRequest) ProtoMessage() {}
+
+func (x *SubmitPendingTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPendingTransactionRequest.ProtoReflect.Descriptor instead.
+func (*SubmitPendingTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SubmitPendingTransactionRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetWalletKeyHash() string {
+	if x != nil {
+		return x.WalletKeyHash
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetRiskKeyHash() string {
+	if x != nil {
+		return x.RiskKeyHash
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetTxBase64Body() string {
+	if x != nil {
+		return x.TxBase64Body
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionRequest) GetReplacement() bool {
+	if x != nil {
+		return x.Replacement
+	}
+	return false
+}
+
+type PendingTransaction struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	RequestId string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ChainName string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Network   string                 `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	PublicKey string                 `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Consumer  string                 `protobuf:"bytes,5,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	// 审批人签名的对象：sha256(chain_name \0 network \0 public_key \0 tx_base64_body 解码后的内容)，hex。
+	// ed25519 直接对它签名，secp256k1 对它的 sha256 签名
+	IntentHash string `protobuf:"bytes,6,opt,name=intent_hash,json=intentHash,proto3" json:"intent_hash,omitempty"`
+	// pending、approved、signed、rejected、expired 或 failed
+	Status     string   `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ApprovedBy []string `protobuf:"bytes,8,rep,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	Quorum     uint32   `protobuf:"varint,9,opt,name=quorum,proto3" json:"quorum,omitempty"`
+	CreatedAt  int64    `protobuf:"varint,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt  int64    `protobuf:"varint,11,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RejectedBy string   `protobuf:"bytes,12,opt,name=rejected_by,json=rejectedBy,proto3" json:"rejected_by,omitempty"`
+	Reason     string   `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	// 只返回给提交方
+	SignedTx      string `protobuf:"bytes,14,opt,name=signed_tx,json=signedTx,proto3" json:"signed_tx,omitempty"`
+	TxHash        string `protobuf:"bytes,15,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	TxMessageHash string `protobuf:"bytes,16,opt,name=tx_message_hash,json=txMessageHash,proto3" json:"tx_message_hash,omitempty"`
+	// 审批人核对的交易内容和解码后的转账目的，只在 listPendingApprovals 中返回
+	TxBase64Body  string `protobuf:"bytes,17,opt,name=tx_base64_body,json=txBase64Body,proto3" json:"tx_base64_body,omitempty"`
+	Intent        string `protobuf:"bytes,18,opt,name=intent,proto3" json:"intent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingTransaction) Reset() {
+	*x = PendingTransaction{}
+	mi := &file_protobuf_wallet_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingTransaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingTransaction) ProtoMessage() {}
+
+func (x *PendingTransaction) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingTransaction.ProtoReflect.Descriptor instead.
+func (*PendingTransaction) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PendingTransaction) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetPublicKey() string {
+	if x != nil {
+		return x.PublicKey
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetIntentHash() string {
+	if x != nil {
+		return x.IntentHash
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetApprovedBy() []string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return nil
+}
+
+func (x *PendingTransaction) GetQuorum() uint32 {
+	if x != nil {
+		return x.Quorum
+	}
+	return 0
+}
+
+func (x *PendingTransaction) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *PendingTransaction) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *PendingTransaction) GetRejectedBy() string {
+	if x != nil {
+		return x.RejectedBy
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetSignedTx() string {
+	if x != nil {
+		return x.SignedTx
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetTxMessageHash() string {
+	if x != nil {
+		return x.TxMessageHash
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetTxBase64Body() string {
+	if x != nil {
+		return x.TxBase64Body
+	}
+	return ""
+}
+
+func (x *PendingTransaction) GetIntent() string {
+	if x != nil {
+		return x.Intent
+	}
+	return ""
+}
+
+type SubmitPendingTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       *PendingTransaction    `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	RejectReason  string                 `protobuf:"bytes,4,opt,name=reject_reason,json=rejectReason,proto3" json:"reject_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPendingTransactionResponse) Reset() {
+	*x = SubmitPendingTransactionResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPendingTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPendingTransactionResponse) ProtoMessage() {}
+
+func (x *SubmitPendingTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPendingTransactionResponse.ProtoReflect.Descriptor instead.
+func (*SubmitPendingTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SubmitPendingTransactionResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *SubmitPendingTransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SubmitPendingTransactionResponse) GetPending() *PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+func (x *SubmitPendingTransactionResponse) GetRejectReason() string {
+	if x != nil {
+		return x.RejectReason
+	}
+	return ""
+}
+
+type ListPendingTransactionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingTransactionsRequest) Reset() {
+	*x = ListPendingTransactionsRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingTransactionsRequest) ProtoMessage() {}
+
+func (x *ListPendingTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListPendingTransactionsRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ListPendingTransactionsRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ListPendingTransactionsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListPendingTransactionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       []*PendingTransaction  `protobuf:"bytes,3,rep,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingTransactionsResponse) Reset() {
+	*x = ListPendingTransactionsResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingTransactionsResponse) ProtoMessage() {}
+
+func (x *ListPendingTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListPendingTransactionsResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ListPendingTransactionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListPendingTransactionsResponse) GetPending() []*PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+// ListPendingApprovalsRequest 审批人查看所有调用方在该链上的待审批交易，
+// 通过 consumers 的 methods 授权给审批人使用的调用方
+type ListPendingApprovalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingApprovalsRequest) Reset() {
+	*x = ListPendingApprovalsRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingApprovalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingApprovalsRequest) ProtoMessage() {}
+
+func (x *ListPendingApprovalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingApprovalsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingApprovalsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListPendingApprovalsRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ListPendingApprovalsRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ListPendingApprovalsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListPendingApprovalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       []*PendingTransaction  `protobuf:"bytes,3,rep,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingApprovalsResponse) Reset() {
+	*x = ListPendingApprovalsResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingApprovalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingApprovalsResponse) ProtoMessage() {}
+
+func (x *ListPendingApprovalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingApprovalsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingApprovalsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListPendingApprovalsResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ListPendingApprovalsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListPendingApprovalsResponse) GetPending() []*PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type ApprovePendingTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ApproverId    string                 `protobuf:"bytes,4,opt,name=approver_id,json=approverId,proto3" json:"approver_id,omitempty"`
+	// 审批人对 intent_hash 的签名，hex
+	Signature     string `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovePendingTransactionRequest) Reset() {
+	*x = ApprovePendingTransactionRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovePendingTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovePendingTransactionRequest) ProtoMessage() {}
+
+func (x *ApprovePendingTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovePendingTransactionRequest.ProtoReflect.Descriptor instead.
+func (*ApprovePendingTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ApprovePendingTransactionRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ApprovePendingTransactionRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ApprovePendingTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ApprovePendingTransactionRequest) GetApproverId() string {
+	if x != nil {
+		return x.ApproverId
+	}
+	return ""
+}
+
+func (x *ApprovePendingTransactionRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type ApprovePendingTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       *PendingTransaction    `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApprovePendingTransactionResponse) Reset() {
+	*x = ApprovePendingTransactionResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApprovePendingTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApprovePendingTransactionResponse) ProtoMessage() {}
+
+func (x *ApprovePendingTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApprovePendingTransactionResponse.ProtoReflect.Descriptor instead.
+func (*ApprovePendingTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ApprovePendingTransactionResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ApprovePendingTransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ApprovePendingTransactionResponse) GetPending() *PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type RejectPendingTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ApproverId    string                 `protobuf:"bytes,4,opt,name=approver_id,json=approverId,proto3" json:"approver_id,omitempty"`
+	// 审批人对 "reject:" || intent_hash（32 字节）的签名，hex
+	Signature     string `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Reason        string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectPendingTransactionRequest) Reset() {
+	*x = RejectPendingTransactionRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectPendingTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectPendingTransactionRequest) ProtoMessage() {}
+
+func (x *RejectPendingTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectPendingTransactionRequest.ProtoReflect.Descriptor instead.
+func (*RejectPendingTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RejectPendingTransactionRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionRequest) GetApproverId() string {
+	if x != nil {
+		return x.ApproverId
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectPendingTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       *PendingTransaction    `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectPendingTransactionResponse) Reset() {
+	*x = RejectPendingTransactionResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectPendingTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectPendingTransactionResponse) ProtoMessage() {}
+
+func (x *RejectPendingTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectPendingTransactionResponse.ProtoReflect.Descriptor instead.
+func (*RejectPendingTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RejectPendingTransactionResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *RejectPendingTransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RejectPendingTransactionResponse) GetPending() *PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type ExpirePendingTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ConsumerToken string                 `protobuf:"bytes,1,opt,name=consumer_token,json=consumerToken,proto3" json:"consumer_token,omitempty"`
+	ChainName     string                 `protobuf:"bytes,2,opt,name=chain_name,json=chainName,proto3" json:"chain_name,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpirePendingTransactionRequest) Reset() {
+	*x = ExpirePendingTransactionRequest{}
+	mi := &file_protobuf_wallet_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpirePendingTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpirePendingTransactionRequest) ProtoMessage() {}
+
+func (x *ExpirePendingTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpirePendingTransactionRequest.ProtoReflect.Descriptor instead.
+func (*ExpirePendingTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ExpirePendingTransactionRequest) GetConsumerToken() string {
+	if x != nil {
+		return x.ConsumerToken
+	}
+	return ""
+}
+
+func (x *ExpirePendingTransactionRequest) GetChainName() string {
+	if x != nil {
+		return x.ChainName
+	}
+	return ""
+}
+
+func (x *ExpirePendingTransactionRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type ExpirePendingTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          ReturnCode             `protobuf:"varint,1,opt,name=code,proto3,enum=theweb3.wallet.ReturnCode" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Pending       *PendingTransaction    `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpirePendingTransactionResponse) Reset() {
+	*x = ExpirePendingTransactionResponse{}
+	mi := &file_protobuf_wallet_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpirePendingTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpirePendingTransactionResponse) ProtoMessage() {}
+
+func (x *ExpirePendingTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_wallet_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpirePendingTransactionResponse.ProtoReflect.Descriptor instead.
+func (*ExpirePendingTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_wallet_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ExpirePendingTransactionResponse) GetCode() ReturnCode {
+	if x != nil {
+		return x.Code
+	}
+	return ReturnCode_ERROR
+}
+
+func (x *ExpirePendingTransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ExpirePendingTransactionResponse) GetPending() *PendingTransaction {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+var File_protobuf_wallet_proto protoreflect.FileDescriptor
+
+const file_protobuf_wallet_proto_rawDesc = "" +
+	"\n" +
+	"\x15protobuf/wallet.proto\x12\x0etheweb3.wallet\"\x97\x01\n" +
+	"\x16ChainSignMethodRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"asset_type\x18\x04 \x01(\tR\tassetType\"\x84\x01\n" +
+	"\x17ChainSignMethodResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vsign_method\x18\x03 \x01(\tR\n" +
+	"signMethod\"\x93\x01\n" +
+	"\x12ChainSchemaRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"asset_type\x18\x04 \x01(\tR\tassetType\"w\n" +
+	"\x13ChainSchemaResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06schema\x18\x03 \x01(\tR\x06schema\"`\n" +
+	"\x0fExportPublicKey\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12.\n" +
+	"\x13compress_public_key\x18\x02 \x01(\tR\x11compressPublicKey\"\xa1\x01\n" +
+	"&CreateKeyPairAndExportPublicKeyRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x17\n" +
+	"\akey_num\x18\x04 \x01(\x04R\x06keyNum\"\xbc\x01\n" +
+	"'CreateKeyPairAndExportPublicKeyResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12G\n" +
+	"\x0fpublic_key_list\x18\x03 \x03(\v2\x1f.theweb3.wallet.ExportPublicKeyR\rpublicKeyList\"\x85\x01\n" +
+	"\x1aExportPublicKeyWithAddress\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12.\n" +
+	"\x13compress_public_key\x18\x02 \x01(\tR\x11compressPublicKey\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\"\xc4\x01\n" +
+	"\"CreateKeyPairsWithAddressesRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12%\n" +
+	"\x0eaddress_format\x18\x04 \x01(\tR\raddressFormat\x12\x17\n" +
+	"\akey_num\x18\x05 \x01(\x04R\x06keyNum\"\xcd\x01\n" +
+	"#CreateKeyPairsWithAddressesResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\\\n" +
+	"\x14public_key_addresses\x18\x03 \x03(\v2*.theweb3.wallet.ExportPublicKeyWithAddressR\x12publicKeyAddresses\"\xe0\x01\n" +
+	"\x1dSignTransactionMessageRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12!\n" +
+	"\fmessage_hash\x18\x05 \x01(\tR\vmessageHash\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x06 \x01(\tR\trequestId\"\xad\x01\n" +
+	"\x1eSignTransactionMessageResponse\x12.\n" +
+	"\x04Code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04Code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\tR\tsignature\x12#\n" +
+	"\rreject_reason\x18\x04 \x01(\tR\frejectReason\"\xd2\x02\n" +
+	"\x1eBuildAndSignTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12&\n" +
+	"\x0fwallet_key_hash\x18\x05 \x01(\tR\rwalletKeyHash\x12\"\n" +
+	"\rrisk_key_hash\x18\x06 \x01(\tR\vriskKeyHash\x12$\n" +
+	"\x0etx_base64_body\x18\a \x01(\tR\ftxBase64Body\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12 \n" +
+	"\vreplacement\x18\t \x01(\bR\vreplacement\"\x93\x02\n" +
+	"\x1fBuildAndSignTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x0ftx_message_hash\x18\x03 \x01(\tR\rtxMessageHash\x12\x17\n" +
+	"\atx_hash\x18\x04 \x01(\tR\x06txHash\x12\x1b\n" +
+	"\tsigned_tx\x18\x05 \x01(\tR\bsignedTx\x12#\n" +
+	"\raccount_count\x18\x06 \x01(\x04R\faccountCount\x12#\n" +
+	"\rreject_reason\x18\a \x01(\tR\frejectReason\"\xc7\x01\n" +
+	"\x12TransactionMessage\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x01 \x01(\tR\tpublicKey\x12&\n" +
+	"\x0fwallet_key_hash\x18\x02 \x01(\tR\rwalletKeyHash\x12\"\n" +
+	"\rrisk_key_hash\x18\x03 \x01(\tR\vriskKeyHash\x12$\n" +
+	"\x0etx_base64_body\x18\x04 \x01(\tR\ftxBase64Body\x12 \n" +
+	"\vreplacement\x18\x05 \x01(\bR\vreplacement\"s\n" +
+	"\x13TransactionWithSign\x12&\n" +
+	"\x0ftx_message_hash\x18\x01 \x01(\tR\rtxMessageHash\x12\x17\n" +
+	"\atx_hash\x18\x02 \x01(\tR\x06txHash\x12\x1b\n" +
+	"\tsigned_tx\x18\x03 \x01(\tR\bsignedTx\"\xdf\x01\n" +
+	"#BuildAndSignBatchTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x129\n" +
+	"\x06tx_msg\x18\x04 \x03(\v2\".theweb3.wallet.TransactionMessageR\x05txMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\"\xdc\x01\n" +
+	"$BuildAndSignBatchTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12E\n" +
+	"\ftx_with_sign\x18\x03 \x03(\v2#.theweb3.wallet.TransactionWithSignR\n" +
+	"txWithSign\x12#\n" +
+	"\rreject_reason\x18\x04 \x01(\tR\frejectReason\"\x89\x02\n" +
+	"\aKeyInfo\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x01 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x02 \x01(\tR\tpublicKey\x12\x14\n" +
+	"\x05curve\x18\x03 \x01(\tR\x05curve\x12\x18\n" +
+	"\anetwork\x18\x04 \x01(\tR\anetwork\x12\x1a\n" +
+	"\bconsumer\x18\x05 \x01(\tR\bconsumer\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x14\n" +
+	"\x05label\x18\a \x01(\tR\x05label\x12'\n" +
+	"\x0fderivation_path\x18\b \x01(\tR\x0ederivationPath\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\"\xc1\x01\n" +
+	"\x0fListKeysRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x14\n" +
+	"\x05label\x18\x03 \x01(\tR\x05label\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\xb1\x01\n" +
+	"\x10ListKeysResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x04keys\x18\x03 \x03(\v2\x17.theweb3.wallet.KeyInfoR\x04keys\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\"t\n" +
+	"\rGetKeyRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\"\x85\x01\n" +
+	"\x0eGetKeyResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12)\n" +
+	"\x03key\x18\x03 \x01(\v2\x17.theweb3.wallet.KeyInfoR\x03key\"\x8f\x01\n" +
+	"\x12SetKeyLabelRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\x12\x14\n" +
+	"\x05label\x18\x04 \x01(\tR\x05label\"_\n" +
+	"\x13SetKeyLabelResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"x\n" +
+	"\x11DisableKeyRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\"^\n" +
+	"\x12DisableKeyResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9c\x01\n" +
+	"\x10DeleteKeyRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\x12#\n" +
+	"\rconfirm_token\x18\x04 \x01(\tR\fconfirmToken\"\x82\x01\n" +
+	"\x11DeleteKeyResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
+	"\rconfirm_token\x18\x03 \x01(\tR\fconfirmToken\"\xf2\x01\n" +
+	"\x11ImportKeysRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1f\n" +
+	"\vadmin_token\x18\x03 \x01(\tR\n" +
+	"adminToken\x12\x18\n" +
+	"\anetwork\x18\x04 \x01(\tR\anetwork\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\x12\x12\n" +
+	"\x04keys\x18\x06 \x03(\tR\x04keys\x12\x1a\n" +
+	"\bpassword\x18\a \x01(\tR\bpassword\x12\x14\n" +
+	"\x05label\x18\b \x01(\tR\x05label\"\x8b\x01\n" +
+	"\x12ImportKeysResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12+\n" +
+	"\x04keys\x18\x03 \x03(\v2\x17.theweb3.wallet.KeyInfoR\x04keys\"\xb7\x01\n" +
+	"\x11ExportKeysRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1f\n" +
+	"\vadmin_token\x18\x03 \x01(\tR\n" +
+	"adminToken\x12\x1f\n" +
+	"\vpublic_keys\x18\x04 \x03(\tR\n" +
+	"publicKeys\x12\x1a\n" +
+	"\bpassword\x18\x05 \x01(\tR\bpassword\"v\n" +
+	"\x12ExportKeysResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06backup\x18\x03 \x01(\fR\x06backup\"x\n" +
+	"\x11LimitUsageRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\tR\tpublicKey\"\xdd\x02\n" +
+	"\n" +
+	"LimitUsage\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05scope\x18\x02 \x01(\tR\x05scope\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x14\n" +
+	"\x05asset\x18\x04 \x01(\tR\x05asset\x12%\n" +
+	"\x0ewindow_seconds\x18\x05 \x01(\x03R\rwindowSeconds\x12\x1d\n" +
+	"\n" +
+	"used_count\x18\x06 \x01(\x04R\tusedCount\x12\x1b\n" +
+	"\tmax_count\x18\a \x01(\x04R\bmaxCount\x12'\n" +
+	"\x0fremaining_count\x18\b \x01(\x04R\x0eremainingCount\x12\x1f\n" +
+	"\vused_amount\x18\t \x01(\tR\n" +
+	"usedAmount\x12\x1d\n" +
+	"\n" +
+	"max_amount\x18\n" +
+	" \x01(\tR\tmaxAmount\x12)\n" +
+	"\x10remaining_amount\x18\v \x01(\tR\x0fremainingAmount\"\x92\x01\n" +
+	"\x12LimitUsageResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x122\n" +
+	"\x06usages\x18\x03 \x03(\v2\x1a.theweb3.wallet.LimitUsageR\x06usages\"\xb4\x02\n" +
+	"\x1fSubmitPendingTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12&\n" +
+	"\x0fwallet_key_hash\x18\x05 \x01(\tR\rwalletKeyHash\x12\"\n" +
+	"\rrisk_key_hash\x18\x06 \x01(\tR\vriskKeyHash\x12$\n" +
+	"\x0etx_base64_body\x18\a \x01(\tR\ftxBase64Body\x12 \n" +
+	"\vreplacement\x18\b \x01(\bR\vreplacement\"\xac\x04\n" +
+	"\x12PendingTransaction\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x18\n" +
+	"\anetwork\x18\x03 \x01(\tR\anetwork\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x04 \x01(\tR\tpublicKey\x12\x1a\n" +
+	"\bconsumer\x18\x05 \x01(\tR\bconsumer\x12\x1f\n" +
+	"\vintent_hash\x18\x06 \x01(\tR\n" +
+	"intentHash\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1f\n" +
+	"\vapproved_by\x18\b \x03(\tR\n" +
+	"approvedBy\x12\x16\n" +
+	"\x06quorum\x18\t \x01(\rR\x06quorum\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\v \x01(\x03R\texpiresAt\x12\x1f\n" +
+	"\vrejected_by\x18\f \x01(\tR\n" +
+	"rejectedBy\x12\x16\n" +
+	"\x06reason\x18\r \x01(\tR\x06reason\x12\x1b\n" +
+	"\tsigned_tx\x18\x0e \x01(\tR\bsignedTx\x12\x17\n" +
+	"\atx_hash\x18\x0f \x01(\tR\x06txHash\x12&\n" +
+	"\x0ftx_message_hash\x18\x10 \x01(\tR\rtxMessageHash\x12$\n" +
+	"\x0etx_base64_body\x18\x11 \x01(\tR\ftxBase64Body\x12\x16\n" +
+	"\x06intent\x18\x12 \x01(\tR\x06intent\"\xcf\x01\n" +
+	" SubmitPendingTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x01(\v2\".theweb3.wallet.PendingTransactionR\apending\x12#\n" +
+	"\rreject_reason\x18\x04 \x01(\tR\frejectReason\"~\n" +
+	"\x1eListPendingTransactionsRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"\xa9\x01\n" +
+	"\x1fListPendingTransactionsResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x03(\v2\".theweb3.wallet.PendingTransactionR\apending\"{\n" +
+	"\x1bListPendingApprovalsRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"\xa6\x01\n" +
+	"\x1cListPendingApprovalsResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x03(\v2\".theweb3.wallet.PendingTransactionR\apending\"\xc6\x01\n" +
+	" ApprovePendingTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12\x1f\n" +
+	"\vapprover_id\x18\x04 \x01(\tR\n" +
+	"approverId\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\"\xab\x01\n" +
+	"!ApprovePendingTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x01(\v2\".theweb3.wallet.PendingTransactionR\apending\"\xdd\x01\n" +
+	"\x1fRejectPendingTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12\x1f\n" +
+	"\vapprover_id\x18\x04 \x01(\tR\n" +
+	"approverId\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"\xaa\x01\n" +
+	" RejectPendingTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x01(\v2\".theweb3.wallet.PendingTransactionR\apending\"\x86\x01\n" +
+	"\x1fExpirePendingTransactionRequest\x12%\n" +
+	"\x0econsumer_token\x18\x01 \x01(\tR\rconsumerToken\x12\x1d\n" +
+	"\n" +
+	"chain_name\x18\x02 \x01(\tR\tchainName\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\"\xaa\x01\n" +
+	" ExpirePendingTransactionResponse\x12.\n" +
+	"\x04code\x18\x01 \x01(\x0e2\x1a.theweb3.wallet.ReturnCodeR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\apending\x18\x03 \x01(\v2\".theweb3.wallet.PendingTransactionR\apending*$\n" +
+	"\n" +
+	"ReturnCode\x12\t\n" +
+	"\x05ERROR\x10\x00\x12\v\n" +
+	"\aSUCCESS\x10\x012\xa4\x12\n" +
+	"\rWalletService\x12g\n" +
+	"\x12getChainSignMethod\x12&.theweb3.wallet.ChainSignMethodRequest\x1a'.theweb3.wallet.ChainSignMethodResponse\"\x00\x12[\n" +
+	"\x0egetChainSchema\x12\".theweb3.wallet.ChainSchemaRequest\x1a#.theweb3.wallet.ChainSchemaResponse\"\x00\x12\x96\x01\n" +
+	"!createKeyPairsExportPublicKeyList\x126.theweb3.wallet.CreateKeyPairAndExportPublicKeyRequest\x1a7.theweb3.wallet.CreateKeyPairAndExportPublicKeyResponse\"\x00\x12\x88\x01\n" +
+	"\x1bcreateKeyPairsWithAddresses\x122.theweb3.wallet.CreateKeyPairsWithAddressesRequest\x1a3.theweb3.wallet.CreateKeyPairsWithAddressesResponse\"\x00\x12y\n" +
+	"\x16signTransactionMessage\x12-.theweb3.wallet.SignTransactionMessageRequest\x1a..theweb3.wallet.SignTransactionMessageResponse\"\x00\x12|\n" +
+	"\x17buildAndSignTransaction\x12..theweb3.wallet.BuildAndSignTransactionRequest\x1a/.theweb3.wallet.BuildAndSignTransactionResponse\"\x00\x12\x8b\x01\n" +
+	"\x1cbuildAndSignBatchTransaction\x123.theweb3.wallet.BuildAndSignBatchTransactionRequest\x1a4.theweb3.wallet.BuildAndSignBatchTransactionResponse\"\x00\x12O\n" +
+	"\blistKeys\x12\x1f.theweb3.wallet.ListKeysRequest\x1a .theweb3.wallet.ListKeysResponse\"\x00\x12I\n" +
+	"\x06getKey\x12\x1d.theweb3.wallet.GetKeyRequest\x1a\x1e.theweb3.wallet.GetKeyResponse\"\x00\x12X\n" +
+	"\vsetKeyLabel\x12\".theweb3.wallet.SetKeyLabelRequest\x1a#.theweb3.wallet.SetKeyLabelResponse\"\x00\x12U\n" +
+	"\n" +
+	"disableKey\x12!.theweb3.wallet.DisableKeyRequest\x1a\".theweb3.wallet.DisableKeyResponse\"\x00\x12R\n" +
+	"\tdeleteKey\x12 .theweb3.wallet.DeleteKeyRequest\x1a!.theweb3.wallet.DeleteKeyResponse\"\x00\x12U\n" +
+	"\n" +
+	"importKeys\x12!.theweb3.wallet.ImportKeysRequest\x1a\".theweb3.wallet.ImportKeysResponse\"\x00\x12U\n" +
+	"\n" +
+	"exportKeys\x12!.theweb3.wallet.ExportKeysRequest\x1a\".theweb3.wallet.ExportKeysResponse\"\x00\x12X\n" +
+	"\rgetLimitUsage\x12!.theweb3.wallet.LimitUsageRequest\x1a\".theweb3.wallet.LimitUsageResponse\"\x00\x12\x7f\n" +
+	"\x18submitPendingTransaction\x12/.theweb3.wallet.SubmitPendingTransactionRequest\x1a0.theweb3.wallet.SubmitPendingTransactionResponse\"\x00\x12|\n" +
+	"\x17listPendingTransactions\x12..theweb3.wallet.ListPendingTransactionsRequest\x1a/.theweb3.wallet.ListPendingTransactionsResponse\"\x00\x12s\n" +
+	"\x14listPendingApprovals\x12+.theweb3.wallet.ListPendingApprovalsRequest\x1a,.theweb3.wallet.ListPendingApprovalsResponse\"\x00\x12\x82\x01\n" +
+	"\x19approvePendingTransaction\x120.theweb3.wallet.ApprovePendingTransactionRequest\x1a1.theweb3.wallet.ApprovePendingTransactionResponse\"\x00\x12\x7f\n" +
+	"\x18rejectPendingTransaction\x12/.theweb3.wallet.RejectPendingTransactionRequest\x1a0.theweb3.wallet.RejectPendingTransactionResponse\"\x00\x12\x7f\n" +
+	"\x18expirePendingTransaction\x12/.theweb3.wallet.ExpirePendingTransactionRequest\x1a0.theweb3.wallet.ExpirePendingTransactionResponse\"\x00B\x13Z\x11./protobuf/walletb\x06proto3"
 
 var (
 	file_protobuf_wallet_proto_rawDescOnce sync.Once
@@ -1392,7 +4008,7 @@ func file_protobuf_wallet_proto_rawDescGZIP() []byte {
 }
 
 var file_protobuf_wallet_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_protobuf_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_protobuf_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_protobuf_wallet_proto_goTypes = []any{
 	(ReturnCode)(0),                                 // 0: theweb3.wallet.ReturnCode
 	(*ChainSignMethodRequest)(nil),                  // 1: theweb3.wallet.ChainSignMethodRequest
@@ -1413,6 +4029,37 @@ var file_protobuf_wallet_proto_goTypes = []any{
 	(*TransactionWithSign)(nil),                     // 16: theweb3.wallet.TransactionWithSign
 	(*BuildAndSignBatchTransactionRequest)(nil),     // 17: theweb3.wallet.BuildAndSignBatchTransactionRequest
 	(*BuildAndSignBatchTransactionResponse)(nil),    // 18: theweb3.wallet.BuildAndSignBatchTransactionResponse
+	(*KeyInfo)(nil),                                 // 19: theweb3.wallet.KeyInfo
+	(*ListKeysRequest)(nil),                         // 20: theweb3.wallet.ListKeysRequest
+	(*ListKeysResponse)(nil),                        // 21: theweb3.wallet.ListKeysResponse
+	(*GetKeyRequest)(nil),                           // 22: theweb3.wallet.GetKeyRequest
+	(*GetKeyResponse)(nil),                          // 23: theweb3.wallet.GetKeyResponse
+	(*SetKeyLabelRequest)(nil),                      // 24: theweb3.wallet.SetKeyLabelRequest
+	(*SetKeyLabelResponse)(nil),                     // 25: theweb3.wallet.SetKeyLabelResponse
+	(*DisableKeyRequest)(nil),                       // 26: theweb3.wallet.DisableKeyRequest
+	(*DisableKeyResponse)(nil),                      // 27: theweb3.wallet.DisableKeyResponse
+	(*DeleteKeyRequest)(nil),                        // 28: theweb3.wallet.DeleteKeyRequest
+	(*DeleteKeyResponse)(nil),                       // 29: theweb3.wallet.DeleteKeyResponse
+	(*ImportKeysRequest)(nil),                       // 30: theweb3.wallet.ImportKeysRequest
+	(*ImportKeysResponse)(nil),                      // 31: theweb3.wallet.ImportKeysResponse
+	(*ExportKeysRequest)(nil),                       // 32: theweb3.wallet.ExportKeysRequest
+	(*ExportKeysResponse)(nil),                      // 33: theweb3.wallet.ExportKeysResponse
+	(*LimitUsageRequest)(nil),                       // 34: theweb3.wallet.LimitUsageRequest
+	(*LimitUsage)(nil),                              // 35: theweb3.wallet.LimitUsage
+	(*LimitUsageResponse)(nil),                      // 36: theweb3.wallet.LimitUsageResponse
+	(*SubmitPendingTransactionRequest)(nil),         // 37: theweb3.wallet.SubmitPendingTransactionRequest
+	(*PendingTransaction)(nil),                      // 38: theweb3.wallet.PendingTransaction
+	(*SubmitPendingTransactionResponse)(nil),        // 39: theweb3.wallet.SubmitPendingTransactionResponse
+	(*ListPendingTransactionsRequest)(nil),          // 40: theweb3.wallet.ListPendingTransactionsRequest
+	(*ListPendingTransactionsResponse)(nil),         // 41: theweb3.wallet.ListPendingTransactionsResponse
+	(*ListPendingApprovalsRequest)(nil),             // 42: theweb3.wallet.ListPendingApprovalsRequest
+	(*ListPendingApprovalsResponse)(nil),            // 43: theweb3.wallet.ListPendingApprovalsResponse
+	(*ApprovePendingTransactionRequest)(nil),        // 44: theweb3.wallet.ApprovePendingTransactionRequest
+	(*ApprovePendingTransactionResponse)(nil),       // 45: theweb3.wallet.ApprovePendingTransactionResponse
+	(*RejectPendingTransactionRequest)(nil),         // 46: theweb3.wallet.RejectPendingTransactionRequest
+	(*RejectPendingTransactionResponse)(nil),        // 47: theweb3.wallet.RejectPendingTransactionResponse
+	(*ExpirePendingTransactionRequest)(nil),         // 48: theweb3.wallet.ExpirePendingTransactionRequest
+	(*ExpirePendingTransactionResponse)(nil),        // 49: theweb3.wallet.ExpirePendingTransactionResponse
 }
 var file_protobuf_wallet_proto_depIdxs = []int32{
 	0,  // 0: theweb3.wallet.ChainSignMethodResponse.code:type_name -> theweb3.wallet.ReturnCode
@@ -1426,25 +4073,77 @@ var file_protobuf_wallet_proto_depIdxs = []int32{
 	15, // 8: theweb3.wallet.BuildAndSignBatchTransactionRequest.tx_msg:type_name -> theweb3.wallet.TransactionMessage
 	0,  // 9: theweb3.wallet.BuildAndSignBatchTransactionResponse.code:type_name -> theweb3.wallet.ReturnCode
 	16, // 10: theweb3.wallet.BuildAndSignBatchTransactionResponse.tx_with_sign:type_name -> theweb3.wallet.TransactionWithSign
-	1,  // 11: theweb3.wallet.WalletService.getChainSignMethod:input_type -> theweb3.wallet.ChainSignMethodRequest
-	3,  // 12: theweb3.wallet.WalletService.getChainSchema:input_type -> theweb3.wallet.ChainSchemaRequest
-	6,  // 13: theweb3.wallet.WalletService.createKeyPairsExportPublicKeyList:input_type -> theweb3.wallet.CreateKeyPairAndExportPublicKeyRequest
-	9,  // 14: theweb3.wallet.WalletService.createKeyPairsWithAddresses:input_type -> theweb3.wallet.CreateKeyPairsWithAddressesRequest
-	11, // 15: theweb3.wallet.WalletService.signTransactionMessage:input_type -> theweb3.wallet.SignTransactionMessageRequest
-	13, // 16: theweb3.wallet.WalletService.buildAndSignTransaction:input_type -> theweb3.wallet.BuildAndSignTransactionRequest
-	17, // 17: theweb3.wallet.WalletService.buildAndSignBatchTransaction:input_type -> theweb3.wallet.BuildAndSignBatchTransactionRequest
-	2,  // 18: theweb3.wallet.WalletService.getChainSignMethod:output_type -> theweb3.wallet.ChainSignMethodResponse
-	4,  // 19: theweb3.wallet.WalletService.getChainSchema:output_type -> theweb3.wallet.ChainSchemaResponse
-	7,  // 20: theweb3.wallet.WalletService.createKeyPairsExportPublicKeyList:output_type -> theweb3.wallet.CreateKeyPairAndExportPublicKeyResponse
-	10, // 21: theweb3.wallet.WalletService.createKeyPairsWithAddresses:output_type -> theweb3.wallet.CreateKeyPairsWithAddressesResponse
-	12, // 22: theweb3.wallet.WalletService.signTransactionMessage:output_type -> theweb3.wallet.SignTransactionMessageResponse
-	14, // 23: theweb3.wallet.WalletService.buildAndSignTransaction:output_type -> theweb3.wallet.BuildAndSignTransactionResponse
-	18, // 24: theweb3.wallet.WalletService.buildAndSignBatchTransaction:output_type -> theweb3.wallet.BuildAndSignBatchTransactionResponse
-	18, // [18:25] is the sub-list for method output_type
-	11, // [11:18] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	0,  // 11: theweb3.wallet.ListKeysResponse.code:type_name -> theweb3.wallet.ReturnCode
+	19, // 12: theweb3.wallet.ListKeysResponse.keys:type_name -> theweb3.wallet.KeyInfo
+	0,  // 13: theweb3.wallet.GetKeyResponse.code:type_name -> theweb3.wallet.ReturnCode
+	19, // 14: theweb3.wallet.GetKeyResponse.key:type_name -> theweb3.wallet.KeyInfo
+	0,  // 15: theweb3.wallet.SetKeyLabelResponse.code:type_name -> theweb3.wallet.ReturnCode
+	0,  // 16: theweb3.wallet.DisableKeyResponse.code:type_name -> theweb3.wallet.ReturnCode
+	0,  // 17: theweb3.wallet.DeleteKeyResponse.code:type_name -> theweb3.wallet.ReturnCode
+	0,  // 18: theweb3.wallet.ImportKeysResponse.code:type_name -> theweb3.wallet.ReturnCode
+	19, // 19: theweb3.wallet.ImportKeysResponse.keys:type_name -> theweb3.wallet.KeyInfo
+	0,  // 20: theweb3.wallet.ExportKeysResponse.code:type_name -> theweb3.wallet.ReturnCode
+	0,  // 21: theweb3.wallet.LimitUsageResponse.code:type_name -> theweb3.wallet.ReturnCode
+	35, // 22: theweb3.wallet.LimitUsageResponse.usages:type_name -> theweb3.wallet.LimitUsage
+	0,  // 23: theweb3.wallet.SubmitPendingTransactionResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 24: theweb3.wallet.SubmitPendingTransactionResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	0,  // 25: theweb3.wallet.ListPendingTransactionsResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 26: theweb3.wallet.ListPendingTransactionsResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	0,  // 27: theweb3.wallet.ListPendingApprovalsResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 28: theweb3.wallet.ListPendingApprovalsResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	0,  // 29: theweb3.wallet.ApprovePendingTransactionResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 30: theweb3.wallet.ApprovePendingTransactionResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	0,  // 31: theweb3.wallet.RejectPendingTransactionResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 32: theweb3.wallet.RejectPendingTransactionResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	0,  // 33: theweb3.wallet.ExpirePendingTransactionResponse.code:type_name -> theweb3.wallet.ReturnCode
+	38, // 34: theweb3.wallet.ExpirePendingTransactionResponse.pending:type_name -> theweb3.wallet.PendingTransaction
+	1,  // 35: theweb3.wallet.WalletService.getChainSignMethod:input_type -> theweb3.wallet.ChainSignMethodRequest
+	3,  // 36: theweb3.wallet.WalletService.getChainSchema:input_type -> theweb3.wallet.ChainSchemaRequest
+	6,  // 37: theweb3.wallet.WalletService.createKeyPairsExportPublicKeyList:input_type -> theweb3.wallet.CreateKeyPairAndExportPublicKeyRequest
+	9,  // 38: theweb3.wallet.WalletService.createKeyPairsWithAddresses:input_type -> theweb3.wallet.CreateKeyPairsWithAddressesRequest
+	11, // 39: theweb3.wallet.WalletService.signTransactionMessage:input_type -> theweb3.wallet.SignTransactionMessageRequest
+	13, // 40: theweb3.wallet.WalletService.buildAndSignTransaction:input_type -> theweb3.wallet.BuildAndSignTransactionRequest
+	17, // 41: theweb3.wallet.WalletService.buildAndSignBatchTransaction:input_type -> theweb3.wallet.BuildAndSignBatchTransactionRequest
+	20, // 42: theweb3.wallet.WalletService.listKeys:input_type -> theweb3.wallet.ListKeysRequest
+	22, // 43: theweb3.wallet.WalletService.getKey:input_type -> theweb3.wallet.GetKeyRequest
+	24, // 44: theweb3.wallet.WalletService.setKeyLabel:input_type -> theweb3.wallet.SetKeyLabelRequest
+	26, // 45: theweb3.wallet.WalletService.disableKey:input_type -> theweb3.wallet.DisableKeyRequest
+	28, // 46: theweb3.wallet.WalletService.deleteKey:input_type -> theweb3.wallet.DeleteKeyRequest
+	30, // 47: theweb3.wallet.WalletService.importKeys:input_type -> theweb3.wallet.ImportKeysRequest
+	32, // 48: theweb3.wallet.WalletService.exportKeys:input_type -> theweb3.wallet.ExportKeysRequest
+	34, // 49: theweb3.wallet.WalletService.getLimitUsage:input_type -> theweb3.wallet.LimitUsageRequest
+	37, // 50: theweb3.wallet.WalletService.submitPendingTransaction:input_type -> theweb3.wallet.SubmitPendingTransactionRequest
+	40, // 51: theweb3.wallet.WalletService.listPendingTransactions:input_type -> theweb3.wallet.ListPendingTransactionsRequest
+	42, // 52: theweb3.wallet.WalletService.listPendingApprovals:input_type -> theweb3.wallet.ListPendingApprovalsRequest
+	44, // 53: theweb3.wallet.WalletService.approvePendingTransaction:input_type -> theweb3.wallet.ApprovePendingTransactionRequest
+	46, // 54: theweb3.wallet.WalletService.rejectPendingTransaction:input_type -> theweb3.wallet.RejectPendingTransactionRequest
+	48, // 55: theweb3.wallet.WalletService.expirePendingTransaction:input_type -> theweb3.wallet.ExpirePendingTransactionRequest
+	2,  // 56: theweb3.wallet.WalletService.getChainSignMethod:output_type -> theweb3.wallet.ChainSignMethodResponse
+	4,  // 57: theweb3.wallet.WalletService.getChainSchema:output_type -> theweb3.wallet.ChainSchemaResponse
+	7,  // 58: theweb3.wallet.WalletService.createKeyPairsExportPublicKeyList:output_type -> theweb3.wallet.CreateKeyPairAndExportPublicKeyResponse
+	10, // 59: theweb3.wallet.WalletService.createKeyPairsWithAddresses:output_type -> theweb3.wallet.CreateKeyPairsWithAddressesResponse
+	12, // 60: theweb3.wallet.WalletService.signTransactionMessage:output_type -> theweb3.wallet.SignTransactionMessageResponse
+	14, // 61: theweb3.wallet.WalletService.buildAndSignTransaction:output_type -> theweb3.wallet.BuildAndSignTransactionResponse
+	18, // 62: theweb3.wallet.WalletService.buildAndSignBatchTransaction:output_type -> theweb3.wallet.BuildAndSignBatchTransactionResponse
+	21, // 63: theweb3.wallet.WalletService.listKeys:output_type -> theweb3.wallet.ListKeysResponse
+	23, // 64: theweb3.wallet.WalletService.getKey:output_type -> theweb3.wallet.GetKeyResponse
+	25, // 65: theweb3.wallet.WalletService.setKeyLabel:output_type -> theweb3.wallet.SetKeyLabelResponse
+	27, // 66: theweb3.wallet.WalletService.disableKey:output_type -> theweb3.wallet.DisableKeyResponse
+	29, // 67: theweb3.wallet.WalletService.deleteKey:output_type -> theweb3.wallet.DeleteKeyResponse
+	31, // 68: theweb3.wallet.WalletService.importKeys:output_type -> theweb3.wallet.ImportKeysResponse
+	33, // 69: theweb3.wallet.WalletService.exportKeys:output_type -> theweb3.wallet.ExportKeysResponse
+	36, // 70: theweb3.wallet.WalletService.getLimitUsage:output_type -> theweb3.wallet.LimitUsageResponse
+	39, // 71: theweb3.wallet.WalletService.submitPendingTransaction:output_type -> theweb3.wallet.SubmitPendingTransactionResponse
+	41, // 72: theweb3.wallet.WalletService.listPendingTransactions:output_type -> theweb3.wallet.ListPendingTransactionsResponse
+	43, // 73: theweb3.wallet.WalletService.listPendingApprovals:output_type -> theweb3.wallet.ListPendingApprovalsResponse
+	45, // 74: theweb3.wallet.WalletService.approvePendingTransaction:output_type -> theweb3.wallet.ApprovePendingTransactionResponse
+	47, // 75: theweb3.wallet.WalletService.rejectPendingTransaction:output_type -> theweb3.wallet.RejectPendingTransactionResponse
+	49, // 76: theweb3.wallet.WalletService.expirePendingTransaction:output_type -> theweb3.wallet.ExpirePendingTransactionResponse
+	56, // [56:77] is the sub-list for method output_type
+	35, // [35:56] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_wallet_proto_init() }
@@ -1458,7 +4157,7 @@ func file_protobuf_wallet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protobuf_wallet_proto_rawDesc), len(file_protobuf_wallet_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   18,
+			NumMessages:   49,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
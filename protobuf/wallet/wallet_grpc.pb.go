@@ -26,6 +26,20 @@ const (
 	WalletService_SignTransactionMessage_FullMethodName            = "/theweb3.wallet.WalletService/signTransactionMessage"
 	WalletService_BuildAndSignTransaction_FullMethodName           = "/theweb3.wallet.WalletService/buildAndSignTransaction"
 	WalletService_BuildAndSignBatchTransaction_FullMethodName      = "/theweb3.wallet.WalletService/buildAndSignBatchTransaction"
+	WalletService_ListKeys_FullMethodName                          = "/theweb3.wallet.WalletService/listKeys"
+	WalletService_GetKey_FullMethodName                            = "/theweb3.wallet.WalletService/getKey"
+	WalletService_SetKeyLabel_FullMethodName                       = "/theweb3.wallet.WalletService/setKeyLabel"
+	WalletService_DisableKey_FullMethodName                        = "/theweb3.wallet.WalletService/disableKey"
+	WalletService_DeleteKey_FullMethodName                         = "/theweb3.wallet.WalletService/deleteKey"
+	WalletService_ImportKeys_FullMethodName                        = "/theweb3.wallet.WalletService/importKeys"
+	WalletService_ExportKeys_FullMethodName                        = "/theweb3.wallet.WalletService/exportKeys"
+	WalletService_GetLimitUsage_FullMethodName                     = "/theweb3.wallet.WalletService/getLimitUsage"
+	WalletService_SubmitPendingTransaction_FullMethodName          = "/theweb3.wallet.WalletService/submitPendingTransaction"
+	WalletService_ListPendingTransactions_FullMethodName           = "/theweb3.wallet.WalletService/listPendingTransactions"
+	WalletService_ListPendingApprovals_FullMethodName              = "/theweb3.wallet.WalletService/listPendingApprovals"
+	WalletService_ApprovePendingTransaction_FullMethodName         = "/theweb3.wallet.WalletService/approvePendingTransaction"
+	WalletService_RejectPendingTransaction_FullMethodName          = "/theweb3.wallet.WalletService/rejectPendingTransaction"
+	WalletService_ExpirePendingTransaction_FullMethodName          = "/theweb3.wallet.WalletService/expirePendingTransaction"
 )
 
 // WalletServiceClient is the client API for WalletService service.
@@ -41,6 +55,24 @@ type WalletServiceClient interface {
 	// 完整签名的流程
 	BuildAndSignTransaction(ctx context.Context, in *BuildAndSignTransactionRequest, opts ...grpc.CallOption) (*BuildAndSignTransactionResponse, error)
 	BuildAndSignBatchTransaction(ctx context.Context, in *BuildAndSignBatchTransactionRequest, opts ...grpc.CallOption) (*BuildAndSignBatchTransactionResponse, error)
+	// 密钥生命周期管理
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error)
+	SetKeyLabel(ctx context.Context, in *SetKeyLabelRequest, opts ...grpc.CallOption) (*SetKeyLabelResponse, error)
+	DisableKey(ctx context.Context, in *DisableKeyRequest, opts ...grpc.CallOption) (*DisableKeyResponse, error)
+	DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error)
+	// 密钥导入与加密备份导出，需要管理员 token
+	ImportKeys(ctx context.Context, in *ImportKeysRequest, opts ...grpc.CallOption) (*ImportKeysResponse, error)
+	ExportKeys(ctx context.Context, in *ExportKeysRequest, opts ...grpc.CallOption) (*ExportKeysResponse, error)
+	// 滚动窗口限额的用量和剩余额度
+	GetLimitUsage(ctx context.Context, in *LimitUsageRequest, opts ...grpc.CallOption) (*LimitUsageResponse, error)
+	// 大额交易的多方审批：提交后等待 quorum 个审批人同意，达到人数后签名
+	SubmitPendingTransaction(ctx context.Context, in *SubmitPendingTransactionRequest, opts ...grpc.CallOption) (*SubmitPendingTransactionResponse, error)
+	ListPendingTransactions(ctx context.Context, in *ListPendingTransactionsRequest, opts ...grpc.CallOption) (*ListPendingTransactionsResponse, error)
+	ListPendingApprovals(ctx context.Context, in *ListPendingApprovalsRequest, opts ...grpc.CallOption) (*ListPendingApprovalsResponse, error)
+	ApprovePendingTransaction(ctx context.Context, in *ApprovePendingTransactionRequest, opts ...grpc.CallOption) (*ApprovePendingTransactionResponse, error)
+	RejectPendingTransaction(ctx context.Context, in *RejectPendingTransactionRequest, opts ...grpc.CallOption) (*RejectPendingTransactionResponse, error)
+	ExpirePendingTransaction(ctx context.Context, in *ExpirePendingTransactionRequest, opts ...grpc.CallOption) (*ExpirePendingTransactionResponse, error)
 }
 
 type walletServiceClient struct {
@@ -121,6 +153,146 @@ func (c *walletServiceClient) BuildAndSignBatchTransaction(ctx context.Context,
 	return out, nil
 }
 
+func (c *walletServiceClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, WalletService_ListKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*GetKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetKeyResponse)
+	err := c.cc.Invoke(ctx, WalletService_GetKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SetKeyLabel(ctx context.Context, in *SetKeyLabelRequest, opts ...grpc.CallOption) (*SetKeyLabelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetKeyLabelResponse)
+	err := c.cc.Invoke(ctx, WalletService_SetKeyLabel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) DisableKey(ctx context.Context, in *DisableKeyRequest, opts ...grpc.CallOption) (*DisableKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableKeyResponse)
+	err := c.cc.Invoke(ctx, WalletService_DisableKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) DeleteKey(ctx context.Context, in *DeleteKeyRequest, opts ...grpc.CallOption) (*DeleteKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteKeyResponse)
+	err := c.cc.Invoke(ctx, WalletService_DeleteKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ImportKeys(ctx context.Context, in *ImportKeysRequest, opts ...grpc.CallOption) (*ImportKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportKeysResponse)
+	err := c.cc.Invoke(ctx, WalletService_ImportKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ExportKeys(ctx context.Context, in *ExportKeysRequest, opts ...grpc.CallOption) (*ExportKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportKeysResponse)
+	err := c.cc.Invoke(ctx, WalletService_ExportKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) GetLimitUsage(ctx context.Context, in *LimitUsageRequest, opts ...grpc.CallOption) (*LimitUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LimitUsageResponse)
+	err := c.cc.Invoke(ctx, WalletService_GetLimitUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) SubmitPendingTransaction(ctx context.Context, in *SubmitPendingTransactionRequest, opts ...grpc.CallOption) (*SubmitPendingTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitPendingTransactionResponse)
+	err := c.cc.Invoke(ctx, WalletService_SubmitPendingTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListPendingTransactions(ctx context.Context, in *ListPendingTransactionsRequest, opts ...grpc.CallOption) (*ListPendingTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingTransactionsResponse)
+	err := c.cc.Invoke(ctx, WalletService_ListPendingTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ListPendingApprovals(ctx context.Context, in *ListPendingApprovalsRequest, opts ...grpc.CallOption) (*ListPendingApprovalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingApprovalsResponse)
+	err := c.cc.Invoke(ctx, WalletService_ListPendingApprovals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ApprovePendingTransaction(ctx context.Context, in *ApprovePendingTransactionRequest, opts ...grpc.CallOption) (*ApprovePendingTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApprovePendingTransactionResponse)
+	err := c.cc.Invoke(ctx, WalletService_ApprovePendingTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) RejectPendingTransaction(ctx context.Context, in *RejectPendingTransactionRequest, opts ...grpc.CallOption) (*RejectPendingTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectPendingTransactionResponse)
+	err := c.cc.Invoke(ctx, WalletService_RejectPendingTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletServiceClient) ExpirePendingTransaction(ctx context.Context, in *ExpirePendingTransactionRequest, opts ...grpc.CallOption) (*ExpirePendingTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExpirePendingTransactionResponse)
+	err := c.cc.Invoke(ctx, WalletService_ExpirePendingTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletServiceServer is the server API for WalletService service.
 // All implementations should embed UnimplementedWalletServiceServer
 // for forward compatibility.
@@ -134,6 +306,24 @@ type WalletServiceServer interface {
 	// 完整签名的流程
 	BuildAndSignTransaction(context.Context, *BuildAndSignTransactionRequest) (*BuildAndSignTransactionResponse, error)
 	BuildAndSignBatchTransaction(context.Context, *BuildAndSignBatchTransactionRequest) (*BuildAndSignBatchTransactionResponse, error)
+	// 密钥生命周期管理
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	GetKey(context.Context, *GetKeyRequest) (*GetKeyResponse, error)
+	SetKeyLabel(context.Context, *SetKeyLabelRequest) (*SetKeyLabelResponse, error)
+	DisableKey(context.Context, *DisableKeyRequest) (*DisableKeyResponse, error)
+	DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error)
+	// 密钥导入与加密备份导出，需要管理员 token
+	ImportKeys(context.Context, *ImportKeysRequest) (*ImportKeysResponse, error)
+	ExportKeys(context.Context, *ExportKeysRequest) (*ExportKeysResponse, error)
+	// 滚动窗口限额的用量和剩余额度
+	GetLimitUsage(context.Context, *LimitUsageRequest) (*LimitUsageResponse, error)
+	// 大额交易的多方审批：提交后等待 quorum 个审批人同意，达到人数后签名
+	SubmitPendingTransaction(context.Context, *SubmitPendingTransactionRequest) (*SubmitPendingTransactionResponse, error)
+	ListPendingTransactions(context.Context, *ListPendingTransactionsRequest) (*ListPendingTransactionsResponse, error)
+	ListPendingApprovals(context.Context, *ListPendingApprovalsRequest) (*ListPendingApprovalsResponse, error)
+	ApprovePendingTransaction(context.Context, *ApprovePendingTransactionRequest) (*ApprovePendingTransactionResponse, error)
+	RejectPendingTransaction(context.Context, *RejectPendingTransactionRequest) (*RejectPendingTransactionResponse, error)
+	ExpirePendingTransaction(context.Context, *ExpirePendingTransactionRequest) (*ExpirePendingTransactionResponse, error)
 }
 
 // UnimplementedWalletServiceServer should be embedded to have
@@ -164,6 +354,48 @@ func (UnimplementedWalletServiceServer) BuildAndSignTransaction(context.Context,
 func (UnimplementedWalletServiceServer) BuildAndSignBatchTransaction(context.Context, *BuildAndSignBatchTransactionRequest) (*BuildAndSignBatchTransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildAndSignBatchTransaction not implemented")
 }
+func (UnimplementedWalletServiceServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedWalletServiceServer) GetKey(context.Context, *GetKeyRequest) (*GetKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKey not implemented")
+}
+func (UnimplementedWalletServiceServer) SetKeyLabel(context.Context, *SetKeyLabelRequest) (*SetKeyLabelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetKeyLabel not implemented")
+}
+func (UnimplementedWalletServiceServer) DisableKey(context.Context, *DisableKeyRequest) (*DisableKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableKey not implemented")
+}
+func (UnimplementedWalletServiceServer) DeleteKey(context.Context, *DeleteKeyRequest) (*DeleteKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKey not implemented")
+}
+func (UnimplementedWalletServiceServer) ImportKeys(context.Context, *ImportKeysRequest) (*ImportKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportKeys not implemented")
+}
+func (UnimplementedWalletServiceServer) ExportKeys(context.Context, *ExportKeysRequest) (*ExportKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportKeys not implemented")
+}
+func (UnimplementedWalletServiceServer) GetLimitUsage(context.Context, *LimitUsageRequest) (*LimitUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLimitUsage not implemented")
+}
+func (UnimplementedWalletServiceServer) SubmitPendingTransaction(context.Context, *SubmitPendingTransactionRequest) (*SubmitPendingTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPendingTransaction not implemented")
+}
+func (UnimplementedWalletServiceServer) ListPendingTransactions(context.Context, *ListPendingTransactionsRequest) (*ListPendingTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingTransactions not implemented")
+}
+func (UnimplementedWalletServiceServer) ListPendingApprovals(context.Context, *ListPendingApprovalsRequest) (*ListPendingApprovalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingApprovals not implemented")
+}
+func (UnimplementedWalletServiceServer) ApprovePendingTransaction(context.Context, *ApprovePendingTransactionRequest) (*ApprovePendingTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApprovePendingTransaction not implemented")
+}
+func (UnimplementedWalletServiceServer) RejectPendingTransaction(context.Context, *RejectPendingTransactionRequest) (*RejectPendingTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectPendingTransaction not implemented")
+}
+func (UnimplementedWalletServiceServer) ExpirePendingTransaction(context.Context, *ExpirePendingTransactionRequest) (*ExpirePendingTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExpirePendingTransaction not implemented")
+}
 func (UnimplementedWalletServiceServer) testEmbeddedByValue() {}
 
 // UnsafeWalletServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -310,6 +542,258 @@ func _WalletService_BuildAndSignBatchTransaction_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletService_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ListKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_GetKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetKey(ctx, req.(*GetKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SetKeyLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetKeyLabelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).SetKeyLabel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_SetKeyLabel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).SetKeyLabel(ctx, req.(*SetKeyLabelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_DisableKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).DisableKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_DisableKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).DisableKey(ctx, req.(*DisableKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_DeleteKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).DeleteKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_DeleteKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).DeleteKey(ctx, req.(*DeleteKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ImportKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ImportKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ImportKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ImportKeys(ctx, req.(*ImportKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ExportKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ExportKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ExportKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ExportKeys(ctx, req.(*ExportKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_GetLimitUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LimitUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).GetLimitUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_GetLimitUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).GetLimitUsage(ctx, req.(*LimitUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_SubmitPendingTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitPendingTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).SubmitPendingTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_SubmitPendingTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).SubmitPendingTransaction(ctx, req.(*SubmitPendingTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListPendingTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListPendingTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ListPendingTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListPendingTransactions(ctx, req.(*ListPendingTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ListPendingApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingApprovalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ListPendingApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ListPendingApprovals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ListPendingApprovals(ctx, req.(*ListPendingApprovalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ApprovePendingTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovePendingTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ApprovePendingTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ApprovePendingTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ApprovePendingTransaction(ctx, req.(*ApprovePendingTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_RejectPendingTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectPendingTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).RejectPendingTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_RejectPendingTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).RejectPendingTransaction(ctx, req.(*RejectPendingTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletService_ExpirePendingTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpirePendingTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServiceServer).ExpirePendingTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WalletService_ExpirePendingTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServiceServer).ExpirePendingTransaction(ctx, req.(*ExpirePendingTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WalletService_ServiceDesc is the grpc.ServiceDesc for WalletService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -345,6 +829,62 @@ var WalletService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "buildAndSignBatchTransaction",
 			Handler:    _WalletService_BuildAndSignBatchTransaction_Handler,
 		},
+		{
+			MethodName: "listKeys",
+			Handler:    _WalletService_ListKeys_Handler,
+		},
+		{
+			MethodName: "getKey",
+			Handler:    _WalletService_GetKey_Handler,
+		},
+		{
+			MethodName: "setKeyLabel",
+			Handler:    _WalletService_SetKeyLabel_Handler,
+		},
+		{
+			MethodName: "disableKey",
+			Handler:    _WalletService_DisableKey_Handler,
+		},
+		{
+			MethodName: "deleteKey",
+			Handler:    _WalletService_DeleteKey_Handler,
+		},
+		{
+			MethodName: "importKeys",
+			Handler:    _WalletService_ImportKeys_Handler,
+		},
+		{
+			MethodName: "exportKeys",
+			Handler:    _WalletService_ExportKeys_Handler,
+		},
+		{
+			MethodName: "getLimitUsage",
+			Handler:    _WalletService_GetLimitUsage_Handler,
+		},
+		{
+			MethodName: "submitPendingTransaction",
+			Handler:    _WalletService_SubmitPendingTransaction_Handler,
+		},
+		{
+			MethodName: "listPendingTransactions",
+			Handler:    _WalletService_ListPendingTransactions_Handler,
+		},
+		{
+			MethodName: "listPendingApprovals",
+			Handler:    _WalletService_ListPendingApprovals_Handler,
+		},
+		{
+			MethodName: "approvePendingTransaction",
+			Handler:    _WalletService_ApprovePendingTransaction_Handler,
+		},
+		{
+			MethodName: "rejectPendingTransaction",
+			Handler:    _WalletService_RejectPendingTransaction_Handler,
+		},
+		{
+			MethodName: "expirePendingTransaction",
+			Handler:    _WalletService_ExpirePendingTransaction_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "protobuf/wallet.proto",
@@ -0,0 +1,271 @@
+// Package quorum 对超过策略阈值的交易执行 K-of-N 多方审批。
+//
+// 调用方提交的交易保存为待审批记录，审批人对 intent hash 签名同意或拒绝，
+// 达到法定人数后由 dispatcher 签名并把结果写回记录。所有状态保存在 LevelDB 中，重启后继续有效
+package quorum
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+var (
+	ErrNotPending       = errors.New("transaction is not pending")
+	ErrAlreadyApproved  = errors.New("approver already approved")
+	ErrNotOwner         = errors.New("transaction belongs to another consumer")
+	ErrInvalidSignature = errors.New("invalid approver signature")
+)
+
+// rejectDomain 拒绝签名的前缀，避免同意的签名被当作拒绝使用
+const rejectDomain = "reject:"
+
+// IntentHash 审批人签名的对象：sha256(chain \0 network \0 public key \0 交易请求体)
+func IntentHash(chainName string, network string, publicKey string, body []byte) []byte {
+	h := sha256.New()
+	for _, field := range []string{chainName, network, publicKey} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// RejectMessage 审批人拒绝时签名的内容
+func RejectMessage(intentHash []byte) []byte {
+	return append([]byte(rejectDomain), intentHash...)
+}
+
+type Workflow struct {
+	db        *leveldb.Keys
+	approvers *riskkey.Verifier
+	quorum    int
+	ttl       time.Duration
+
+	// 保护记录的读-改-写；signing 为正在签名的记录，防止并发请求重复签名
+	mu      sync.Mutex
+	signing map[string]bool
+	now     func() time.Time
+}
+
+// NewWorkflow approvers 为 N 个审批人的公钥，quorum 为 K
+func NewWorkflow(db *leveldb.Keys, approvers []*riskkey.Key, quorum int, ttl time.Duration) (*Workflow, error) {
+	if quorum <= 0 || quorum > len(approvers) {
+		return nil, fmt.Errorf("quorum %d must be between 1 and the number of approvers (%d)", quorum, len(approvers))
+	}
+	if ttl <= 0 {
+		return nil, errors.New("approval ttl must be positive")
+	}
+	for _, key := range approvers {
+		if key.Type == riskkey.TypeHmacSha256 {
+			return nil, fmt.Errorf("approver %s: approvers must use ed25519 or secp256k1 keys", key.ID)
+		}
+	}
+	verifier, err := riskkey.NewVerifier("approver", approvers)
+	if err != nil {
+		return nil, err
+	}
+	return &Workflow{
+		db:        db,
+		approvers: verifier,
+		quorum:    quorum,
+		ttl:       ttl,
+		signing:   make(map[string]bool),
+		now:       time.Now,
+	}, nil
+}
+
+func (w *Workflow) Quorum() int {
+	return w.quorum
+}
+
+// newID 时间在前，按 id 排序即为提交顺序
+func newID(now time.Time) (string, error) {
+	id := binary.BigEndian.AppendUint64(nil, uint64(now.UnixNano()))
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(append(id, random...)), nil
+}
+
+// Submit 保存待审批交易，tx 的 Id、IntentHash、状态和时间由 Submit 填写
+func (w *Workflow) Submit(tx *leveldb.PendingTransaction, body []byte) error {
+	now := w.now()
+	id, err := newID(now)
+	if err != nil {
+		return err
+	}
+	tx.Id = id
+	tx.IntentHash = hex.EncodeToString(IntentHash(tx.ChainName, tx.Network, tx.PublicKey, body))
+	tx.Status = leveldb.PendingStatusPending
+	tx.Approvals = make(map[string]string)
+	tx.CreatedAt = now.Unix()
+	tx.ExpiresAt = now.Add(w.ttl).Unix()
+	return w.db.PutPendingTransaction(tx)
+}
+
+// load 读取记录，超时未达到法定人数的记录转为 expired
+func (w *Workflow) load(id string) (*leveldb.PendingTransaction, error) {
+	tx, err := w.db.GetPendingTransaction(id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status == leveldb.PendingStatusPending && w.now().Unix() >= tx.ExpiresAt {
+		tx.Status = leveldb.PendingStatusExpired
+		if err := w.db.PutPendingTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+func (w *Workflow) Get(id string) (*leveldb.PendingTransaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.load(id)
+}
+
+// List 返回调用方的记录，status 为空时返回所有状态
+func (w *Workflow) List(consumerName string, chainName string, status string) ([]*leveldb.PendingTransaction, error) {
+	return w.list(func(tx *leveldb.PendingTransaction) bool {
+		return tx.Consumer == consumerName && (chainName == "" || tx.ChainName == chainName)
+	}, status)
+}
+
+// ListForApproval 返回所有调用方在 chainName 上的记录，供审批人核对交易内容
+func (w *Workflow) ListForApproval(chainName string, status string) ([]*leveldb.PendingTransaction, error) {
+	return w.list(func(tx *leveldb.PendingTransaction) bool {
+		return chainName == "" || tx.ChainName == chainName
+	}, status)
+}
+
+func (w *Workflow) list(match func(tx *leveldb.PendingTransaction) bool, status string) ([]*leveldb.PendingTransaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	all, err := w.db.ListPendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	var txs []*leveldb.PendingTransaction
+	for _, tx := range all {
+		if !match(tx) {
+			continue
+		}
+		if tx, err = w.load(tx.Id); err != nil {
+			return nil, err
+		}
+		if status == "" || tx.Status == status {
+			txs = append(txs, tx)
+		}
+	}
+	return txs, nil
+}
+
+func (w *Workflow) verify(approverID string, signature string, message []byte) error {
+	if approverID == "" {
+		return fmt.Errorf("%w: approver id is empty", ErrInvalidSignature)
+	}
+	if _, err := w.approvers.Verify(message, approverID+":"+signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// Approve 记录审批人的同意签名。返回的 release 为 true 时调用方必须签名，
+// 并用 Finish 写回结果；同一记录同时只有一个调用方拿到 release
+func (w *Workflow) Approve(id string, approverID string, signature string) (*leveldb.PendingTransaction, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, err := w.load(id)
+	if err != nil {
+		return nil, false, err
+	}
+	if tx.Status != leveldb.PendingStatusPending && tx.Status != leveldb.PendingStatusApproved {
+		return nil, false, fmt.Errorf("%w: %s", ErrNotPending, tx.Status)
+	}
+	// 已达到法定人数的记录同样要求有效的审批签名，否则任何调用方都能触发签名
+	intentHash, _ := hex.DecodeString(tx.IntentHash)
+	if err := w.verify(approverID, signature, intentHash); err != nil {
+		return nil, false, err
+	}
+	if tx.Status == leveldb.PendingStatusPending {
+		if _, ok := tx.Approvals[approverID]; ok {
+			return nil, false, ErrAlreadyApproved
+		}
+		tx.Approvals[approverID] = signature
+		if len(tx.Approvals) >= w.quorum {
+			tx.Status = leveldb.PendingStatusApproved
+		}
+		if err := w.db.PutPendingTransaction(tx); err != nil {
+			return nil, false, err
+		}
+	}
+	// 已达到法定人数但还没有签名（包括签名前进程重启的记录）
+	if tx.Status == leveldb.PendingStatusApproved && !w.signing[id] {
+		w.signing[id] = true
+		return tx, true, nil
+	}
+	return tx, false, nil
+}
+
+// Finish 写回签名结果，signErr 不为 nil 时记录转为 failed
+func (w *Workflow) Finish(tx *leveldb.PendingTransaction, signErr error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.signing, tx.Id)
+	if signErr != nil {
+		tx.Status = leveldb.PendingStatusFailed
+		tx.Reason = signErr.Error()
+	} else {
+		tx.Status = leveldb.PendingStatusSigned
+	}
+	return w.db.PutPendingTransaction(tx)
+}
+
+// Reject 审批人对 RejectMessage(intent hash) 签名拒绝，任何一个审批人拒绝即终止
+func (w *Workflow) Reject(id string, approverID string, signature string, reason string) (*leveldb.PendingTransaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, err := w.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status != leveldb.PendingStatusPending {
+		return nil, fmt.Errorf("%w: %s", ErrNotPending, tx.Status)
+	}
+	intentHash, _ := hex.DecodeString(tx.IntentHash)
+	if err := w.verify(approverID, signature, RejectMessage(intentHash)); err != nil {
+		return nil, err
+	}
+	tx.Status = leveldb.PendingStatusRejected
+	tx.RejectedBy = approverID
+	tx.Reason = reason
+	return tx, w.db.PutPendingTransaction(tx)
+}
+
+// Expire 提交方撤回还在等待审批的交易
+func (w *Workflow) Expire(id string, consumerName string) (*leveldb.PendingTransaction, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	tx, err := w.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Consumer != consumerName {
+		return nil, ErrNotOwner
+	}
+	if tx.Status != leveldb.PendingStatusPending {
+		return nil, fmt.Errorf("%w: %s", ErrNotPending, tx.Status)
+	}
+	tx.Status = leveldb.PendingStatusExpired
+	return tx, w.db.PutPendingTransaction(tx)
+}
@@ -0,0 +1,152 @@
+package quorum
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/riskkey"
+)
+
+type approver struct {
+	id  string
+	key ed25519.PrivateKey
+}
+
+func (a approver) sign(tx *leveldb.PendingTransaction) string {
+	hash, _ := hex.DecodeString(tx.IntentHash)
+	return hex.EncodeToString(ed25519.Sign(a.key, hash))
+}
+
+func (a approver) reject(tx *leveldb.PendingTransaction) string {
+	hash, _ := hex.DecodeString(tx.IntentHash)
+	return hex.EncodeToString(ed25519.Sign(a.key, RejectMessage(hash)))
+}
+
+func newApprovers(t *testing.T, ids ...string) ([]approver, []*riskkey.Key) {
+	var approvers []approver
+	var keys []*riskkey.Key
+	for _, id := range ids {
+		pub, pri, _ := ed25519.GenerateKey(rand.Reader)
+		key, err := riskkey.NewKey(id, riskkey.TypeEd25519, nil, hex.EncodeToString(pub))
+		if err != nil {
+			t.Fatal(err)
+		}
+		approvers = append(approvers, approver{id: id, key: pri})
+		keys = append(keys, key)
+	}
+	return approvers, keys
+}
+
+func TestWorkflow(t *testing.T) {
+	dir := t.TempDir()
+	db, err := leveldb.NewKeyStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approvers, keys := newApprovers(t, "alice", "bob", "carol")
+	workflow, err := NewWorkflow(db, keys, 2, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	workflow.now = func() time.Time { return now }
+	submit := func() *leveldb.PendingTransaction {
+		tx := &leveldb.PendingTransaction{Consumer: "exchange", ChainName: "Ethereum", Network: "mainnet", PublicKey: "04ab", TxBase64Body: "e30="}
+		if err := workflow.Submit(tx, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	tx := submit()
+	if _, _, err := workflow.Approve(tx.Id, "alice", approvers[1].sign(tx)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("signature of another approver: err = %v", err)
+	}
+	if _, release, err := workflow.Approve(tx.Id, "alice", approvers[0].sign(tx)); err != nil || release {
+		t.Fatalf("first approval: release %v, err %v", release, err)
+	}
+	if _, _, err := workflow.Approve(tx.Id, "alice", approvers[0].sign(tx)); !errors.Is(err, ErrAlreadyApproved) {
+		t.Fatalf("duplicate approval: err = %v", err)
+	}
+
+	// 重启后审批记录仍然有效
+	db.Close()
+	if db, err = leveldb.NewKeyStore(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	workflow, _ = NewWorkflow(db, keys, 2, time.Hour)
+	workflow.now = func() time.Time { return now }
+
+	approved, release, err := workflow.Approve(tx.Id, "bob", approvers[1].sign(tx))
+	if err != nil || !release || approved.Status != leveldb.PendingStatusApproved {
+		t.Fatalf("quorum approval: tx %+v, release %v, err %v", approved, release, err)
+	}
+	// 已达到法定人数的记录同样验证审批签名
+	if _, _, err := workflow.Approve(tx.Id, "carol", "00"); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("unsigned approval of an approved tx: err = %v", err)
+	}
+	if _, _, err := workflow.Approve(tx.Id, "", ""); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("empty approval of an approved tx: err = %v", err)
+	}
+	// 签名过程中再次审批不会重复释放
+	if _, release, err := workflow.Approve(tx.Id, "carol", approvers[2].sign(tx)); err != nil || release {
+		t.Fatalf("approval while signing: release %v, err %v", release, err)
+	}
+	approved.SignedTx = "0xsigned"
+	if err := workflow.Finish(approved, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := workflow.Get(tx.Id); got.Status != leveldb.PendingStatusSigned || got.SignedTx != "0xsigned" {
+		t.Fatalf("signed tx = %+v", got)
+	}
+
+	// 同意的签名不能用来拒绝
+	rejected := submit()
+	if _, err := workflow.Reject(rejected.Id, "carol", approvers[2].sign(rejected), "no"); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("approval used as rejection: err = %v", err)
+	}
+	if _, err := workflow.Reject(rejected.Id, "carol", approvers[2].reject(rejected), "unknown destination"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := workflow.Approve(rejected.Id, "alice", approvers[0].sign(rejected)); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("approve rejected: err = %v", err)
+	}
+
+	expired := submit()
+	now = now.Add(time.Hour)
+	if _, _, err := workflow.Approve(expired.Id, "alice", approvers[0].sign(expired)); !errors.Is(err, ErrNotPending) {
+		t.Fatalf("approve expired: err = %v", err)
+	}
+	withdrawn := submit()
+	if _, err := workflow.Expire(withdrawn.Id, "payroll"); !errors.Is(err, ErrNotOwner) {
+		t.Fatalf("expire by another consumer: err = %v", err)
+	}
+	if _, err := workflow.Expire(withdrawn.Id, "exchange"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := workflow.List("exchange", "Ethereum", leveldb.PendingStatusExpired)
+	if err != nil || len(txs) != 2 || txs[0].Id != expired.Id || txs[1].Id != withdrawn.Id {
+		t.Fatalf("expired list = %v, err %v", txs, err)
+	}
+	if txs, _ := workflow.List("payroll", "", ""); len(txs) != 0 {
+		t.Fatalf("other consumer sees %d transactions", len(txs))
+	}
+	// 审批人看到所有调用方的记录
+	if txs, err := workflow.ListForApproval("Ethereum", ""); err != nil || len(txs) != 4 || txs[0].TxBase64Body == "" {
+		t.Fatalf("approval list = %v, err %v", txs, err)
+	}
+	if txs, _ := workflow.ListForApproval("Solana", ""); len(txs) != 0 {
+		t.Fatalf("approval list of another chain = %v", txs)
+	}
+
+	if _, err := NewWorkflow(db, keys, 4, time.Hour); err == nil {
+		t.Fatal("expected quorum above the number of approvers to be rejected")
+	}
+}
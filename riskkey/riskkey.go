@@ -0,0 +1,146 @@
+// Package riskkey 校验钱包后端和风控系统对交易请求的审批。
+//
+// 审批是对 base64 解码后的交易请求体的 HMAC-SHA256 或签名，格式为 <key id>:<hex>，
+// 不带 key id 时依次尝试所有密钥。轮换期间同一角色可以同时配置多个密钥版本。
+// secp256k1 签名的是请求体的 sha256，为 64 字节 r || s（可以带第 65 字节的恢复 id）
+package riskkey
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	TypeHmacSha256 = "hmac-sha256"
+	TypeEd25519    = "ed25519"
+	TypeSecp256k1  = "secp256k1"
+)
+
+var (
+	ErrNoKeys       = errors.New("no approval keys configured")
+	ErrUnknownKey   = errors.New("unknown approval key")
+	ErrInvalidProof = errors.New("invalid approval")
+)
+
+// Key 一个审批密钥版本，HMAC 保存共享密钥，签名类型保存公钥
+type Key struct {
+	ID     string
+	Type   string
+	secret []byte
+	pubKey []byte
+}
+
+// NewKey HMAC 使用 secret，ed25519 和 secp256k1 使用 hex 编码的公钥
+func NewKey(id string, keyType string, secret []byte, publicKey string) (*Key, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("invalid approval key id %q", id)
+	}
+	key := &Key{ID: id, Type: keyType}
+	switch keyType {
+	case TypeHmacSha256:
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("approval key %s: hmac secret must be at least 32 bytes", id)
+		}
+		key.secret = secret
+		return key, nil
+	case TypeEd25519, TypeSecp256k1:
+	default:
+		return nil, fmt.Errorf("approval key %s: unsupported type %q", id, keyType)
+	}
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(publicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("approval key %s: %w", id, err)
+	}
+	if keyType == TypeEd25519 && len(pubKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("approval key %s: ed25519 public key must be 32 bytes", id)
+	}
+	if keyType == TypeSecp256k1 {
+		parsed, err := crypto.DecompressPubkey(pubKey)
+		if err != nil {
+			if parsed, err = crypto.UnmarshalPubkey(pubKey); err != nil {
+				return nil, fmt.Errorf("approval key %s: invalid secp256k1 public key", id)
+			}
+		}
+		pubKey = crypto.CompressPubkey(parsed)
+	}
+	key.pubKey = pubKey
+	return key, nil
+}
+
+func (k *Key) verify(body []byte, proof []byte) bool {
+	switch k.Type {
+	case TypeHmacSha256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(body)
+		return hmac.Equal(mac.Sum(nil), proof)
+	case TypeEd25519:
+		return len(proof) == ed25519.SignatureSize && ed25519.Verify(k.pubKey, body, proof)
+	case TypeSecp256k1:
+		if len(proof) == 65 {
+			proof = proof[:64]
+		}
+		digest := sha256.Sum256(body)
+		return len(proof) == 64 && crypto.VerifySignature(k.pubKey, digest[:], proof)
+	}
+	return false
+}
+
+// Verifier 一个审批角色（wallet 或 risk）当前有效的所有密钥版本
+type Verifier struct {
+	role string
+	keys map[string]*Key
+	// 配置顺序，不带 key id 的审批按此顺序尝试
+	order []*Key
+}
+
+func NewVerifier(role string, keys []*Key) (*Verifier, error) {
+	v := &Verifier{role: role, keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := v.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate %s approval key %s", role, key.ID)
+		}
+		v.keys[key.ID] = key
+		v.order = append(v.order, key)
+	}
+	return v, nil
+}
+
+// Verify 校验 body 的审批，返回通过校验的 key id
+func (v *Verifier) Verify(body []byte, approval string) (string, error) {
+	if len(v.order) == 0 {
+		return "", fmt.Errorf("%s: %w", v.role, ErrNoKeys)
+	}
+	candidates := v.order
+	encoded := approval
+	if id, rest, ok := strings.Cut(approval, ":"); ok {
+		key, found := v.keys[id]
+		if !found {
+			return "", fmt.Errorf("%s: %w %s", v.role, ErrUnknownKey, id)
+		}
+		candidates, encoded = []*Key{key}, rest
+	}
+	proof, err := hex.DecodeString(strings.TrimPrefix(encoded, "0x"))
+	if err != nil || len(proof) == 0 {
+		return "", fmt.Errorf("%s: %w", v.role, ErrInvalidProof)
+	}
+	for _, key := range candidates {
+		if key.verify(body, proof) {
+			return key.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%s: %w", v.role, ErrInvalidProof)
+}
+
+// HmacApproval 生成 HMAC-SHA256 审批，供调用方和测试使用
+func HmacApproval(id string, secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return id + ":" + hex.EncodeToString(mac.Sum(nil))
+}
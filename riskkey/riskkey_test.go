@@ -0,0 +1,76 @@
+package riskkey
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifier(t *testing.T) {
+	body := []byte(`{"chain_id":"1","nonce":1}`)
+	oldSecret, newSecret := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	oldKey, err := NewKey("2025-01", TypeHmacSha256, oldSecret, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, _ := NewKey("2025-07", TypeHmacSha256, newSecret, "")
+	edPub, edPri, _ := ed25519.GenerateKey(rand.Reader)
+	edKey, err := NewKey("ed", TypeEd25519, nil, hex.EncodeToString(edPub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPri, _ := crypto.GenerateKey()
+	ecKey, err := NewKey("ec", TypeSecp256k1, nil, hex.EncodeToString(crypto.FromECDSAPub(&ecPri.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := NewVerifier("risk", []*Key{oldKey, newKey, edKey, ecKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := sha256.Sum256(body)
+	ecSig, _ := crypto.Sign(digest[:], ecPri)
+	approvals := map[string]string{
+		// 轮换期间新旧两个版本都有效
+		HmacApproval("2025-01", oldSecret, body):              "2025-01",
+		HmacApproval("2025-07", newSecret, body):              "2025-07",
+		"ed:" + hex.EncodeToString(ed25519.Sign(edPri, body)): "ed",
+		"ec:" + hex.EncodeToString(ecSig):                     "ec",
+		// 不带 key id 时尝试所有密钥
+		HmacApproval("2025-07", newSecret, body)[len("2025-07:"):]: "2025-07",
+	}
+	for approval, want := range approvals {
+		got, err := verifier.Verify(body, approval)
+		if err != nil || got != want {
+			t.Fatalf("%s: got %s, err %v", approval, got, err)
+		}
+	}
+
+	tampered := append([]byte(nil), body...)
+	tampered[2] ^= 1
+	if _, err := verifier.Verify(tampered, HmacApproval("2025-07", newSecret, body)); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("tampered body: err = %v", err)
+	}
+	if _, err := verifier.Verify(body, HmacApproval("2024-01", oldSecret, body)); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("retired key: err = %v", err)
+	}
+	// 旧版本的 Keccak256(body || key) 不再被接受
+	legacy := hex.EncodeToString(crypto.Keccak256(append(body, []byte("risk key 111")...)))
+	if _, err := verifier.Verify(body, legacy); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("legacy hash: err = %v", err)
+	}
+	empty, _ := NewVerifier("wallet", nil)
+	if _, err := empty.Verify(body, HmacApproval("2025-07", newSecret, body)); !errors.Is(err, ErrNoKeys) {
+		t.Fatalf("no keys: err = %v", err)
+	}
+	if _, err := NewKey("short", TypeHmacSha256, []byte("risk key 111"), ""); err == nil {
+		t.Fatal("expected a short hmac secret to be rejected")
+	}
+}
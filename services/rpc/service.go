@@ -2,32 +2,45 @@ package rpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/0xshin-chan/wallet-sign/chaindispatcher"
 	"github.com/0xshin-chan/wallet-sign/config"
-	"github.com/0xshin-chan/wallet-sign/hsm"
+	"github.com/0xshin-chan/wallet-sign/metrics"
 	"github.com/0xshin-chan/wallet-sign/protobuf/wallet"
+	"github.com/0xshin-chan/wallet-sign/tracing"
 )
 
 const MaxReceivedMessageSize = 1024 * 1024 * 30000
 
 type RpcService struct {
-	conf      *config.Config
-	HsmClient *hsm.HsmClient
+	conf *config.Config
 	wallet.UnimplementedWalletServiceServer
 	stopped atomic.Bool
+	metrics *http.Server
+	// 发送剩余的 span 并关闭导出器
+	shutdownTracing func(context.Context) error
 }
 
 func (s *RpcService) Stop(ctx context.Context) error {
 	s.stopped.Store(true)
-	return nil
+	var errs []error
+	if s.metrics != nil {
+		errs = append(errs, s.metrics.Shutdown(ctx))
+	}
+	if s.shutdownTracing != nil {
+		errs = append(errs, s.shutdownTracing(ctx))
+	}
+	return errors.Join(errs...)
 }
 
 func (s *RpcService) Stopped() bool {
@@ -38,32 +51,44 @@ func NewRpcService(config *config.Config) (*RpcService, error) {
 	rpcService := &RpcService{
 		conf: config,
 	}
-	var hsmCli *hsm.HsmClient
-	var hsmErr error
-	if config.HsmEnabled {
-		hsmCli, hsmErr = hsm.NewHSMClient(context.Background(), config.KeyPath, config.KeyName)
-		if hsmErr != nil {
-			log.Error("new hsm client fail", "hsmErr", hsmErr)
-			return nil, hsmErr
-		}
-		rpcService.HsmClient = hsmCli
-	}
 	return rpcService, nil
 }
 
 func (s *RpcService) Start(ctx context.Context) error {
+	shutdownTracing, err := tracing.Setup(ctx, s.conf.Tracing)
+	if err != nil {
+		log.Error("setup tracing fail", "err", err)
+		return err
+	}
+	s.shutdownTracing = shutdownTracing
+	dispatcher, err := chaindispatcher.NewChainDispatcher(s.conf)
+	if err != nil {
+		log.Error("new chain dispatcher fail", "err", err)
+		return err
+	}
+	tlsConf, err := newServerTLSConfig(s.conf.RpcServer.Tls)
+	if err != nil {
+		log.Error("load tls config fail", "err", err)
+		return err
+	}
+	if s.conf.MetricsServer.Port > 0 {
+		s.startMetrics()
+	}
 	go func(s *RpcService) {
 		addr := fmt.Sprintf("%s:%d", s.conf.RpcServer.Host, s.conf.RpcServer.Port)
 		log.Info("start rpc service", "addr:", addr)
 
-		opt := grpc.MaxRecvMsgSize(MaxReceivedMessageSize)
-
-		dispatcher, _ := chaindispatcher.NewChainDispatcher(s.conf)
-
-		gs := grpc.NewServer(
-			opt,
+		opts := []grpc.ServerOption{
+			grpc.MaxRecvMsgSize(MaxReceivedMessageSize),
 			grpc.ChainUnaryInterceptor(dispatcher.Interceptor),
-		)
+		}
+		if tlsConf != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+		} else {
+			log.Warn("rpc service has no tls config, requests are sent in plaintext")
+		}
+
+		gs := grpc.NewServer(opts...)
 
 		defer gs.GracefulStop()
 
@@ -83,3 +108,15 @@ func (s *RpcService) Start(ctx context.Context) error {
 	}(s)
 	return nil
 }
+
+// startMetrics 启动输出 /metrics 的 HTTP 服务
+func (s *RpcService) startMetrics() {
+	addr := fmt.Sprintf("%s:%d", s.conf.MetricsServer.Host, s.conf.MetricsServer.Port)
+	s.metrics = metrics.NewServer(addr)
+	go func(server *http.Server) {
+		log.Info("start metrics server", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("metrics server fail", "err", err)
+		}
+	}(s.metrics)
+}
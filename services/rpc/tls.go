@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+)
+
+// 两次检查证书文件的最小间隔
+const certCheckInterval = time.Second
+
+// certReloader 在握手时检查证书文件的修改时间，文件变化后重新加载。
+// 加载失败时继续使用旧证书，避免证书轮换过程中写了一半的文件导致服务不可用
+type certReloader struct {
+	conf config.TlsConfig
+
+	mu        sync.Mutex
+	tlsConf   *tls.Config
+	modTimes  []time.Time
+	lastCheck time.Time
+}
+
+// newServerTLSConfig 返回 gRPC 服务使用的 tls.Config，cert_file 为空时返回 nil
+func newServerTLSConfig(conf config.TlsConfig) (*tls.Config, error) {
+	if conf.CertFile == "" {
+		if conf.RequireClientCert || conf.ClientCaFile != "" {
+			return nil, errors.New("tls: client certificates need cert_file and key_file")
+		}
+		return nil, nil
+	}
+	if conf.RequireClientCert && conf.ClientCaFile == "" {
+		return nil, errors.New("tls: require_client_cert needs client_ca_file")
+	}
+	r := &certReloader{conf: conf}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.conf.CertFile, r.conf.KeyFile}
+	if r.conf.ClientCaFile != "" {
+		files = append(files, r.conf.ClientCaFile)
+	}
+	return files
+}
+
+func (r *certReloader) load() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.conf.CertFile, r.conf.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load certificate: %w", err)
+	}
+	tlsConf := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.NoClientCert,
+	}
+	if r.conf.ClientCaFile != "" {
+		data, err := os.ReadFile(r.conf.ClientCaFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("tls: no certificate found in %s", r.conf.ClientCaFile)
+		}
+		tlsConf.ClientCAs = pool
+		tlsConf.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.conf.RequireClientCert {
+			tlsConf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	r.tlsConf, r.modTimes = tlsConf, modTimes
+	return nil
+}
+
+func (r *certReloader) stat() ([]time.Time, error) {
+	var modTimes []time.Time
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+	return modTimes, nil
+}
+
+func (r *certReloader) changed() bool {
+	modTimes, err := r.stat()
+	if err != nil {
+		log.Warn("stat tls files fail", "err", err)
+		return false
+	}
+	for i := range modTimes {
+		if !modTimes[i].Equal(r.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now := time.Now(); now.Sub(r.lastCheck) >= certCheckInterval {
+		r.lastCheck = now
+		if r.changed() {
+			if err := r.load(); err != nil {
+				log.Error("reload tls certificate fail, keep the previous one", "err", err)
+			} else {
+				log.Info("tls certificate reloaded", "cert", r.conf.CertFile)
+			}
+		}
+	}
+	return r.tlsConf, nil
+}
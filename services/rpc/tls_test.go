@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+func issueCert(t *testing.T, cn string, serial int64, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"Acme"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA, template.BasicConstraintsValid = true, true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{cert: cert, key: key, tls: tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}}
+}
+
+func writeCert(t *testing.T, c *testCert, certFile, keyFile string) {
+	t.Helper()
+	keyDer, _ := x509.MarshalECPrivateKey(c.key)
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if keyFile != "" {
+		if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// handshake 返回服务端证书的序列号和服务端看到的客户端证书
+func handshake(t *testing.T, serverConf *tls.Config, clientConf *tls.Config) (int64, []*x509.Certificate, error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	type result struct {
+		conn *tls.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		server := tls.Server(conn, serverConf)
+		err = server.Handshake()
+		if err == nil {
+			_, err = server.Read(make([]byte, 1))
+		}
+		done <- result{server, err}
+	}()
+	client, err := tls.Dial("tcp", listener.Addr().String(), clientConf)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer client.Close()
+	// TLS 1.3 的客户端证书在客户端握手完成后才被服务端校验，写一个字节等待服务端的结果
+	client.Write([]byte{1})
+	res := <-done
+	if res.err != nil {
+		return 0, nil, res.err
+	}
+	server := res.conn
+	defer server.Close()
+	state := server.ConnectionState()
+	var verified []*x509.Certificate
+	if len(state.VerifiedChains) > 0 {
+		verified = state.VerifiedChains[0]
+	}
+	return client.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), verified, nil
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := issueCert(t, "wallet-sign ca", 1, nil)
+	conf := config.TlsConfig{
+		CertFile:          filepath.Join(dir, "server.crt"),
+		KeyFile:           filepath.Join(dir, "server.key"),
+		ClientCaFile:      filepath.Join(dir, "ca.crt"),
+		RequireClientCert: true,
+	}
+	writeCert(t, ca, conf.ClientCaFile, "")
+	writeCert(t, issueCert(t, "localhost", 2, ca), conf.CertFile, conf.KeyFile)
+
+	serverConf, err := newServerTLSConfig(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client := issueCert(t, "exchange", 3, ca)
+	clientConf := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{client.tls}}
+
+	serial, chain, err := handshake(t, serverConf, clientConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial != 2 || len(chain) == 0 || chain[0].Subject.String() != "CN=exchange,O=Acme" {
+		t.Fatalf("serial %d, client chain %v", serial, chain)
+	}
+	if _, _, err := handshake(t, serverConf, &tls.Config{RootCAs: roots, ServerName: "localhost"}); err == nil {
+		t.Fatal("expected a client without certificate to be rejected")
+	}
+
+	// 证书文件更新后，下一次握手使用新证书
+	writeCert(t, issueCert(t, "localhost", 4, ca), conf.CertFile, conf.KeyFile)
+	future := time.Now().Add(time.Minute)
+	os.Chtimes(conf.CertFile, future, future)
+	os.Chtimes(conf.KeyFile, future, future)
+	time.Sleep(certCheckInterval)
+	if serial, _, err := handshake(t, serverConf, clientConf); err != nil || serial != 4 {
+		t.Fatalf("after reload: serial %d, err %v", serial, err)
+	}
+
+	// 写坏的证书不会替换正在使用的证书
+	os.WriteFile(conf.CertFile, []byte("broken"), 0600)
+	future = future.Add(time.Minute)
+	os.Chtimes(conf.CertFile, future, future)
+	time.Sleep(certCheckInterval)
+	if serial, _, err := handshake(t, serverConf, clientConf); err != nil || serial != 4 {
+		t.Fatalf("after broken write: serial %d, err %v", serial, err)
+	}
+
+	if tlsConf, err := newServerTLSConfig(config.TlsConfig{}); tlsConf != nil || err != nil {
+		t.Fatalf("empty config: %v, %v", tlsConf, err)
+	}
+	if _, err := newServerTLSConfig(config.TlsConfig{CertFile: conf.CertFile, KeyFile: conf.KeyFile, RequireClientCert: true}); err == nil {
+		t.Fatal("expected require_client_cert without a ca to be rejected")
+	}
+}
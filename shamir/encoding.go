@@ -0,0 +1,64 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// sharePrefix 份额的文本前缀，便于人工识别
+	sharePrefix  = "wss-"
+	shareVersion = 1
+	checksumSize = 4
+	// version || id || threshold || index
+	shareHeaderSize = 1 + 2 + 1 + 1
+)
+
+var ErrInvalidChecksum = errors.New("share checksum mismatch")
+
+// Encode 编码为 "wss-" + hex(version || id || threshold || index || value || checksum)，
+// checksum 为前面所有字节 sha256 的前 4 字节，抄写错误可以在恢复前被发现
+func (s *Share) Encode() string {
+	body := make([]byte, 0, shareHeaderSize+len(s.Value)+checksumSize)
+	body = append(body, shareVersion)
+	body = binary.BigEndian.AppendUint16(body, s.ID)
+	body = append(body, s.Threshold, s.Index)
+	body = append(body, s.Value...)
+	checksum := sha256.Sum256(body)
+	body = append(body, checksum[:checksumSize]...)
+	return sharePrefix + hex.EncodeToString(body)
+}
+
+// Decode 解析 Encode 的输出，忽略空白字符
+func Decode(text string) (*Share, error) {
+	text = strings.Join(strings.Fields(text), "")
+	if !strings.HasPrefix(text, sharePrefix) {
+		return nil, errors.New("not a share")
+	}
+	body, err := hex.DecodeString(text[len(sharePrefix):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid share encoding: %w", err)
+	}
+	if len(body) <= shareHeaderSize+checksumSize {
+		return nil, errors.New("share is too short")
+	}
+	payload, checksum := body[:len(body)-checksumSize], body[len(body)-checksumSize:]
+	want := sha256.Sum256(payload)
+	if !bytes.Equal(checksum, want[:checksumSize]) {
+		return nil, ErrInvalidChecksum
+	}
+	if payload[0] != shareVersion {
+		return nil, fmt.Errorf("unsupported share version %d", payload[0])
+	}
+	return &Share{
+		ID:        binary.BigEndian.Uint16(payload[1:3]),
+		Threshold: payload[3],
+		Index:     payload[4],
+		Value:     append([]byte(nil), payload[shareHeaderSize:]...),
+	}, nil
+}
@@ -0,0 +1,40 @@
+package shamir
+
+// GF(2^8) 运算，使用 AES 的不可约多项式 x^8 + x^4 + x^3 + x + 1，生成元为 3
+var (
+	expTable [510]byte
+	logTable [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		expTable[i+255] = x
+		logTable[x] = byte(i)
+		// x *= 3
+		x ^= xtime(x)
+	}
+}
+
+func xtime(x byte) byte {
+	if x&0x80 != 0 {
+		return x<<1 ^ 0x1b
+	}
+	return x << 1
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// gfDiv b 不能为 0
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
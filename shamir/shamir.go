@@ -0,0 +1,143 @@
+// Package shamir 在 GF(256) 上实现 Shamir 秘密分享，用于主种子和 KEK 的 M-of-N 离线备份。
+//
+// 秘密后面会拼接 4 字节的 sha256 摘要后再分享，恢复时校验摘要，混入错误的份额会被发现。
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	MinThreshold = 2
+	MaxShares    = 255
+	digestSize   = 4
+)
+
+var (
+	ErrNotEnoughShares = errors.New("not enough shares to recover the secret")
+	ErrMixedShares     = errors.New("shares belong to different splits")
+	ErrDigestMismatch  = errors.New("recovered secret does not match its digest")
+)
+
+// Share 一个份额，同一次拆分的所有份额 ID 相同
+type Share struct {
+	ID        uint16
+	Threshold byte
+	Index     byte
+	Value     []byte
+}
+
+// Split 把秘密拆成 shares 份，任意 threshold 份可以恢复
+func Split(secret []byte, threshold int, shares int) ([]*Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+	if threshold < MinThreshold || threshold > shares || shares > MaxShares {
+		return nil, fmt.Errorf("invalid threshold %d of %d shares", threshold, shares)
+	}
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	payload := appendDigest(secret)
+
+	result := make([]*Share, shares)
+	for i := range result {
+		result[i] = &Share{
+			ID:        binary.BigEndian.Uint16(id[:]),
+			Threshold: byte(threshold),
+			Index:     byte(i + 1),
+			Value:     make([]byte, len(payload)),
+		}
+	}
+	// 每个字节独立取一个常数项为该字节的 threshold-1 次随机多项式
+	coefficients := make([]byte, threshold)
+	for pos, b := range payload {
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		coefficients[0] = b
+		for _, share := range result {
+			share.Value[pos] = evaluate(coefficients, share.Index)
+		}
+	}
+	return result, nil
+}
+
+// Combine 用至少 threshold 份同一次拆分的份额恢复秘密
+func Combine(shares []*Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	first := shares[0]
+	seen := make(map[byte]bool)
+	var used []*Share
+	for _, share := range shares {
+		if share.ID != first.ID || share.Threshold != first.Threshold || len(share.Value) != len(first.Value) {
+			return nil, ErrMixedShares
+		}
+		if share.Index == 0 {
+			return nil, errors.New("invalid share index")
+		}
+		if seen[share.Index] {
+			continue
+		}
+		seen[share.Index] = true
+		used = append(used, share)
+	}
+	if len(used) < int(first.Threshold) {
+		return nil, ErrNotEnoughShares
+	}
+	used = used[:first.Threshold]
+
+	payload := make([]byte, len(first.Value))
+	for pos := range payload {
+		payload[pos] = interpolateAtZero(used, pos)
+	}
+	if len(payload) <= digestSize {
+		return nil, ErrDigestMismatch
+	}
+	secret := payload[:len(payload)-digestSize]
+	if !bytes.Equal(appendDigest(secret), payload) {
+		return nil, ErrDigestMismatch
+	}
+	return secret, nil
+}
+
+func appendDigest(secret []byte) []byte {
+	digest := sha256.Sum256(secret)
+	payload := make([]byte, 0, len(secret)+digestSize)
+	payload = append(payload, secret...)
+	return append(payload, digest[:digestSize]...)
+}
+
+// evaluate 用秦九韶算法计算多项式在 x 处的值
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// interpolateAtZero 拉格朗日插值求 f(0)
+func interpolateAtZero(shares []*Share, pos int) byte {
+	var result byte
+	for i, si := range shares {
+		basis := byte(1)
+		for j, sj := range shares {
+			if i == j {
+				continue
+			}
+			// GF(2^8) 中减法即异或：l_i(0) = Π x_j / (x_j - x_i)
+			basis = gfMul(basis, gfDiv(sj.Index, sj.Index^si.Index))
+		}
+		result ^= gfMul(si.Value[pos], basis)
+	}
+	return result
+}
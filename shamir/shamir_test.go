@@ -0,0 +1,59 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 16)
+	shares, err := Split(secret, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var picked []*Share
+		for _, i := range subset {
+			share, err := Decode(shares[i].Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+			picked = append(picked, share)
+		}
+		recovered, err := Combine(picked)
+		if err != nil || !bytes.Equal(recovered, secret) {
+			t.Fatalf("subset %v: recovered %x, err %v", subset, recovered, err)
+		}
+	}
+	if _, err := Combine(shares[:2]); err != ErrNotEnoughShares {
+		t.Fatalf("err = %v, want ErrNotEnoughShares", err)
+	}
+	if _, err := Combine([]*Share{shares[0], shares[0], shares[1]}); err != ErrNotEnoughShares {
+		t.Fatalf("duplicate shares: err = %v, want ErrNotEnoughShares", err)
+	}
+
+	other, _ := Split(secret, 3, 5)
+	if _, err := Combine([]*Share{shares[0], shares[1], other[2]}); err == nil {
+		t.Fatal("expected shares of different splits to be rejected")
+	}
+	forged := *shares[2]
+	forged.Value = append([]byte(nil), forged.Value...)
+	forged.Value[0] ^= 1
+	if _, err := Combine([]*Share{shares[0], shares[1], &forged}); err != ErrDigestMismatch {
+		t.Fatalf("err = %v, want ErrDigestMismatch", err)
+	}
+}
+
+func TestDecodeChecksum(t *testing.T) {
+	shares, _ := Split([]byte("kek"), 2, 2)
+	encoded := []byte(shares[0].Encode())
+	last := len(encoded) - 1
+	if encoded[last] == '0' {
+		encoded[last] = '1'
+	} else {
+		encoded[last] = '0'
+	}
+	if _, err := Decode(string(encoded)); err != ErrInvalidChecksum {
+		t.Fatalf("err = %v, want ErrInvalidChecksum", err)
+	}
+}
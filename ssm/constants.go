@@ -3,3 +3,8 @@ package ssm
 const (
 	EmptyHexString = "0x00"
 )
+
+const (
+	CurveSecp256k1 = "secp256k1"
+	CurveEd25519   = "ed25519"
+)
@@ -2,11 +2,14 @@ package ssm
 
 import (
 	"encoding/hex"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
 )
 
 type ECDSASigner struct{}
@@ -23,6 +26,30 @@ func (ecdsa *ECDSASigner) CreateKeyPair() (priKey string, pubKey string, compres
 	return privateKeyStr, publicKeyStr, compressPublicKeyStr, nil
 }
 
+func (ecdsa *ECDSASigner) DeriveKeyPair(seed []byte, path string) (priKey string, pubKey string, compressPubKey string, err error) {
+	btcecKey, err := hdwallet.DeriveSecp256k1(seed, path)
+	if err != nil {
+		log.Error("derive key fail", "path", path, "err", err)
+		return EmptyHexString, EmptyHexString, EmptyHexString, err
+	}
+	privateKey := btcecKey.ToECDSA()
+	privateKeyStr := hex.EncodeToString(crypto.FromECDSA(privateKey))
+	publicKeyStr := hex.EncodeToString(crypto.FromECDSAPub(&privateKey.PublicKey))
+	compressPublicKeyStr := hex.EncodeToString(crypto.CompressPubkey(&privateKey.PublicKey))
+	return privateKeyStr, publicKeyStr, compressPublicKeyStr, nil
+}
+
+func (ecdsa *ECDSASigner) ImportKeyPair(priKey []byte) (string, string, string, error) {
+	privateKey, err := crypto.ToECDSA(priKey)
+	if err != nil {
+		return EmptyHexString, EmptyHexString, EmptyHexString, fmt.Errorf("invalid secp256k1 private key: %w", err)
+	}
+	privateKeyStr := hex.EncodeToString(crypto.FromECDSA(privateKey))
+	publicKeyStr := hex.EncodeToString(crypto.FromECDSAPub(&privateKey.PublicKey))
+	compressPublicKeyStr := hex.EncodeToString(crypto.CompressPubkey(&privateKey.PublicKey))
+	return privateKeyStr, publicKeyStr, compressPublicKeyStr, nil
+}
+
 func (ecdsa *ECDSASigner) SignMessage(priKey string, txMsg string) (string, error) {
 	hash := common.HexToHash(txMsg)
 	priByte, err := hex.DecodeString(priKey)
@@ -1,10 +1,15 @@
 package ssm
 
 import (
+	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/hdwallet"
 )
 
 type EdDSASigner struct{}
@@ -18,6 +23,34 @@ func (eddsa *EdDSASigner) CreateKeyPair() (string, string, string, error) {
 	return hex.EncodeToString(privateKey), hex.EncodeToString(publicKey), hex.EncodeToString(publicKey), err
 }
 
+func (eddsa *EdDSASigner) DeriveKeyPair(seed []byte, path string) (string, string, string, error) {
+	privateKey, err := hdwallet.DeriveEd25519(seed, path)
+	if err != nil {
+		log.Error("derive key fail", "path", path, "err", err)
+		return EmptyHexString, EmptyHexString, EmptyHexString, err
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return hex.EncodeToString(privateKey), hex.EncodeToString(publicKey), hex.EncodeToString(publicKey), nil
+}
+
+// ImportKeyPair 接受 32 字节种子或 64 字节 种子||公钥 格式的私钥，后者会校验公钥是否匹配
+func (eddsa *EdDSASigner) ImportKeyPair(priKey []byte) (string, string, string, error) {
+	var privateKey ed25519.PrivateKey
+	switch len(priKey) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(priKey)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.NewKeyFromSeed(priKey[:ed25519.SeedSize])
+		if !bytes.Equal(privateKey[ed25519.SeedSize:], priKey[ed25519.SeedSize:]) {
+			return EmptyHexString, EmptyHexString, EmptyHexString, errors.New("ed25519 private key does not match its public key")
+		}
+	default:
+		return EmptyHexString, EmptyHexString, EmptyHexString, fmt.Errorf("invalid ed25519 private key length %d", len(priKey))
+	}
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return hex.EncodeToString(privateKey), hex.EncodeToString(publicKey), hex.EncodeToString(publicKey), nil
+}
+
 func (eddsa *EdDSASigner) SignMessage(priKey string, txMsg string) (string, error) {
 	priKeyByte, err := hex.DecodeString(priKey)
 	if err != nil {
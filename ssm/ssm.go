@@ -1,7 +1,44 @@
 package ssm
 
+import "context"
+
 type Signer interface {
 	CreateKeyPair() (privateKey string, publicKey string, compressPubKey string, err error)
 	SignMessage(priKey string, msg string) (signature string, err error)
 	VerifySignature(pubKey string, msgHash string, signature string) (bool, error)
 }
+
+// HDSigner 支持从 HD 钱包种子按路径派生密钥对，返回格式与 CreateKeyPair 一致
+type HDSigner interface {
+	DeriveKeyPair(seed []byte, path string) (privateKey string, publicKey string, compressPubKey string, err error)
+}
+
+// KeyImporter 校验外部导入的原始私钥属于签名器使用的曲线，返回格式与 CreateKeyPair 一致
+type KeyImporter interface {
+	ImportKeyPair(priKey []byte) (privateKey string, publicKey string, compressPubKey string, err error)
+}
+
+// SchnorrOnlySigner 声明新建的密钥只能产生 Schnorr 签名（例如 FROST secp256k1-tr），
+// 包装其它签名器的实现按自己新建密钥的方式回答
+type SchnorrOnlySigner interface {
+	SchnorrOnly() bool
+}
+
+// SchnorrOnly signer 没有实现 SchnorrOnlySigner 时视为可以产生 ECDSA 签名
+func SchnorrOnly(signer Signer) bool {
+	s, ok := signer.(SchnorrOnlySigner)
+	return ok && s.SchnorrOnly()
+}
+
+// ContextSigner 签名时使用调用方的上下文，请求的取消和链路追踪会传到 KMS/HSM 调用
+type ContextSigner interface {
+	SignMessageContext(ctx context.Context, priKey string, msg string) (signature string, err error)
+}
+
+// SignMessageContext signer 实现 ContextSigner 时带上下文签名，否则调用 SignMessage
+func SignMessageContext(ctx context.Context, signer Signer, priKey string, msg string) (string, error) {
+	if s, ok := signer.(ContextSigner); ok {
+		return s.SignMessageContext(ctx, priKey, msg)
+	}
+	return signer.SignMessage(priKey, msg)
+}
@@ -0,0 +1,120 @@
+// Package tracing OpenTelemetry 链路追踪。
+//
+// span 由全局 TracerProvider 产生，未调用 Setup 或 Install 时为空实现，不产生开销。
+// gRPC 请求的 trace 上下文（W3C traceparent）通过 Extract 从请求元数据中取出，服务内的 span 都挂在它下面。
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+)
+
+const (
+	instrumentationName = "github.com/0xshin-chan/wallet-sign"
+
+	defaultServiceName = "wallet-sign"
+)
+
+// Setup 按配置创建 OTLP/gRPC 导出器并安装为全局 TracerProvider，返回的函数在退出时发送剩余的 span。
+// 未开启时返回空函数
+func Setup(ctx context.Context, conf config.TracingConfig) (func(context.Context) error, error) {
+	if !conf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if conf.Endpoint == "" {
+		return nil, errors.New("tracing endpoint is not configured")
+	}
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint)}
+	if conf.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return Install(exporter, conf).Shutdown, nil
+}
+
+// Install 使用给定的导出器安装全局 TracerProvider 和 W3C 传播器，测试中可以传入内存导出器
+func Install(exporter sdktrace.SpanExporter, conf config.TracingConfig) *sdktrace.TracerProvider {
+	serviceName := conf.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	ratio := conf.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider
+}
+
+// Start 开始一个子 span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartServer 开始一个 gRPC 服务端 span
+func StartServer(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// End 结束 span，err 不为 nil 时记录错误
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract 从 gRPC 请求元数据中取出上游的 trace 上下文
+func Extract(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+}
+
+// metadataCarrier 让传播器读写 gRPC 元数据
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key string, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/0xshin-chan/wallet-sign/config"
+)
+
+func TestSetupRequiresEndpoint(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Setup(context.Background(), config.TracingConfig{Enabled: true}); err == nil {
+		t.Fatal("tracing enabled without endpoint")
+	}
+}
+
+func TestExtractContinuesIncomingTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := Install(exporter, config.TracingConfig{})
+	defer provider.Shutdown(context.Background())
+
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	ctx, span := StartServer(Extract(ctx), "rpc")
+	_, child := Start(ctx, "child")
+	End(child, context.Canceled)
+	End(span, nil)
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	for _, s := range spans {
+		if s.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Fatalf("span %s has trace id %s", s.Name, s.SpanContext.TraceID())
+		}
+	}
+	if spans[1].SpanKind != trace.SpanKindServer || spans[1].Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("server span = %+v", spans[1])
+	}
+	if len(spans[0].Events) == 0 {
+		t.Fatal("child span did not record the error")
+	}
+}
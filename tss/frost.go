@@ -0,0 +1,268 @@
+package tss
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/0xshin-chan/wallet-sign/frost"
+)
+
+const (
+	protocolFrostKeygen  = "frost/keygen"
+	protocolFrostRefresh = "frost/refresh"
+	protocolFrostSign    = "frost/sign"
+
+	roundFrostCommit  = "frost/commit"
+	roundFrostShare   = "frost/share"
+	roundFrostConfirm = "frost/confirm"
+	roundFrostDone    = "frost/done"
+	roundFrostNonce   = "frost/nonce"
+	roundFrostZ       = "frost/z"
+
+	// FrostKeyIDPrefix FROST 密钥 id 的前缀，完整格式为 frost:<suite>:<群公钥 hex>
+	FrostKeyIDPrefix = "frost:"
+)
+
+type frostShare struct {
+	Share *big.Int `json:"share"`
+}
+
+// frostConfirm 各方算出的密钥 id 和分片指纹，确认所有人得到一致的群公钥和验证分片
+type frostConfirm struct {
+	KeyID       string `json:"key_id"`
+	Fingerprint []byte `json:"fingerprint"`
+}
+
+type frostZ struct {
+	Z *big.Int `json:"z"`
+}
+
+func frostKeyID(suite string, groupKey []byte) string {
+	return FrostKeyIDPrefix + suite + ":" + hex.EncodeToString(groupKey)
+}
+
+// FrostKeySuite 从 FROST 密钥 id 中取出密码套件名
+func FrostKeySuite(keyID string) (string, bool) {
+	if !strings.HasPrefix(keyID, FrostKeyIDPrefix) {
+		return "", false
+	}
+	suite, _, ok := strings.Cut(strings.TrimPrefix(keyID, FrostKeyIDPrefix), ":")
+	return suite, ok
+}
+
+// FrostKeygen 发起 FROST 分布式密钥生成，parties 必须包含本方
+func (n *Node) FrostKeygen(ctx context.Context, suite string, threshold int, parties []int) (string, *frost.KeyShare, error) {
+	if err := validateParties(n.id, parties); err != nil {
+		return "", nil, err
+	}
+	if threshold < 2 || threshold > len(parties) {
+		return "", nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, len(parties))
+	}
+	if _, err := frost.SuiteByName(suite); err != nil {
+		return "", nil, err
+	}
+	return n.initiateFrostDealer(ctx, startRequest{Protocol: protocolFrostKeygen, Suite: suite, Threshold: threshold, Parties: parties})
+}
+
+// FrostRefresh 发起分片刷新：群公钥不变，所有参与方的分片被替换，旧分片不能再与新分片一起签名。
+// 刷新需要密钥的全部参与方在线
+func (n *Node) FrostRefresh(ctx context.Context, keyID string) (*frost.KeyShare, error) {
+	share, err := n.store.LoadFrostShare(keyID)
+	if err != nil {
+		return nil, err
+	}
+	_, refreshed, err := n.initiateFrostDealer(ctx, startRequest{Protocol: protocolFrostRefresh, KeyID: keyID, Parties: share.Parties})
+	return refreshed, err
+}
+
+func (n *Node) initiateFrostDealer(ctx context.Context, req startRequest) (string, *frost.KeyShare, error) {
+	sessionID, err := n.start(ctx, req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer n.closeSession(sessionID)
+	sess := n.session(sessionID)
+	keyID, share, err := n.runFrostDealer(ctx, sess, req)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := n.store.SaveFrostShare(keyID, share); err != nil {
+		return "", nil, err
+	}
+	// 等所有参与方保存完分片再返回，避免在分片落盘前发起签名
+	if _, err := collect[frostConfirm](ctx, n, sess, roundFrostDone, req.Parties); err != nil {
+		return "", nil, err
+	}
+	return keyID, share, nil
+}
+
+// runFrostDealer 运行密钥生成或刷新的三轮消息：承诺、私下发送取值、确认结果
+func (n *Node) runFrostDealer(ctx context.Context, sess *session, req startRequest) (string, *frost.KeyShare, error) {
+	var (
+		dealer *frost.Dealer
+		round1 *frost.Round1
+		keyID  = req.KeyID
+		err    error
+	)
+	switch req.Protocol {
+	case protocolFrostKeygen:
+		dealer, round1, err = frost.NewKeygen(req.Suite, n.id, req.Threshold, req.Parties)
+	case protocolFrostRefresh:
+		var share *frost.KeyShare
+		share, err = n.store.LoadFrostShare(req.KeyID)
+		if err != nil {
+			return "", nil, err
+		}
+		if !sameParties(share.Parties, req.Parties) {
+			return "", nil, errors.New("refresh must include every party of the key")
+		}
+		dealer, round1, err = frost.NewRefresh(share)
+	default:
+		err = fmt.Errorf("unknown frost protocol %q", req.Protocol)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := n.broadcast(ctx, sess, roundFrostCommit, req.Parties, round1); err != nil {
+		return "", nil, err
+	}
+	for _, party := range otherParties(n.id, req.Parties) {
+		if err := n.sendTo(ctx, sess, roundFrostShare, party, &frostShare{Share: dealer.Share(party)}); err != nil {
+			return "", nil, err
+		}
+	}
+	commits, err := collect[frost.Round1](ctx, n, sess, roundFrostCommit, req.Parties)
+	if err != nil {
+		return "", nil, err
+	}
+	received, err := collect[frostShare](ctx, n, sess, roundFrostShare, req.Parties)
+	if err != nil {
+		return "", nil, err
+	}
+	shares := make(map[int]*big.Int, len(received))
+	for party, msg := range received {
+		shares[party] = msg.Share
+	}
+	share, err := dealer.Finish(commits, shares)
+	if err != nil {
+		return "", nil, err
+	}
+	if req.Protocol == protocolFrostKeygen {
+		keyID = frostKeyID(share.Suite, share.GroupKey)
+	}
+
+	confirm := &frostConfirm{KeyID: keyID, Fingerprint: share.Fingerprint()}
+	if err := n.broadcast(ctx, sess, roundFrostConfirm, req.Parties, confirm); err != nil {
+		return "", nil, err
+	}
+	confirms, err := collect[frostConfirm](ctx, n, sess, roundFrostConfirm, req.Parties)
+	if err != nil {
+		return "", nil, err
+	}
+	for party, c := range confirms {
+		if c.KeyID != keyID || !bytes.Equal(c.Fingerprint, confirm.Fingerprint) {
+			return "", nil, fmt.Errorf("party %d derived a different key share set", party)
+		}
+	}
+	return keyID, share, nil
+}
+
+// FrostSign 发起对 msg 的两轮 FROST 签名。signers 为空时选本方和分片中排在前面的参与方
+func (n *Node) FrostSign(ctx context.Context, keyID string, signers []int, msg []byte) ([]byte, error) {
+	share, err := n.store.LoadFrostShare(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		signers = defaultSigners(n.id, share.Parties, share.Threshold)
+	}
+	if err := validateFrostSigners(n.id, share, signers); err != nil {
+		return nil, err
+	}
+	sessionID, err := n.start(ctx, startRequest{Protocol: protocolFrostSign, Parties: signers, KeyID: keyID, Message: msg})
+	if err != nil {
+		return nil, err
+	}
+	defer n.closeSession(sessionID)
+	return n.runFrostSign(ctx, n.session(sessionID), share, n.id, signers, msg)
+}
+
+// runFrostSign 第一轮所有签名方互相广播随机数承诺，第二轮把签名分片发给发起方，由发起方校验并合成签名；
+// 非发起方返回 nil
+func (n *Node) runFrostSign(ctx context.Context, sess *session, share *frost.KeyShare, initiator int, signers []int, msg []byte) ([]byte, error) {
+	if err := validateFrostSigners(n.id, share, signers); err != nil {
+		return nil, err
+	}
+	if !containsParty(signers, initiator) {
+		return nil, fmt.Errorf("initiator %d is not a signer", initiator)
+	}
+	nonce, commitment, err := share.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.broadcast(ctx, sess, roundFrostNonce, signers, commitment); err != nil {
+		return nil, err
+	}
+	received, err := collect[frost.Commitment](ctx, n, sess, roundFrostNonce, signers)
+	if err != nil {
+		return nil, err
+	}
+	commitments := []*frost.Commitment{commitment}
+	for party, c := range received {
+		// 以 transport 上的发送方为准，防止冒用其它参与方的 id
+		c.ID = party
+		commitments = append(commitments, c)
+	}
+	sort.Slice(commitments, func(i, j int) bool { return commitments[i].ID < commitments[j].ID })
+
+	z, err := share.Sign(nonce, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if n.id != initiator {
+		return nil, n.sendTo(ctx, sess, roundFrostZ, initiator, &frostZ{Z: z})
+	}
+	zs, err := collect[frostZ](ctx, n, sess, roundFrostZ, signers)
+	if err != nil {
+		return nil, err
+	}
+	shares := map[int]*big.Int{n.id: z}
+	for party, m := range zs {
+		shares[party] = m.Z
+	}
+	return share.Aggregate(msg, commitments, shares)
+}
+
+func validateFrostSigners(self int, share *frost.KeyShare, signers []int) error {
+	if err := validateParties(self, signers); err != nil {
+		return err
+	}
+	if len(signers) < share.Threshold {
+		return fmt.Errorf("need at least %d signers, got %d", share.Threshold, len(signers))
+	}
+	for _, signer := range signers {
+		if !containsParty(share.Parties, signer) {
+			return fmt.Errorf("party %d does not hold a share of this key", signer)
+		}
+	}
+	return nil
+}
+
+func sameParties(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, party := range b {
+		if !containsParty(a, party) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package tss
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/frost"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// FrostSigner 以 FROST 门限 Schnorr 协议实现 ssm.Signer，与 Signer 一样用密钥 id 代替私钥，
+// 不带 FrostKeyIDPrefix 的普通私钥交给 fallback。
+//
+// ed25519 套件输出标准 Ed25519 签名（Solana）；secp256k1-tr 套件输出 BIP340 签名，对应的 p2tr 地址直接以
+// 群公钥作为输出公钥（与 bitcoin 适配器生成 p2tr 地址的方式一致，没有 BIP86 tweak），只能用于 p2tr 地址
+type FrostSigner struct {
+	node      *Node
+	suite     string
+	threshold int
+	parties   []int
+	timeout   time.Duration
+	fallback  ssm.Signer
+}
+
+var (
+	_ ssm.Signer            = (*FrostSigner)(nil)
+	_ ssm.SchnorrOnlySigner = (*FrostSigner)(nil)
+)
+
+func NewFrostSigner(node *Node, suite string, threshold int, parties []int, fallback ssm.Signer) *FrostSigner {
+	return &FrostSigner{
+		node:      node,
+		suite:     suite,
+		threshold: threshold,
+		parties:   parties,
+		timeout:   DefaultTimeout,
+		fallback:  fallback,
+	}
+}
+
+// SchnorrOnly secp256k1-tr 套件的密钥只能产生 BIP340 签名
+func (s *FrostSigner) SchnorrOnly() bool {
+	return s.suite == frost.SuiteSecp256k1TR
+}
+
+// CreateKeyPair 公钥格式与本地签名器一致：ed25519 为 32 字节公钥，secp256k1 为非压缩和压缩公钥
+func (s *FrostSigner) CreateKeyPair() (string, string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	keyID, share, err := s.node.FrostKeygen(ctx, s.suite, s.threshold, s.parties)
+	if err != nil {
+		log.Error("frost keygen fail", "suite", s.suite, "err", err)
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	if s.suite != frost.SuiteSecp256k1TR {
+		pubKey := hex.EncodeToString(share.GroupKey)
+		return keyID, pubKey, pubKey, nil
+	}
+	pubKey, err := btcec.ParsePubKey(share.GroupKey)
+	if err != nil {
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	return keyID, hex.EncodeToString(pubKey.SerializeUncompressed()), hex.EncodeToString(share.GroupKey), nil
+}
+
+func (s *FrostSigner) SignMessage(priKey string, txMsg string) (string, error) {
+	if !strings.HasPrefix(priKey, FrostKeyIDPrefix) {
+		return s.fallback.SignMessage(priKey, txMsg)
+	}
+	msg, err := hex.DecodeString(strings.TrimPrefix(txMsg, "0x"))
+	if err != nil {
+		log.Error("decode tx message fail", "err", err)
+		return ssm.EmptyHexString, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	signature, err := s.node.FrostSign(ctx, priKey, nil, msg)
+	if err != nil {
+		log.Error("frost sign fail", "key", priKey, "err", err)
+		return ssm.EmptyHexString, err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+// VerifySignature secp256k1-tr 下 64 字节的签名按 BIP340 校验，其它情况交给 fallback
+func (s *FrostSigner) VerifySignature(pubKey string, msgHash string, signature string) (bool, error) {
+	sig, err := hex.DecodeString(signature)
+	if s.suite != frost.SuiteSecp256k1TR || err != nil || len(sig) != 64 {
+		return s.fallback.VerifySignature(pubKey, msgHash, signature)
+	}
+	pubKeyBytes, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return false, err
+	}
+	parsed, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		return false, err
+	}
+	msg, err := hex.DecodeString(strings.TrimPrefix(msgHash, "0x"))
+	if err != nil {
+		return false, err
+	}
+	return frost.Verify(s.suite, parsed.SerializeCompressed(), msg, sig)
+}
+
+// Refresh 刷新密钥的所有分片，群公钥和地址不变
+func (s *FrostSigner) Refresh(keyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if _, err := s.node.FrostRefresh(ctx, keyID); err != nil {
+		log.Error("frost refresh fail", "key", keyID, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package tss
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+const (
+	roundKeygenCommit  = "keygen/commit"
+	roundKeygenShare   = "keygen/share"
+	roundKeygenConfirm = "keygen/confirm"
+	roundKeygenDone    = "keygen/done"
+
+	// KeyIDPrefix 门限密钥 id 的前缀，后接压缩公钥 hex，用来与普通私钥区分
+	KeyIDPrefix = "tss:"
+)
+
+var curve = btcec.S256()
+
+// keygenCommit 第一轮广播：多项式系数的 Feldman 承诺和本方的 Paillier 公钥
+type keygenCommit struct {
+	Commitments []Point  `json:"commitments"`
+	PaillierN   *big.Int `json:"paillier_n"`
+}
+
+// keygenShare 第二轮点对点发送：本方多项式在接收方处的取值
+type keygenShare struct {
+	Share *big.Int `json:"share"`
+}
+
+// keygenConfirm 第三轮广播：各方算出的密钥 id，用来确认所有人得到同一个公钥
+type keygenConfirm struct {
+	KeyID string `json:"key_id"`
+}
+
+func (n *Node) runKeygen(ctx context.Context, sess *session, threshold int, parties []int) (*KeyShare, error) {
+	if err := validateParties(n.id, parties); err != nil {
+		return nil, err
+	}
+	if threshold < 2 || threshold > len(parties) {
+		return nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, len(parties))
+	}
+	// 本方随机选一个 threshold-1 次多项式，常数项是本方对私钥的贡献
+	coefficients := make([]*big.Int, threshold)
+	commitments := make([]Point, threshold)
+	for i := range coefficients {
+		c, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = c
+		commitments[i] = scalarBaseMult(c)
+	}
+	paillier, err := GeneratePaillierKey(n.paillierBits)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := n.broadcast(ctx, sess, roundKeygenCommit, parties, &keygenCommit{Commitments: commitments, PaillierN: paillier.N}); err != nil {
+		return nil, err
+	}
+	for _, party := range otherParties(n.id, parties) {
+		share := evaluatePolynomial(coefficients, party)
+		if err := n.sendTo(ctx, sess, roundKeygenShare, party, &keygenShare{Share: share}); err != nil {
+			return nil, err
+		}
+	}
+
+	commits, err := collect[keygenCommit](ctx, n, sess, roundKeygenCommit, parties)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := collect[keygenShare](ctx, n, sess, roundKeygenShare, parties)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := evaluatePolynomial(coefficients, n.id)
+	publicKey := commitments[0]
+	paillierKeys := map[int]*PaillierPublicKey{n.id: &paillier.PaillierPublicKey}
+	for party, commit := range commits {
+		if len(commit.Commitments) != threshold {
+			return nil, fmt.Errorf("party %d committed to %d coefficients, want %d", party, len(commit.Commitments), threshold)
+		}
+		for _, point := range commit.Commitments {
+			if !isOnCurve(point) {
+				return nil, fmt.Errorf("party %d committed to an invalid point", party)
+			}
+		}
+		if commit.PaillierN == nil || commit.PaillierN.BitLen() < n.paillierBits-1 {
+			return nil, fmt.Errorf("party %d paillier modulus is too small", party)
+		}
+		share := shares[party].Share
+		if share == nil || share.Sign() <= 0 || share.Cmp(curve.N) >= 0 {
+			return nil, fmt.Errorf("party %d sent an invalid share", party)
+		}
+		// Feldman 校验：share·G == Σ C_k · id^k
+		if !pointEqual(scalarBaseMult(share), evaluateCommitments(commit.Commitments, n.id)) {
+			return nil, fmt.Errorf("share from party %d does not match its commitments", party)
+		}
+		secret = new(big.Int).Add(secret, share)
+		publicKey = pointAdd(publicKey, commit.Commitments[0])
+		paillierKeys[party] = &PaillierPublicKey{N: commit.PaillierN}
+	}
+	secret.Mod(secret, curve.N)
+	if publicKey.X == nil {
+		return nil, errors.New("public key is the point at infinity")
+	}
+
+	keyID := KeyIDPrefix + hex.EncodeToString(compressPoint(publicKey))
+	if err := n.broadcast(ctx, sess, roundKeygenConfirm, parties, &keygenConfirm{KeyID: keyID}); err != nil {
+		return nil, err
+	}
+	confirms, err := collect[keygenConfirm](ctx, n, sess, roundKeygenConfirm, parties)
+	if err != nil {
+		return nil, err
+	}
+	for party, confirm := range confirms {
+		if confirm.KeyID != keyID {
+			return nil, fmt.Errorf("party %d derived a different public key", party)
+		}
+	}
+
+	return &KeyShare{
+		KeyID:        keyID,
+		PartyID:      n.id,
+		Threshold:    threshold,
+		Parties:      append([]int(nil), parties...),
+		Secret:       secret,
+		PublicKey:    publicKey,
+		Paillier:     paillier,
+		PaillierKeys: paillierKeys,
+	}, nil
+}
+
+func evaluatePolynomial(coefficients []*big.Int, x int) *big.Int {
+	bx := big.NewInt(int64(x))
+	y := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y.Mul(y, bx)
+		y.Add(y, coefficients[i])
+		y.Mod(y, curve.N)
+	}
+	return y
+}
+
+func evaluateCommitments(commitments []Point, x int) Point {
+	var result Point
+	power := big.NewInt(1)
+	bx := big.NewInt(int64(x))
+	for _, c := range commitments {
+		result = pointAdd(result, scalarMult(c, power))
+		power = new(big.Int).Mod(new(big.Int).Mul(power, bx), curve.N)
+	}
+	return result
+}
+
+// lagrangeAtZero 参与方 id 在 signers 集合上的拉格朗日系数 Π j / (j - id)
+func lagrangeAtZero(id int, signers []int) *big.Int {
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	for _, j := range signers {
+		if j == id {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(j)))
+		den.Mul(den, big.NewInt(int64(j-id)))
+	}
+	den.Mod(den, curve.N)
+	den.ModInverse(den, curve.N)
+	return num.Mul(num, den).Mod(num, curve.N)
+}
+
+func randomScalar() (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, curve.N)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+// 点运算沿用 elliptic.Curve 的约定，X 为 nil 表示无穷远点
+
+func scalarBaseMult(k *big.Int) Point {
+	x, y := curve.ScalarBaseMult(k.Bytes())
+	return Point{X: x, Y: y}
+}
+
+func scalarMult(p Point, k *big.Int) Point {
+	if p.X == nil || k.Sign() == 0 {
+		return Point{}
+	}
+	x, y := curve.ScalarMult(p.X, p.Y, k.Bytes())
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return Point{}
+	}
+	return Point{X: x, Y: y}
+}
+
+func pointAdd(a, b Point) Point {
+	if a.X == nil {
+		return b
+	}
+	if b.X == nil {
+		return a
+	}
+	x, y := curve.Add(a.X, a.Y, b.X, b.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return Point{}
+	}
+	return Point{X: x, Y: y}
+}
+
+func pointEqual(a, b Point) bool {
+	if a.X == nil || b.X == nil {
+		return a.X == nil && b.X == nil
+	}
+	return a.X.Cmp(b.X) == 0 && a.Y.Cmp(b.Y) == 0
+}
+
+func isOnCurve(p Point) bool {
+	if p.X == nil || p.Y == nil || p.X.Sign() < 0 || p.Y.Sign() < 0 {
+		return false
+	}
+	if p.X.Cmp(curve.P) >= 0 || p.Y.Cmp(curve.P) >= 0 {
+		return false
+	}
+	return curve.IsOnCurve(p.X, p.Y)
+}
+
+func compressPoint(p Point) []byte {
+	out := make([]byte, 33)
+	out[0] = 0x02 + byte(p.Y.Bit(0))
+	p.X.FillBytes(out[1:])
+	return out
+}
+
+func uncompressPoint(p Point) []byte {
+	out := make([]byte, 65)
+	out[0] = 0x04
+	p.X.FillBytes(out[1:33])
+	p.Y.FillBytes(out[33:])
+	return out
+}
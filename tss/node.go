@@ -0,0 +1,407 @@
+// Package tss 实现 secp256k1 的门限 ECDSA（t-of-n），私钥以 Shamir 分片的形式分散在多个参与方，
+// 任何时候都不会在一处重建。
+//
+// 密钥生成是基于 Feldman VSS 的分布式密钥生成；签名沿用 GG18 的结构，用 Paillier 同态加密完成
+// MtA（乘法转加法），得到 k·γ 和 k·x 的加法分享后合成签名。
+//
+// 注意：当前实现只抵御半诚实（诚实但好奇）的参与方，省略了 GG18 中的零知识证明（Paillier 模数、
+// MtA 范围证明）和承诺-揭示步骤。部署时参与方之间必须是相互认证的信道，并且每个参与方的运行环境需要
+// 单独保护。
+//
+// 同一套节点和 transport 还负责运行 FROST 门限 Schnorr 协议（见 frost.go），密码学部分在 frost 包中。
+package tss
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	roundStart     = "start"
+	protocolKeygen = "keygen"
+	protocolSign   = "sign"
+
+	DefaultPaillierBits = 2048
+	// 超过这个时间仍未完成的会话会被清理
+	sessionTTL = 10 * time.Minute
+)
+
+// startRequest 发起方通知其它参与方加入一个协议会话
+type startRequest struct {
+	Protocol  string `json:"protocol"`
+	Threshold int    `json:"threshold,omitempty"`
+	Parties   []int  `json:"parties"`
+	KeyID     string `json:"key_id,omitempty"`
+	Hash      []byte `json:"hash,omitempty"`
+	Suite     string `json:"suite,omitempty"`
+	Message   []byte `json:"message,omitempty"`
+}
+
+// Node 一个参与方。Run 负责接收消息，收到其它参与方发起的会话时自动参与；
+// Keygen 和 Sign 以本方为发起方运行协议
+type Node struct {
+	id           int
+	transport    Transport
+	store        ShareStore
+	paillierBits int
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+func NewNode(id int, transport Transport, store ShareStore, paillierBits int) *Node {
+	if paillierBits <= 0 {
+		paillierBits = DefaultPaillierBits
+	}
+	return &Node{
+		id:           id,
+		transport:    transport,
+		store:        store,
+		paillierBits: paillierBits,
+		sessions:     make(map[string]*session),
+	}
+}
+
+func (n *Node) ID() int {
+	return n.id
+}
+
+// Run 持续接收消息直到 ctx 结束
+func (n *Node) Run(ctx context.Context) error {
+	for {
+		msg, err := n.transport.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if msg.Round == roundStart {
+			var req startRequest
+			if err := json.Unmarshal(msg.Payload, &req); err != nil {
+				log.Error("decode tss start request fail", "party", n.id, "from", msg.From, "err", err)
+				continue
+			}
+			go n.participate(ctx, msg.SessionID, msg.From, req)
+			continue
+		}
+		n.session(msg.SessionID).deliver(msg)
+	}
+}
+
+// participate 作为非发起方参与 initiator 发起的会话
+func (n *Node) participate(ctx context.Context, sessionID string, initiator int, req startRequest) {
+	ctx, cancel := context.WithTimeout(ctx, sessionTTL)
+	defer cancel()
+	defer n.closeSession(sessionID)
+
+	sess := n.session(sessionID)
+	switch req.Protocol {
+	case protocolKeygen:
+		share, err := n.runKeygen(ctx, sess, req.Threshold, req.Parties)
+		if err != nil {
+			log.Error("tss keygen fail", "party", n.id, "session", sessionID, "err", err)
+			return
+		}
+		if err := n.store.SaveShare(share); err != nil {
+			log.Error("save tss key share fail", "party", n.id, "key", share.KeyID, "err", err)
+			return
+		}
+		// 分片落盘后再通知发起方，避免发起方在分片保存前就发起签名
+		if err := n.sendTo(ctx, sess, roundKeygenDone, initiator, &keygenConfirm{KeyID: share.KeyID}); err != nil {
+			log.Error("notify tss keygen done fail", "party", n.id, "err", err)
+		}
+	case protocolSign:
+		share, err := n.store.LoadShare(req.KeyID)
+		if err != nil {
+			log.Error("load tss key share fail", "party", n.id, "key", req.KeyID, "err", err)
+			return
+		}
+		if _, err := n.runSign(ctx, sess, share, req.Parties, req.Hash); err != nil {
+			log.Error("tss sign fail", "party", n.id, "session", sessionID, "err", err)
+		}
+	case protocolFrostKeygen, protocolFrostRefresh:
+		keyID, share, err := n.runFrostDealer(ctx, sess, req)
+		if err != nil {
+			log.Error("frost key generation fail", "party", n.id, "protocol", req.Protocol, "session", sessionID, "err", err)
+			return
+		}
+		if err := n.store.SaveFrostShare(keyID, share); err != nil {
+			log.Error("save frost key share fail", "party", n.id, "key", keyID, "err", err)
+			return
+		}
+		if err := n.sendTo(ctx, sess, roundFrostDone, initiator, &frostConfirm{KeyID: keyID, Fingerprint: share.Fingerprint()}); err != nil {
+			log.Error("notify frost done fail", "party", n.id, "err", err)
+		}
+	case protocolFrostSign:
+		share, err := n.store.LoadFrostShare(req.KeyID)
+		if err != nil {
+			log.Error("load frost key share fail", "party", n.id, "key", req.KeyID, "err", err)
+			return
+		}
+		if _, err := n.runFrostSign(ctx, sess, share, initiator, req.Parties, req.Message); err != nil {
+			log.Error("frost sign fail", "party", n.id, "session", sessionID, "err", err)
+		}
+	default:
+		log.Error("unknown tss protocol", "party", n.id, "protocol", req.Protocol)
+	}
+}
+
+// Keygen 发起分布式密钥生成，parties 必须包含本方
+func (n *Node) Keygen(ctx context.Context, threshold int, parties []int) (*KeyShare, error) {
+	if err := validateParties(n.id, parties); err != nil {
+		return nil, err
+	}
+	if threshold < 2 || threshold > len(parties) {
+		return nil, fmt.Errorf("invalid threshold %d of %d parties", threshold, len(parties))
+	}
+	sessionID, err := n.start(ctx, startRequest{Protocol: protocolKeygen, Threshold: threshold, Parties: parties})
+	if err != nil {
+		return nil, err
+	}
+	defer n.closeSession(sessionID)
+	share, err := n.runKeygen(ctx, n.session(sessionID), threshold, parties)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.store.SaveShare(share); err != nil {
+		return nil, err
+	}
+	if _, err := collect[keygenConfirm](ctx, n, n.session(sessionID), roundKeygenDone, parties); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// Sign 发起对 32 字节 hash 的门限签名，返回 r || s || v。signers 为空时选本方和分片中排在前面的参与方
+func (n *Node) Sign(ctx context.Context, keyID string, signers []int, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errors.New("hash must be 32 bytes")
+	}
+	share, err := n.store.LoadShare(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		signers = defaultSigners(n.id, share.Parties, share.Threshold)
+	}
+	if err := validateParties(n.id, signers); err != nil {
+		return nil, err
+	}
+	if len(signers) != share.Threshold {
+		return nil, fmt.Errorf("need exactly %d signers, got %d", share.Threshold, len(signers))
+	}
+	for _, signer := range signers {
+		if !containsParty(share.Parties, signer) {
+			return nil, fmt.Errorf("party %d does not hold a share of %s", signer, keyID)
+		}
+	}
+	sessionID, err := n.start(ctx, startRequest{Protocol: protocolSign, Parties: signers, KeyID: keyID, Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	defer n.closeSession(sessionID)
+	return n.runSign(ctx, n.session(sessionID), share, signers, hash)
+}
+
+// start 生成会话 id 并通知其它参与方
+func (n *Node) start(ctx context.Context, req startRequest) (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	sessionID := hex.EncodeToString(id[:])
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	for _, party := range req.Parties {
+		if party == n.id {
+			continue
+		}
+		msg := &Message{SessionID: sessionID, Round: roundStart, From: n.id, To: party, Payload: payload}
+		if err := n.transport.Send(ctx, msg); err != nil {
+			return "", fmt.Errorf("notify party %d: %w", party, err)
+		}
+	}
+	return sessionID, nil
+}
+
+func (n *Node) sendTo(ctx context.Context, sess *session, round string, to int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return n.transport.Send(ctx, &Message{SessionID: sess.id, Round: round, From: n.id, To: to, Payload: data})
+}
+
+// broadcast 逐个发给会话中的其它参与方，而不是整个网络
+func (n *Node) broadcast(ctx context.Context, sess *session, round string, parties []int, payload interface{}) error {
+	for _, party := range parties {
+		if party == n.id {
+			continue
+		}
+		if err := n.sendTo(ctx, sess, round, party, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect 等待 parties 中除本方外每个参与方在 round 的消息，并解码为 T
+func collect[T any](ctx context.Context, n *Node, sess *session, round string, parties []int) (map[int]*T, error) {
+	raw, err := sess.wait(ctx, round, otherParties(n.id, parties))
+	if err != nil {
+		return nil, fmt.Errorf("round %s: %w", round, err)
+	}
+	result := make(map[int]*T, len(raw))
+	for from, data := range raw {
+		value := new(T)
+		if err := json.Unmarshal(data, value); err != nil {
+			return nil, fmt.Errorf("round %s: decode message from party %d: %w", round, from, err)
+		}
+		result[from] = value
+	}
+	return result, nil
+}
+
+func (n *Node) session(id string) *session {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if sess, ok := n.sessions[id]; ok {
+		return sess
+	}
+	now := time.Now()
+	for key, sess := range n.sessions {
+		if now.Sub(sess.created) > sessionTTL {
+			delete(n.sessions, key)
+		}
+	}
+	sess := &session{
+		id:      id,
+		created: now,
+		notify:  make(chan struct{}),
+		inbox:   make(map[string]map[int][]byte),
+	}
+	n.sessions[id] = sess
+	return sess
+}
+
+func (n *Node) closeSession(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.sessions, id)
+}
+
+// session 缓存一个会话收到的消息，其它参与方可能比本方更早进入下一轮
+type session struct {
+	id      string
+	created time.Time
+
+	mu     sync.Mutex
+	notify chan struct{}
+	inbox  map[string]map[int][]byte
+}
+
+func (s *session) deliver(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	round, ok := s.inbox[msg.Round]
+	if !ok {
+		round = make(map[int][]byte)
+		s.inbox[msg.Round] = round
+	}
+	if _, dup := round[msg.From]; dup {
+		return
+	}
+	round[msg.From] = msg.Payload
+	close(s.notify)
+	s.notify = make(chan struct{})
+}
+
+func (s *session) wait(ctx context.Context, round string, from []int) (map[int][]byte, error) {
+	for {
+		s.mu.Lock()
+		received := s.inbox[round]
+		complete := true
+		for _, party := range from {
+			if _, ok := received[party]; !ok {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			result := make(map[int][]byte, len(from))
+			for _, party := range from {
+				result[party] = received[party]
+			}
+			s.mu.Unlock()
+			return result, nil
+		}
+		notify := s.notify
+		s.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func validateParties(self int, parties []int) error {
+	seen := make(map[int]bool)
+	for _, party := range parties {
+		if party <= 0 {
+			return fmt.Errorf("invalid party id %d", party)
+		}
+		if seen[party] {
+			return fmt.Errorf("duplicate party id %d", party)
+		}
+		seen[party] = true
+	}
+	if !seen[self] {
+		return fmt.Errorf("party %d is not in the party list", self)
+	}
+	return nil
+}
+
+func defaultSigners(self int, parties []int, threshold int) []int {
+	signers := []int{self}
+	for _, party := range parties {
+		if len(signers) == threshold {
+			break
+		}
+		if party != self {
+			signers = append(signers, party)
+		}
+	}
+	return signers
+}
+
+func otherParties(self int, parties []int) []int {
+	others := make([]int, 0, len(parties))
+	for _, party := range parties {
+		if party != self {
+			others = append(others, party)
+		}
+	}
+	return others
+}
+
+func containsParty(parties []int, party int) bool {
+	for _, p := range parties {
+		if p == party {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package tss
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+var one = big.NewInt(1)
+
+// PaillierPublicKey 取 g = N + 1 的 Paillier 公钥，签名时用于 MtA 的同态乘法
+type PaillierPublicKey struct {
+	N *big.Int `json:"n"`
+}
+
+type PaillierPrivateKey struct {
+	PaillierPublicKey
+	Lambda *big.Int `json:"lambda"`
+	Mu     *big.Int `json:"mu"`
+}
+
+func GeneratePaillierKey(bits int) (*PaillierPrivateKey, error) {
+	for {
+		p, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		n := new(big.Int).Mul(p, q)
+		// λ = φ(N) 在 g = N + 1 时同样可用
+		lambda := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		mu := new(big.Int).ModInverse(lambda, n)
+		if mu == nil {
+			continue
+		}
+		return &PaillierPrivateKey{PaillierPublicKey: PaillierPublicKey{N: n}, Lambda: lambda, Mu: mu}, nil
+	}
+}
+
+func (pk *PaillierPublicKey) nSquare() *big.Int {
+	return new(big.Int).Mul(pk.N, pk.N)
+}
+
+// Encrypt c = (1 + N)^m * r^N mod N^2
+func (pk *PaillierPublicKey) Encrypt(m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pk.N) >= 0 {
+		return nil, errors.New("paillier plaintext out of range")
+	}
+	r, err := randomUnit(pk.N)
+	if err != nil {
+		return nil, err
+	}
+	n2 := pk.nSquare()
+	gm := new(big.Int).Mul(m, pk.N)
+	gm.Add(gm, one)
+	gm.Mod(gm, n2)
+	rn := new(big.Int).Exp(r, pk.N, n2)
+	return gm.Mul(gm, rn).Mod(gm, n2), nil
+}
+
+// Add 同态加法：Dec(Add(c1, c2)) = m1 + m2
+func (pk *PaillierPublicKey) Add(c1, c2 *big.Int) *big.Int {
+	n2 := pk.nSquare()
+	return new(big.Int).Mod(new(big.Int).Mul(c1, c2), n2)
+}
+
+// Mul 同态数乘：Dec(Mul(c, k)) = k * m
+func (pk *PaillierPublicKey) Mul(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, pk.nSquare())
+}
+
+func (sk *PaillierPrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	n2 := sk.nSquare()
+	if c.Sign() <= 0 || c.Cmp(n2) >= 0 {
+		return nil, errors.New("paillier ciphertext out of range")
+	}
+	// L(c^λ mod N^2) * μ mod N，其中 L(x) = (x - 1) / N
+	u := new(big.Int).Exp(c, sk.Lambda, n2)
+	u.Sub(u, one)
+	u.Div(u, sk.N)
+	u.Mul(u, sk.Mu)
+	return u.Mod(u, sk.N), nil
+}
+
+func randomUnit(n *big.Int) (*big.Int, error) {
+	for {
+		r, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, n).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package tss
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	roundSignCommit = "sign/commit"
+	roundSignMta    = "sign/mta"
+	roundSignDelta  = "sign/delta"
+	roundSignS      = "sign/s"
+
+	// mtaMaskBits MtA 中加性掩码比 k·γ 的上界多出的统计安全位数
+	mtaMaskBits = 128
+)
+
+// signCommit 第一轮广播：Γ_i = γ_i·G 和用本方 Paillier 公钥加密的 k_i
+type signCommit struct {
+	Gamma Point    `json:"gamma"`
+	EncK  *big.Int `json:"enc_k"`
+}
+
+// signMta 第二轮点对点发送：接收方 k 与本方 γ、w 的同态乘积加上掩码
+type signMta struct {
+	CGamma *big.Int `json:"c_gamma"`
+	CW     *big.Int `json:"c_w"`
+}
+
+type signDelta struct {
+	Delta *big.Int `json:"delta"`
+}
+
+type signS struct {
+	S *big.Int `json:"s"`
+}
+
+// runSign 对 hash 运行签名协议。设 k = Σk_i，γ = Σγ_i，x = Σw_i：
+// R = (kγ)^-1·Σ Γ_i = k^-1·G，s = Σ (m·k_i + r·σ_i) = k(m + r·x)，即以 k^-1 为随机数的标准 ECDSA 签名
+func (n *Node) runSign(ctx context.Context, sess *session, share *KeyShare, signers []int, hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errors.New("hash must be 32 bytes")
+	}
+	if err := validateParties(n.id, signers); err != nil {
+		return nil, err
+	}
+	if len(signers) != share.Threshold {
+		return nil, fmt.Errorf("need exactly %d signers, got %d", share.Threshold, len(signers))
+	}
+	q := curve.N
+	w := new(big.Int).Mul(lagrangeAtZero(n.id, signers), share.Secret)
+	w.Mod(w, q)
+	k, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	gamma, err := randomScalar()
+	if err != nil {
+		return nil, err
+	}
+	encK, err := share.Paillier.Encrypt(k)
+	if err != nil {
+		return nil, err
+	}
+	commit := &signCommit{Gamma: scalarBaseMult(gamma), EncK: encK}
+	if err := n.broadcast(ctx, sess, roundSignCommit, signers, commit); err != nil {
+		return nil, err
+	}
+	commits, err := collect[signCommit](ctx, n, sess, roundSignCommit, signers)
+	if err != nil {
+		return nil, err
+	}
+
+	// MtA：对每个其它签名方 j，用 j 的公钥同态计算 k_j·γ_i + β' 和 k_j·w_i + ν'，本方保留 -β' 和 -ν'
+	delta := new(big.Int).Mul(k, gamma)
+	sigma := new(big.Int).Mul(k, w)
+	maskBound := new(big.Int).Lsh(new(big.Int).Mul(q, q), mtaMaskBits)
+	for j, c := range commits {
+		pk, ok := share.PaillierKeys[j]
+		if !ok {
+			return nil, fmt.Errorf("no paillier key for party %d", j)
+		}
+		if !isOnCurve(c.Gamma) || c.EncK == nil {
+			return nil, fmt.Errorf("invalid commitment from party %d", j)
+		}
+		if pk.N.BitLen() <= maskBound.BitLen()+1 {
+			return nil, fmt.Errorf("paillier modulus of party %d is too small", j)
+		}
+		cGamma, beta, err := mtaResponse(pk, c.EncK, gamma, maskBound)
+		if err != nil {
+			return nil, err
+		}
+		cW, nu, err := mtaResponse(pk, c.EncK, w, maskBound)
+		if err != nil {
+			return nil, err
+		}
+		delta.Add(delta, beta)
+		sigma.Add(sigma, nu)
+		if err := n.sendTo(ctx, sess, roundSignMta, j, &signMta{CGamma: cGamma, CW: cW}); err != nil {
+			return nil, err
+		}
+	}
+	mtas, err := collect[signMta](ctx, n, sess, roundSignMta, signers)
+	if err != nil {
+		return nil, err
+	}
+	for j, m := range mtas {
+		alpha, err := share.Paillier.Decrypt(m.CGamma)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt mta from party %d: %w", j, err)
+		}
+		mu, err := share.Paillier.Decrypt(m.CW)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt mta from party %d: %w", j, err)
+		}
+		delta.Add(delta, alpha)
+		sigma.Add(sigma, mu)
+	}
+	delta.Mod(delta, q)
+	sigma.Mod(sigma, q)
+
+	if err := n.broadcast(ctx, sess, roundSignDelta, signers, &signDelta{Delta: delta}); err != nil {
+		return nil, err
+	}
+	deltas, err := collect[signDelta](ctx, n, sess, roundSignDelta, signers)
+	if err != nil {
+		return nil, err
+	}
+	totalDelta := new(big.Int).Set(delta)
+	gammaSum := commit.Gamma
+	for j := range deltas {
+		if deltas[j].Delta == nil {
+			return nil, fmt.Errorf("invalid delta from party %d", j)
+		}
+		totalDelta.Add(totalDelta, deltas[j].Delta)
+		gammaSum = pointAdd(gammaSum, commits[j].Gamma)
+	}
+	totalDelta.Mod(totalDelta, q)
+	deltaInv := new(big.Int).ModInverse(totalDelta, q)
+	if deltaInv == nil {
+		return nil, errors.New("delta is not invertible")
+	}
+	R := scalarMult(gammaSum, deltaInv)
+	if R.X == nil {
+		return nil, errors.New("nonce point is the point at infinity")
+	}
+	r := new(big.Int).Mod(R.X, q)
+	if r.Sign() == 0 {
+		return nil, errors.New("signature r is zero")
+	}
+
+	m := new(big.Int).SetBytes(hash)
+	si := new(big.Int).Mul(m, k)
+	si.Add(si, new(big.Int).Mul(r, sigma))
+	si.Mod(si, q)
+	if err := n.broadcast(ctx, sess, roundSignS, signers, &signS{S: si}); err != nil {
+		return nil, err
+	}
+	ss, err := collect[signS](ctx, n, sess, roundSignS, signers)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Set(si)
+	for j := range ss {
+		if ss[j].S == nil {
+			return nil, fmt.Errorf("invalid s from party %d", j)
+		}
+		s.Add(s, ss[j].S)
+	}
+	s.Mod(s, q)
+	if s.Sign() == 0 {
+		return nil, errors.New("signature s is zero")
+	}
+
+	// 规范为 low-S，并按 R.y 的奇偶得到恢复 id，格式与 crypto.Sign 一致
+	recID := byte(R.Y.Bit(0))
+	if s.Cmp(new(big.Int).Rsh(q, 1)) > 0 {
+		s.Sub(q, s)
+		recID ^= 1
+	}
+	sig := make([]byte, 65)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:64])
+	sig[64] = recID
+
+	recovered, err := crypto.Ecrecover(hash, sig)
+	if err != nil || !bytes.Equal(recovered, uncompressPoint(share.PublicKey)) {
+		return nil, errors.New("threshold signature does not verify against the public key")
+	}
+	return sig, nil
+}
+
+// mtaResponse 返回 Enc(k_j·x + β') 和本方的加法分享 -β' mod q
+func mtaResponse(pk *PaillierPublicKey, encK *big.Int, x *big.Int, maskBound *big.Int) (*big.Int, *big.Int, error) {
+	if encK.Sign() <= 0 || encK.Cmp(pk.nSquare()) >= 0 {
+		return nil, nil, errors.New("paillier ciphertext out of range")
+	}
+	betaPrime, err := rand.Int(rand.Reader, maskBound)
+	if err != nil {
+		return nil, nil, err
+	}
+	encBeta, err := pk.Encrypt(betaPrime)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := pk.Add(pk.Mul(encK, x), encBeta)
+	share := new(big.Int).Neg(betaPrime)
+	return c, share.Mod(share, curve.N), nil
+}
@@ -0,0 +1,68 @@
+package tss
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+// Signer 以门限协议实现 ssm.Signer。CreateKeyPair 返回的“私钥”是密钥 id（KeyIDPrefix + 压缩公钥），
+// 私钥分片保存在各参与方自己的 ShareStore 中；SignMessage 收到不带前缀的普通私钥时交给 fallback 处理，
+// 这样开启门限模式前生成或导入的密钥仍然可以签名
+type Signer struct {
+	node      *Node
+	threshold int
+	parties   []int
+	timeout   time.Duration
+	fallback  ssm.Signer
+}
+
+var _ ssm.Signer = (*Signer)(nil)
+
+func NewSigner(node *Node, threshold int, parties []int, fallback ssm.Signer) *Signer {
+	return &Signer{
+		node:      node,
+		threshold: threshold,
+		parties:   parties,
+		timeout:   DefaultTimeout,
+		fallback:  fallback,
+	}
+}
+
+func (s *Signer) CreateKeyPair() (string, string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	share, err := s.node.Keygen(ctx, s.threshold, s.parties)
+	if err != nil {
+		log.Error("tss keygen fail", "err", err)
+		return ssm.EmptyHexString, ssm.EmptyHexString, ssm.EmptyHexString, err
+	}
+	return share.KeyID, hex.EncodeToString(uncompressPoint(share.PublicKey)), hex.EncodeToString(compressPoint(share.PublicKey)), nil
+}
+
+func (s *Signer) SignMessage(priKey string, txMsg string) (string, error) {
+	if !strings.HasPrefix(priKey, KeyIDPrefix) {
+		return s.fallback.SignMessage(priKey, txMsg)
+	}
+	hash := common.HexToHash(txMsg)
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	signature, err := s.node.Sign(ctx, priKey, nil, hash[:])
+	if err != nil {
+		log.Error("tss sign fail", "key", priKey, "err", err)
+		return ssm.EmptyHexString, err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+func (s *Signer) VerifySignature(pubKey string, msgHash string, signature string) (bool, error) {
+	return s.fallback.VerifySignature(pubKey, msgHash, signature)
+}
@@ -0,0 +1,138 @@
+package tss
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/0xshin-chan/wallet-sign/frost"
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+)
+
+var ErrShareNotFound = errors.New("key share not found")
+
+// Point 曲线上的点（仿射坐标）
+type Point struct {
+	X *big.Int `json:"x"`
+	Y *big.Int `json:"y"`
+}
+
+// KeyShare 一个参与方持有的私钥分片，任意 Threshold 个参与方可以联合签名
+type KeyShare struct {
+	KeyID     string `json:"key_id"`
+	PartyID   int    `json:"party_id"`
+	Threshold int    `json:"threshold"`
+	Parties   []int  `json:"parties"`
+	// Secret 私钥多项式在 PartyID 处的取值
+	Secret    *big.Int `json:"secret"`
+	PublicKey Point    `json:"public_key"`
+	// Paillier 本方的 Paillier 私钥，PaillierKeys 为所有参与方的公钥
+	Paillier     *PaillierPrivateKey        `json:"paillier"`
+	PaillierKeys map[int]*PaillierPublicKey `json:"paillier_keys"`
+}
+
+// ShareStore 保存本参与方的私钥分片，FROST 分片与 ECDSA 分片的密钥 id 前缀不同，互不冲突
+type ShareStore interface {
+	SaveShare(share *KeyShare) error
+	LoadShare(keyID string) (*KeyShare, error)
+	SaveFrostShare(keyID string, share *frost.KeyShare) error
+	LoadFrostShare(keyID string) (*frost.KeyShare, error)
+}
+
+type MemoryShareStore struct {
+	mu          sync.RWMutex
+	shares      map[string]*KeyShare
+	frostShares map[string]*frost.KeyShare
+}
+
+func NewMemoryShareStore() *MemoryShareStore {
+	return &MemoryShareStore{shares: make(map[string]*KeyShare), frostShares: make(map[string]*frost.KeyShare)}
+}
+
+func (s *MemoryShareStore) SaveShare(share *KeyShare) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shares[share.KeyID] = share
+	return nil
+}
+
+func (s *MemoryShareStore) LoadShare(keyID string) (*KeyShare, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	share, ok := s.shares[keyID]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+	return share, nil
+}
+
+func (s *MemoryShareStore) SaveFrostShare(keyID string, share *frost.KeyShare) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frostShares[keyID] = share
+	return nil
+}
+
+func (s *MemoryShareStore) LoadFrostShare(keyID string) (*frost.KeyShare, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	share, ok := s.frostShares[keyID]
+	if !ok {
+		return nil, ErrShareNotFound
+	}
+	return share, nil
+}
+
+// levelShareStore 把分片按 key store 的静态加密规则保存在 LevelDB 中
+type levelShareStore struct {
+	db    *leveldb.Keys
+	party int
+}
+
+func NewLevelShareStore(db *leveldb.Keys, party int) ShareStore {
+	return &levelShareStore{db: db, party: party}
+}
+
+func (s *levelShareStore) SaveShare(share *KeyShare) error {
+	data, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+	return s.db.StoreTssShare(s.party, share.KeyID, data)
+}
+
+func (s *levelShareStore) LoadShare(keyID string) (*KeyShare, error) {
+	var share KeyShare
+	if err := s.load(keyID, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (s *levelShareStore) SaveFrostShare(keyID string, share *frost.KeyShare) error {
+	data, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+	return s.db.StoreTssShare(s.party, keyID, data)
+}
+
+func (s *levelShareStore) LoadFrostShare(keyID string) (*frost.KeyShare, error) {
+	var share frost.KeyShare
+	if err := s.load(keyID, &share); err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (s *levelShareStore) load(keyID string, share interface{}) error {
+	data, err := s.db.GetTssShare(s.party, keyID)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrKeyNotFound) {
+			return ErrShareNotFound
+		}
+		return err
+	}
+	return json.Unmarshal(data, share)
+}
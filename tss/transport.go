@@ -0,0 +1,90 @@
+package tss
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Message 参与方之间的协议消息，To 为 0 时广播给其它所有参与方
+type Message struct {
+	SessionID string `json:"session_id"`
+	Round     string `json:"round"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Payload   []byte `json:"payload"`
+}
+
+// Transport 参与方之间的消息通道，实现方需要保证消息的来源可信（例如 mTLS），协议本身不做认证
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+	Receive(ctx context.Context) (*Message, error)
+}
+
+const memoryInboxSize = 1024
+
+// MemoryNetwork 进程内的消息网络，所有参与方在同一个进程中运行，主要用于测试
+type MemoryNetwork struct {
+	mu      sync.RWMutex
+	inboxes map[int]chan *Message
+}
+
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{inboxes: make(map[int]chan *Message)}
+}
+
+// Join 为参与方 id 创建一个接入网络的 Transport
+func (n *MemoryNetwork) Join(id int) Transport {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	inbox, ok := n.inboxes[id]
+	if !ok {
+		inbox = make(chan *Message, memoryInboxSize)
+		n.inboxes[id] = inbox
+	}
+	return &memoryTransport{id: id, network: n, inbox: inbox}
+}
+
+type memoryTransport struct {
+	id      int
+	network *MemoryNetwork
+	inbox   chan *Message
+}
+
+func (t *memoryTransport) Send(ctx context.Context, msg *Message) error {
+	t.network.mu.RLock()
+	var targets []chan *Message
+	if msg.To == 0 {
+		for id, inbox := range t.network.inboxes {
+			if id != t.id {
+				targets = append(targets, inbox)
+			}
+		}
+	} else if inbox, ok := t.network.inboxes[msg.To]; ok {
+		targets = append(targets, inbox)
+	}
+	t.network.mu.RUnlock()
+	if len(targets) == 0 {
+		return fmt.Errorf("party %d is not in the network", msg.To)
+	}
+
+	for _, inbox := range targets {
+		copied := *msg
+		copied.From = t.id
+		select {
+		case inbox <- &copied:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (t *memoryTransport) Receive(ctx context.Context) (*Message, error) {
+	select {
+	case msg := <-t.inbox:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
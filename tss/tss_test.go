@@ -0,0 +1,125 @@
+package tss
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/0xshin-chan/wallet-sign/frost"
+	"github.com/0xshin-chan/wallet-sign/ssm"
+)
+
+// 测试使用较短的 Paillier 模数以缩短素数生成时间
+const testPaillierBits = 1024
+
+func startNodes(t *testing.T, parties []int) map[int]*Node {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	network := NewMemoryNetwork()
+	nodes := make(map[int]*Node)
+	for _, id := range parties {
+		node := NewNode(id, network.Join(id), NewMemoryShareStore(), testPaillierBits)
+		nodes[id] = node
+		go node.Run(ctx)
+	}
+	return nodes
+}
+
+func TestThresholdSign(t *testing.T) {
+	parties := []int{1, 2, 3}
+	nodes := startNodes(t, parties)
+	ctx := context.Background()
+
+	share, err := nodes[1].Keygen(ctx, 2, parties)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := uncompressPoint(share.PublicKey)
+	hash := crypto.Keccak256([]byte("threshold"))
+
+	// 任意两方都可以签名，且签名可以恢复出同一个公钥
+	for _, signers := range [][]int{{1, 2}, {1, 3}, {3, 2}} {
+		sig, err := nodes[signers[0]].Sign(ctx, share.KeyID, signers, hash)
+		if err != nil {
+			t.Fatalf("signers %v: %v", signers, err)
+		}
+		recovered, err := crypto.Ecrecover(hash, sig)
+		if err != nil || hex.EncodeToString(recovered) != hex.EncodeToString(pubKey) {
+			t.Fatalf("signers %v: recovered %x, err %v", signers, recovered, err)
+		}
+	}
+	if _, err := nodes[1].Sign(ctx, share.KeyID, []int{1}, hash); err == nil {
+		t.Fatal("expected signing below the threshold to fail")
+	}
+}
+
+func TestSignerInterface(t *testing.T) {
+	parties := []int{1, 2, 3}
+	nodes := startNodes(t, parties)
+	signer := NewSigner(nodes[1], 2, parties, &ssm.ECDSASigner{})
+
+	keyID, pubKey, _, err := signer.CreateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgHash := hex.EncodeToString(crypto.Keccak256([]byte("message")))
+	sig, err := signer.SignMessage(keyID, msgHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, err := signer.VerifySignature(pubKey, msgHash, sig)
+	if err != nil || !ok {
+		t.Fatalf("verify = %v, err %v", ok, err)
+	}
+
+	// 不带前缀的普通私钥走本地签名
+	localKey, localPub, _, _ := new(ssm.ECDSASigner).CreateKeyPair()
+	sig, err = signer.SignMessage(localKey, msgHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := signer.VerifySignature(localPub, msgHash, sig); !ok {
+		t.Fatal("fallback signature does not verify")
+	}
+}
+
+func TestFrostSigner(t *testing.T) {
+	parties := []int{1, 2, 3}
+	nodes := startNodes(t, parties)
+	msg := hex.EncodeToString([]byte("solana message"))
+
+	signer := NewFrostSigner(nodes[2], frost.SuiteEd25519, 2, parties, &ssm.EdDSASigner{})
+	keyID, pubKey, _, err := signer.CreateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 刷新前后签名都能用同一个公钥校验
+	for i := 0; i < 2; i++ {
+		sig, err := signer.SignMessage(keyID, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := signer.VerifySignature(pubKey, msg, sig); !ok {
+			t.Fatalf("signature %s does not verify", sig)
+		}
+		if err := signer.Refresh(keyID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	taproot := NewFrostSigner(nodes[1], frost.SuiteSecp256k1TR, 2, parties, &ssm.ECDSASigner{})
+	keyID, pubKey, _, err = taproot.CreateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgHash := hex.EncodeToString(crypto.Keccak256([]byte("sighash")))
+	sig, err := taproot.SignMessage(keyID, msgHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := taproot.VerifySignature(pubKey, msgHash, sig); err != nil || !ok {
+		t.Fatalf("verify = %v, err %v", ok, err)
+	}
+}
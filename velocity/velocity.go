@@ -0,0 +1,218 @@
+// Package velocity 按滚动时间窗口限制签名次数和转出金额。
+//
+// 每个限额按签名公钥（热钱包）或调用方分别计数，计数事件保存在 LevelDB 中，重启后继续生效。
+// 签名前先预占额度，签名失败时撤销，保证并发请求不会同时越过限额
+package velocity
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+)
+
+const (
+	// ScopeKey 每个签名公钥分别计数
+	ScopeKey = "key"
+	// ScopeConsumer 每个调用方分别计数
+	ScopeConsumer = "consumer"
+
+	// ReasonLimitExceeded 超过限额时返回的拒绝原因码
+	ReasonLimitExceeded = "velocity_limit_exceeded"
+)
+
+var ErrLimitExceeded = errors.New("velocity limit exceeded")
+
+// Limit 一条限额，MaxCount 限制窗口内的签名次数，MaxAmount 限制窗口内 Asset 的转出合计，为 0 或 nil 表示不限制
+type Limit struct {
+	Name  string
+	Scope string
+	// 为空表示所有链、所有调用方
+	Chain    string
+	Consumer string
+	// policy.AssetNative 或代币合约地址
+	Asset     string
+	MaxAmount *big.Int
+	MaxCount  int
+	Window    time.Duration
+}
+
+func (l *Limit) validate() error {
+	if l.Name == "" {
+		return errors.New("velocity limit name is empty")
+	}
+	if l.Scope != ScopeKey && l.Scope != ScopeConsumer {
+		return fmt.Errorf("velocity limit %s: unsupported scope %q", l.Name, l.Scope)
+	}
+	if l.Window <= 0 {
+		return fmt.Errorf("velocity limit %s: window must be positive", l.Name)
+	}
+	if l.MaxCount <= 0 && l.MaxAmount == nil {
+		return fmt.Errorf("velocity limit %s: neither max_count nor max_amount is set", l.Name)
+	}
+	if l.MaxAmount != nil && l.Asset == "" {
+		return fmt.Errorf("velocity limit %s: max_amount needs an asset", l.Name)
+	}
+	return nil
+}
+
+func (l *Limit) applies(chain string, consumerName string) bool {
+	return (l.Chain == "" || l.Chain == chain) && (l.Consumer == "" || l.Consumer == consumerName)
+}
+
+func (l *Limit) subject(chain string, publicKey string, consumerName string) string {
+	if l.Scope == ScopeKey {
+		return chain + ":" + publicKey
+	}
+	return consumerName
+}
+
+// amount 返回交易中该限额资产的转出合计
+func (l *Limit) amount(intents []policy.Intent) *big.Int {
+	total := new(big.Int)
+	if l.MaxAmount == nil {
+		return total
+	}
+	asset := policy.NormalizeAddress(l.Asset)
+	for _, intent := range intents {
+		if intent.Amount != nil && policy.NormalizeAddress(intent.Asset) == asset {
+			total.Add(total, intent.Amount)
+		}
+	}
+	return total
+}
+
+// Signing 一次待签名的请求，原始消息签名没有 Intents，只计次数
+type Signing struct {
+	Chain     string
+	PublicKey string
+	Consumer  string
+	Intents   []policy.Intent
+}
+
+type Limiter struct {
+	db     *leveldb.Keys
+	limits []Limit
+	// 保证检查和预占之间没有其他请求写入
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+func NewLimiter(db *leveldb.Keys, limits []Limit) (*Limiter, error) {
+	names := make(map[string]bool, len(limits))
+	for i := range limits {
+		if err := limits[i].validate(); err != nil {
+			return nil, err
+		}
+		if names[limits[i].Name] {
+			return nil, fmt.Errorf("duplicate velocity limit %s", limits[i].Name)
+		}
+		names[limits[i].Name] = true
+	}
+	return &Limiter{db: db, limits: limits, now: time.Now}, nil
+}
+
+// Reservation 预占的额度，签名失败时调用 Cancel 归还
+type Reservation struct {
+	db   *leveldb.Keys
+	keys [][]byte
+}
+
+// Cancel 撤销预占，nil 可以安全调用
+func (r *Reservation) Cancel() error {
+	if r == nil || len(r.keys) == 0 {
+		return nil
+	}
+	return r.db.DeleteVelocityEvents(r.keys)
+}
+
+// Reserve 检查所有适用的限额，都未超过时一次性写入计数事件
+func (l *Limiter) Reserve(s Signing) (*Reservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var events []leveldb.VelocityEvent
+	expired := make(map[string]time.Time)
+	for i := range l.limits {
+		limit := &l.limits[i]
+		if !limit.applies(s.Chain, s.Consumer) {
+			continue
+		}
+		amount := limit.amount(s.Intents)
+		if limit.MaxCount <= 0 && amount.Sign() == 0 {
+			continue
+		}
+		counter := limit.Name + "/" + limit.subject(s.Chain, s.PublicKey, s.Consumer)
+		since := now.Add(-limit.Window)
+		count, used, err := l.db.VelocityUsage(counter, since)
+		if err != nil {
+			return nil, err
+		}
+		if limit.MaxCount > 0 && count+1 > limit.MaxCount {
+			return nil, fmt.Errorf("%w: %s allows %d signatures per %s", ErrLimitExceeded, limit.Name, limit.MaxCount, limit.Window)
+		}
+		if limit.MaxAmount != nil && new(big.Int).Add(used, amount).Cmp(limit.MaxAmount) > 0 {
+			return nil, fmt.Errorf("%w: %s allows %s %s per %s, used %s, requested %s",
+				ErrLimitExceeded, limit.Name, limit.MaxAmount, limit.Asset, limit.Window, used, amount)
+		}
+		event := leveldb.VelocityEvent{Counter: counter, At: now}
+		if amount.Sign() > 0 {
+			event.Amount = amount
+		}
+		events = append(events, event)
+		expired[counter] = since
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	keys, err := l.db.AddVelocityEvents(events, expired)
+	if err != nil {
+		return nil, err
+	}
+	return &Reservation{db: l.db, keys: keys}, nil
+}
+
+// Usage 一条限额当前窗口内的用量
+type Usage struct {
+	Limit       *Limit
+	Subject     string
+	UsedCount   int
+	UsedAmount  *big.Int
+	RemainCount int
+	// MaxAmount 为 nil 时为 nil
+	RemainAmount *big.Int
+}
+
+// Usage 返回调用方可见的限额用量，publicKey 为空时不返回按公钥计数的限额
+func (l *Limiter) Usage(chain string, publicKey string, consumerName string) ([]Usage, error) {
+	now := l.now()
+	var usages []Usage
+	for i := range l.limits {
+		limit := &l.limits[i]
+		if !limit.applies(chain, consumerName) || (limit.Scope == ScopeKey && publicKey == "") {
+			continue
+		}
+		subject := limit.subject(chain, publicKey, consumerName)
+		count, used, err := l.db.VelocityUsage(limit.Name+"/"+subject, now.Add(-limit.Window))
+		if err != nil {
+			return nil, err
+		}
+		usage := Usage{Limit: limit, Subject: subject, UsedCount: count, UsedAmount: used}
+		if limit.MaxCount > 0 {
+			usage.RemainCount = max(limit.MaxCount-count, 0)
+		}
+		if limit.MaxAmount != nil {
+			usage.RemainAmount = new(big.Int).Sub(limit.MaxAmount, used)
+			if usage.RemainAmount.Sign() < 0 {
+				usage.RemainAmount.SetInt64(0)
+			}
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
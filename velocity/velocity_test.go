@@ -0,0 +1,100 @@
+package velocity
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xshin-chan/wallet-sign/leveldb"
+	"github.com/0xshin-chan/wallet-sign/policy"
+)
+
+func TestLimiter(t *testing.T) {
+	dir := t.TempDir()
+	db, err := leveldb.NewKeyStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usdt := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	limits := []Limit{
+		{Name: "hot-wallet-usdt", Scope: ScopeKey, Chain: "Ethereum", Asset: usdt, MaxAmount: big.NewInt(1000), Window: time.Hour},
+		{Name: "consumer-rate", Scope: ScopeConsumer, MaxCount: 3, Window: time.Minute},
+	}
+	limiter, err := NewLimiter(db, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	limiter.now = func() time.Time { return now }
+	spend := func(publicKey string, consumerName string, amount int64) Signing {
+		return Signing{Chain: "Ethereum", PublicKey: publicKey, Consumer: consumerName, Intents: []policy.Intent{
+			// 地址大小写不影响资产匹配
+			{Asset: "0xdac17f958d2ee523a2206206994597c13d831ec7", Amount: big.NewInt(amount)},
+		}}
+	}
+
+	if _, err := limiter.Reserve(spend("k1", "exchange", 600)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.Reserve(spend("k1", "payroll", 500)); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("over amount: err = %v", err)
+	}
+	// 另一个热钱包有自己的额度
+	reservation, err := limiter.Reserve(spend("k2", "payroll", 500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 签名失败后归还额度和次数
+	if err := reservation.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.Reserve(spend("k2", "payroll", 1000)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Reserve(Signing{Chain: "Solana", PublicKey: "s", Consumer: "exchange"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := limiter.Reserve(Signing{Chain: "Solana", PublicKey: "s", Consumer: "exchange"}); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("over count: err = %v", err)
+	}
+
+	// 重启后计数仍然有效
+	db.Close()
+	if db, err = leveldb.NewKeyStore(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	limiter, _ = NewLimiter(db, limits)
+	limiter.now = func() time.Time { return now }
+	usages, err := limiter.Usage("Ethereum", "k1", "exchange")
+	if err != nil || len(usages) != 2 {
+		t.Fatalf("usage = %v, err %v", usages, err)
+	}
+	if u := usages[0]; u.UsedAmount.Int64() != 600 || u.RemainAmount.Int64() != 400 {
+		t.Fatalf("amount usage = %+v", u)
+	}
+	if u := usages[1]; u.UsedCount != 3 || u.RemainCount != 0 {
+		t.Fatalf("count usage = %+v", u)
+	}
+
+	// 窗口滑过后额度恢复
+	now = now.Add(time.Minute + time.Second)
+	if _, err := limiter.Reserve(Signing{Chain: "Solana", PublicKey: "s", Consumer: "exchange"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.Reserve(spend("k1", "other", 500)); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("hourly amount should still be used: err = %v", err)
+	}
+	now = now.Add(time.Hour)
+	if _, err := limiter.Reserve(spend("k1", "other", 1000)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewLimiter(db, []Limit{{Name: "bad", Scope: ScopeKey, MaxAmount: big.NewInt(1), Window: time.Hour}}); err == nil {
+		t.Fatal("expected max_amount without an asset to be rejected")
+	}
+}